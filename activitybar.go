@@ -6,6 +6,8 @@ import (
 	"time"
 
 	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -24,6 +26,9 @@ type ActivityBar struct {
 	activeColor   string
 	hintColor     string
 	progressColor string
+	keys          *KeyMap
+	clock         *FrameClock // Shared frame clock; nil runs a timer of its own
+	lastFrame     time.Time
 }
 
 // ActivityBarOption configures an ActivityBar.
@@ -43,9 +48,27 @@ func WithActivityBarTheme(theme string) ActivityBarOption {
 	}
 }
 
+// WithActivityBarKeyMap replaces the default keymap
+func WithActivityBarKeyMap(km *KeyMap) ActivityBarOption {
+	return func(a *ActivityBar) {
+		a.keys = km
+	}
+}
+
+// DefaultActivityBarKeyMap returns the default ActivityBar bindings for the
+// action "cancel"
+func DefaultActivityBarKeyMap() *KeyMap {
+	km := NewKeyMap("ActivityBar")
+	km.Bind("cancel", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "interrupt")))
+	return km
+}
+
 // activityBarTickMsg is sent periodically to update the spinner and timer
 type activityBarTickMsg time.Time
 
+// Broadcast delivers ticks to every activity bar in an Application
+func (activityBarTickMsg) Broadcast() {}
+
 var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
 // NewActivityBar creates a new activity bar
@@ -57,6 +80,7 @@ func NewActivityBar(opts ...ActivityBarOption) *ActivityBar {
 		activeColor:   "\033[1;36m",
 		hintColor:     "\033[2m",
 		progressColor: "\033[36m",
+		keys:          DefaultActivityBarKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -84,14 +108,25 @@ func (a *ActivityBar) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return a, a.tick()
 		}
 
+	case FrameMsg:
+		if a.active && frameDue(&a.lastFrame, msg.Time, activityBarFrameInterval) {
+			a.spinner = (a.spinner + 1) % len(spinnerFrames)
+			a.elapsed = time.Since(a.startTime)
+		}
+
 	case tea.KeyMsg:
-		if a.focused && a.active && a.cancelable && msg.String() == "esc" {
+		if a.focused && a.active && a.cancelable && a.keys.Matches(msg, "cancel") {
 			a.Stop()
 		}
 	}
 	return a, nil
 }
 
+// KeyMap returns the bar's bindings, which can be rebound
+func (a *ActivityBar) KeyMap() *KeyMap {
+	return a.keys
+}
+
 // View renders the activity bar
 func (a *ActivityBar) View() string {
 	if a.width == 0 {
@@ -115,7 +150,7 @@ func (a *ActivityBar) View() string {
 
 	// Cancelable hint
 	if a.cancelable {
-		status = append(status, a.hintColor+"esc to interrupt"+"\033[0m")
+		status = append(status, a.hintColor+a.keys.hint("cancel")+" to interrupt"+"\033[0m")
 	}
 
 	// Elapsed time
@@ -135,9 +170,7 @@ func (a *ActivityBar) View() string {
 	line := strings.Join(parts, " ")
 
 	// Truncate if too long
-	if len(stripANSI(line)) > a.width {
-		line = truncateANSI(line, a.width-3) + "..."
-	}
+	line = textwidth.TruncateWith(line, a.width, "...")
 
 	return line + "\n"
 }
@@ -179,9 +212,26 @@ func (a *ActivityBar) SetProgress(progress string) {
 	a.progress = progress
 }
 
-// tick returns a command that sends an activityBarTickMsg after a delay
+// SetFrameClock animates the bar on a shared clock instead of its own timer
+func (a *ActivityBar) SetFrameClock(clock *FrameClock) {
+	a.clock = clock
+}
+
+// Animating reports whether the bar's spinner is running
+func (a *ActivityBar) Animating() bool {
+	return a.active
+}
+
+// activityBarFrameInterval is how often the spinner steps
+const activityBarFrameInterval = 100 * time.Millisecond
+
+// tick wakes the frame clock, or without one returns a command that sends an
+// activityBarTickMsg after a delay
 func (a *ActivityBar) tick() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+	if a.clock != nil {
+		return a.clock.Wake()
+	}
+	return tea.Tick(activityBarFrameInterval, func(t time.Time) tea.Msg {
 		return activityBarTickMsg(t)
 	})
 }
@@ -213,49 +263,3 @@ func (a *ActivityBar) applyDesignTokens(tokens *design.DesignTokens) {
 		a.hintColor = foreground
 	}
 }
-
-// stripANSI removes ANSI escape codes (reused from border_components.go logic)
-func stripANSI(s string) string {
-	var result strings.Builder
-	inEscape := false
-
-	for _, r := range s {
-		if r == '\x1b' {
-			inEscape = true
-		} else if inEscape {
-			if r == 'm' {
-				inEscape = false
-			}
-		} else {
-			result.WriteRune(r)
-		}
-	}
-	return result.String()
-}
-
-// truncateANSI truncates preserving ANSI codes (reused from border_components.go logic)
-func truncateANSI(s string, maxWidth int) string {
-	var result strings.Builder
-	visualWidth := 0
-	inEscape := false
-
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\x1b' {
-			inEscape = true
-			result.WriteByte(s[i])
-		} else if inEscape {
-			result.WriteByte(s[i])
-			if s[i] == 'm' {
-				inEscape = false
-			}
-		} else {
-			if visualWidth >= maxWidth {
-				break
-			}
-			result.WriteByte(s[i])
-			visualWidth++
-		}
-	}
-
-	return result.String()
-}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -21,7 +22,7 @@ func TestActivityBarVeryLongStatus(t *testing.T) {
 	}
 
 	// Should truncate to fit width
-	if len(stripANSI(view)) > 100 {
+	if len(textwidth.Strip(view)) > 100 {
 		t.Error("View should truncate long status")
 	}
 }
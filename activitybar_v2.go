@@ -30,6 +30,8 @@ type ActivityBarV2 struct {
 	cancelable  bool
 	tokens      *design.DesignTokens
 	accentColor *color.Color
+	clock       *FrameClock // Shared frame clock; nil runs a timer of its own
+	lastFrame   time.Time
 }
 
 // NewActivityBarV2 creates an activity bar using the full stack
@@ -55,13 +57,19 @@ func (a *ActivityBarV2) Update(msg tea.Msg) (Component, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 
-	case tickMsg:
+	case activityBarTickMsg:
 		if a.active {
 			a.spinner = (a.spinner + 1) % len(spinnerFrames)
 			a.elapsed = time.Since(a.startTime)
 			return a, a.tick()
 		}
 
+	case FrameMsg:
+		if a.active && frameDue(&a.lastFrame, msg.Time, activityBarFrameInterval) {
+			a.spinner = (a.spinner + 1) % len(spinnerFrames)
+			a.elapsed = time.Since(a.startTime)
+		}
+
 	case tea.KeyMsg:
 		if a.focused && a.active && a.cancelable && msg.String() == "esc" {
 			a.Stop()
@@ -194,10 +202,25 @@ func (a *ActivityBarV2) SetProgress(progress string) {
 	a.progress = progress
 }
 
-// tick returns a command that sends a tickMsg after a delay
+// SetFrameClock animates the bar on a shared clock instead of its own timer
+func (a *ActivityBarV2) SetFrameClock(clock *FrameClock) {
+	a.clock = clock
+}
+
+// Animating reports whether the bar's spinner is running
+func (a *ActivityBarV2) Animating() bool {
+	return a.active
+}
+
+// tick wakes the frame clock, or without one returns a command that sends an
+// activityBarTickMsg after a delay. The tick is broadcast, like the default
+// ActivityBar's.
 func (a *ActivityBarV2) tick() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+	if a.clock != nil {
+		return a.clock.Wake()
+	}
+	return tea.Tick(activityBarFrameInterval, func(t time.Time) tea.Msg {
+		return activityBarTickMsg(t)
 	})
 }
 
@@ -4,27 +4,40 @@ import (
 	"fmt"
 	"strings"
 
+	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// CodeBlock displays source code with line numbers, syntax highlighting (future), and collapse/expand
+// CodeBlock displays source code with line numbers, syntax highlighting, and collapse/expand.
+// Highlighting is enabled by WithLanguage (any language registered with RegisterLexer)
+// or WithCodeLexer, and uses colors derived from design-system tokens.
 type CodeBlock struct {
 	width     int
 	height    int
 	focused   bool
 
 	// Content
-	operation string   // e.g., "Write", "Read", "Edit"
-	filename  string   // File being operated on
-	summary   string   // e.g., "Wrote 253 lines to file.go"
-	lines     []string // Code lines
-	language  string   // Programming language (for future syntax highlighting)
+	operation string      // e.g., "Write", "Read", "Edit"
+	filename  string      // File being operated on
+	summary   string      // e.g., "Wrote 253 lines to file.go"
+	lines     []string    // Code lines
+	language  string      // Programming language, selects the lexer for highlighting
+	lexer     Lexer       // Explicit lexer, overrides language
+	syntax    SyntaxTheme // Highlight colors (nil = default theme)
 
 	// Display state
 	expanded     bool // Whether code is shown or collapsed
 	maxLines     int  // Maximum lines to show when expanded (0 = show all)
 	startLine    int  // Starting line number (1-indexed)
 	showPreview  int  // Number of lines to show when collapsed (default 8)
+	currentLine  int  // Line marked by GoToLine (0 = none)
+
+	keys *KeyMap
+
+	// Header position from the last render, for mouse hit testing
+	hits HitMap
 }
 
 // CodeBlockOption configures a CodeBlock
@@ -72,6 +85,27 @@ func WithLanguage(lang string) CodeBlockOption {
 	}
 }
 
+// WithCodeLexer highlights the code with a custom lexer instead of a registered language
+func WithCodeLexer(lexer Lexer) CodeBlockOption {
+	return func(cb *CodeBlock) {
+		cb.lexer = lexer
+	}
+}
+
+// WithCodeDesignTokens applies design-system colors to syntax highlighting
+func WithCodeDesignTokens(tokens *design.DesignTokens) CodeBlockOption {
+	return func(cb *CodeBlock) {
+		cb.syntax = SyntaxThemeFromTokens(tokens)
+	}
+}
+
+// WithCodeTheme applies a named design-system theme to syntax highlighting
+func WithCodeTheme(theme string) CodeBlockOption {
+	return func(cb *CodeBlock) {
+		cb.syntax = SyntaxThemeFromTokens(designTokensForTheme(theme))
+	}
+}
+
 // WithStartLine sets the starting line number
 func WithStartLine(line int) CodeBlockOption {
 	return func(cb *CodeBlock) {
@@ -100,6 +134,21 @@ func WithPreviewLines(n int) CodeBlockOption {
 	}
 }
 
+// WithCodeKeyMap replaces the default keymap
+func WithCodeKeyMap(km *KeyMap) CodeBlockOption {
+	return func(cb *CodeBlock) {
+		cb.keys = km
+	}
+}
+
+// DefaultCodeBlockKeyMap returns the default CodeBlock bindings for the
+// action "toggle"
+func DefaultCodeBlockKeyMap() *KeyMap {
+	km := NewKeyMap("CodeBlock")
+	km.Bind("toggle", key.NewBinding(key.WithKeys("ctrl+o", "enter", " "), key.WithHelp("ctrl+o", "expand")))
+	return km
+}
+
 // NewCodeBlock creates a new code block component
 func NewCodeBlock(opts ...CodeBlockOption) *CodeBlock {
 	cb := &CodeBlock{
@@ -107,6 +156,7 @@ func NewCodeBlock(opts ...CodeBlockOption) *CodeBlock {
 		startLine:   1,
 		showPreview: 8,
 		expanded:    false,
+		keys:        DefaultCodeBlockKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -133,8 +183,15 @@ func (cb *CodeBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return cb, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+o", "enter", " ":
+		if cb.keys.Matches(msg, "toggle") {
+			cb.Toggle()
+		}
+
+	case tea.MouseMsg:
+		if !isLeftClick(msg) {
+			return cb, nil
+		}
+		if zone, ok := cb.hits.Test(msg.X, msg.Y); ok && zone.ID == "header" {
 			cb.Toggle()
 		}
 	}
@@ -156,6 +213,8 @@ func (cb *CodeBlock) View() string {
 	if cb.filename != "" {
 		b.WriteString(fmt.Sprintf("(\033[36m%s\033[0m)", cb.filename))
 	}
+	cb.hits.Reset()
+	cb.hits.Add("header", 0, Bounds{X: 0, Y: 0, Width: textwidth.Width(b.String()), Height: 1})
 	b.WriteString("\n")
 
 	// Summary line
@@ -188,6 +247,11 @@ func (cb *CodeBlock) Focused() bool {
 	return cb.focused
 }
 
+// KeyMap returns the block's bindings, which can be rebound
+func (cb *CodeBlock) KeyMap() *KeyMap {
+	return cb.keys
+}
+
 // Toggle expands or collapses the code block
 func (cb *CodeBlock) Toggle() {
 	cb.expanded = !cb.expanded
@@ -208,6 +272,22 @@ func (cb *CodeBlock) IsExpanded() bool {
 	return cb.expanded
 }
 
+// GoToLine expands the block and marks line n, scrolling it into view when
+// the block shows at most maxLines lines. Lines are numbered from the start
+// line.
+func (cb *CodeBlock) GoToLine(n int) {
+	if n < cb.startLine || n >= cb.startLine+len(cb.lines) {
+		return
+	}
+	cb.currentLine = n
+	cb.expanded = true
+}
+
+// CurrentLine returns the line marked by GoToLine, or 0 if none is
+func (cb *CodeBlock) CurrentLine() int {
+	return cb.currentLine
+}
+
 // getOperationIcon returns an icon for the operation type
 func (cb *CodeBlock) getOperationIcon() string {
 	switch strings.ToLower(cb.operation) {
@@ -234,15 +314,16 @@ func (cb *CodeBlock) renderCollapsed() string {
 	}
 
 	// Show preview lines
+	highlighted := cb.highlight(cb.lines[:linesToShow])
 	for i := 0; i < linesToShow; i++ {
 		lineNum := cb.startLine + i
-		b.WriteString(cb.renderLine(lineNum, cb.lines[i]))
+		b.WriteString(cb.renderLine(lineNum, highlighted[i]))
 	}
 
 	// Show "… +N lines" indicator
 	remainingLines := len(cb.lines) - linesToShow
 	if remainingLines > 0 {
-		b.WriteString(fmt.Sprintf("     \033[2m… +%d lines (\033[3mctrl+o to expand\033[0m\033[2m)\033[0m\n", remainingLines))
+		b.WriteString(fmt.Sprintf("     \033[2m… +%d lines (\033[3m%s to expand\033[0m\033[2m)\033[0m\n", remainingLines, cb.keys.hint("toggle")))
 	}
 
 	return b.String()
@@ -257,14 +338,22 @@ func (cb *CodeBlock) renderExpanded() string {
 		linesToShow = cb.maxLines
 	}
 
-	for i := 0; i < linesToShow; i++ {
+	// Center the current line when it would fall outside the shown lines
+	offset := 0
+	if cb.currentLine > 0 && cb.currentLine-cb.startLine >= linesToShow {
+		offset = min(cb.currentLine-cb.startLine-linesToShow/2, len(cb.lines)-linesToShow)
+		b.WriteString(fmt.Sprintf("     \033[2m… %d lines above\033[0m\n", offset))
+	}
+
+	// Highlight from the top so lexer state carries into the shown lines
+	highlighted := cb.highlight(cb.lines[:offset+linesToShow])
+	for i := offset; i < offset+linesToShow; i++ {
 		lineNum := cb.startLine + i
-		b.WriteString(cb.renderLine(lineNum, cb.lines[i]))
+		b.WriteString(cb.renderLine(lineNum, highlighted[i]))
 	}
 
 	// Show "… +N more lines" if truncated
-	if cb.maxLines > 0 && len(cb.lines) > cb.maxLines {
-		remainingLines := len(cb.lines) - cb.maxLines
+	if remainingLines := len(cb.lines) - offset - linesToShow; remainingLines > 0 {
 		b.WriteString(fmt.Sprintf("     \033[2m… +%d more lines (truncated)\033[0m\n", remainingLines))
 	}
 
@@ -278,5 +367,13 @@ func (cb *CodeBlock) renderLine(lineNum int, content string) string {
 	lineNumWidth := len(fmt.Sprintf("%d", maxLineNum))
 
 	// Render: "      1 package main"
+	if lineNum == cb.currentLine {
+		return fmt.Sprintf(" \033[33m❯\033[0m\033[1m%*d\033[0m %s\n", lineNumWidth, lineNum, content)
+	}
 	return fmt.Sprintf("  \033[2m%*d\033[0m %s\n", lineNumWidth, lineNum, content)
 }
+
+// highlight applies syntax highlighting to lines if a lexer is configured
+func (cb *CodeBlock) highlight(lines []string) []string {
+	return highlightCode(lines, cb.lexer, cb.language, cb.syntax)
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -461,3 +463,33 @@ func TestCodeBlockMultipleOptions(t *testing.T) {
 		t.Error("Expected expanded=true")
 	}
 }
+
+// TestCodeBlockGoToLine tests marking and scrolling to a line
+func TestCodeBlockGoToLine(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 50; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	cb := NewCodeBlock(WithCodeLines(lines), WithCodeMaxLines(10))
+
+	cb.GoToLine(40)
+	if !cb.IsExpanded() || cb.CurrentLine() != 40 {
+		t.Fatal("GoToLine should expand the block and mark the line")
+	}
+
+	view := textwidth.Strip(cb.View())
+	if !strings.Contains(view, "❯40 line 40") {
+		t.Errorf("Current line should be marked:\n%s", view)
+	}
+	if !strings.Contains(view, "… 34 lines above") || strings.Contains(view, "line 34\n") {
+		t.Errorf("View should scroll so the current line is visible:\n%s", view)
+	}
+	if !strings.Contains(view, "… +6 more lines") {
+		t.Errorf("View should count the lines below:\n%s", view)
+	}
+
+	cb.GoToLine(99)
+	if cb.CurrentLine() != 40 {
+		t.Error("Out of range lines should be ignored")
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CommandUsage records how often and how recently a command was run
+type CommandUsage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// HistoryStore persists command history between sessions
+type HistoryStore interface {
+	// Load returns the saved history, or an empty map if nothing was saved
+	Load() (map[string]CommandUsage, error)
+	// Save replaces the saved history
+	Save(usage map[string]CommandUsage) error
+}
+
+// FileHistoryStore saves command history as JSON in a file
+type FileHistoryStore struct {
+	path string
+}
+
+// NewFileHistoryStore creates a store that reads and writes the file at path.
+// The file and its directory are created on the first save.
+func NewFileHistoryStore(path string) *FileHistoryStore {
+	return &FileHistoryStore{path: path}
+}
+
+// Load reads the history file; a missing file is an empty history
+func (s *FileHistoryStore) Load() (map[string]CommandUsage, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]CommandUsage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]CommandUsage{}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// Save writes the history file, replacing it atomically so a crash never
+// leaves a half-written file behind
+func (s *FileHistoryStore) Save(usage map[string]CommandUsage) error {
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// HistorySaveErrorMsg reports that a HistoryStore failed to save. The
+// command that was recorded still ran.
+type HistorySaveErrorMsg struct {
+	Err error
+}
+
+// CommandHistory tracks which commands are run, keyed by command name, and
+// ranks them by frecency: a blend of how often and how recently each was used.
+type CommandHistory struct {
+	mu      sync.Mutex
+	usage   map[string]CommandUsage
+	store   HistoryStore
+	now     func() time.Time
+	version int // Incremented by every record
+
+	saveMu sync.Mutex // Serializes saves
+	saved  int        // Version last saved, so a stale snapshot is never written over a newer one
+}
+
+// NewCommandHistory creates a history backed by store, loading what it has
+// saved. Pass a nil store to keep history in memory only. If loading fails the
+// error is returned along with an empty history that is still usable.
+func NewCommandHistory(store HistoryStore) (*CommandHistory, error) {
+	h := &CommandHistory{
+		usage: map[string]CommandUsage{},
+		store: store,
+		now:   time.Now,
+	}
+	if store == nil {
+		return h, nil
+	}
+
+	usage, err := store.Load()
+	if err != nil {
+		return h, err
+	}
+	if usage != nil {
+		h.usage = usage
+	}
+	return h, nil
+}
+
+// Record counts a run of the named command and saves the history
+func (h *CommandHistory) Record(name string) error {
+	snapshot, version := h.count(name)
+	if snapshot == nil {
+		return nil
+	}
+	return h.save(snapshot, version)
+}
+
+// RecordCmd counts a run of the named command and returns a command that
+// saves the history off the update loop, sending a HistorySaveErrorMsg if
+// the save fails. It returns nil when the history has no store.
+func (h *CommandHistory) RecordCmd(name string) tea.Cmd {
+	snapshot, version := h.count(name)
+	if snapshot == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := h.save(snapshot, version); err != nil {
+			return HistorySaveErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// count records a run of the named command in memory and returns a copy of
+// the usage to save with its version, or nil without a store
+func (h *CommandHistory) count(name string) (map[string]CommandUsage, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	u := h.usage[name]
+	u.Count++
+	u.LastUsed = h.now()
+	h.usage[name] = u
+	h.version++
+
+	if h.store == nil {
+		return nil, h.version
+	}
+	snapshot := make(map[string]CommandUsage, len(h.usage))
+	for k, v := range h.usage {
+		snapshot[k] = v
+	}
+	return snapshot, h.version
+}
+
+// save writes snapshot unless a newer version was already saved
+func (h *CommandHistory) save(snapshot map[string]CommandUsage, version int) error {
+	h.saveMu.Lock()
+	defer h.saveMu.Unlock()
+
+	if version <= h.saved {
+		return nil
+	}
+	if err := h.store.Save(snapshot); err != nil {
+		return err
+	}
+	h.saved = version
+	return nil
+}
+
+// Usage returns the recorded usage of the named command
+func (h *CommandHistory) Usage(name string) CommandUsage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.usage[name]
+}
+
+// Frecency scores the named command by use count weighted by how recently it
+// was last used. Commands that were never run score 0.
+func (h *CommandHistory) Frecency(name string) int {
+	h.mu.Lock()
+	u, now := h.usage[name], h.now()
+	h.mu.Unlock()
+
+	if u.Count == 0 {
+		return 0
+	}
+
+	age := now.Sub(u.LastUsed)
+	switch {
+	case age < time.Hour:
+		return u.Count * 8
+	case age < 24*time.Hour:
+		return u.Count * 4
+	case age < 7*24*time.Hour:
+		return u.Count * 2
+	default:
+		return u.Count
+	}
+}
+
+// Rank returns commands ordered by frecency, most used first. Commands with
+// equal scores, including those never run, keep their original order.
+func (h *CommandHistory) Rank(commands []Command) []Command {
+	ranked := append([]Command(nil), commands...)
+	scores := make(map[string]int, len(ranked))
+	for _, cmd := range ranked {
+		scores[cmd.Name] = h.Frecency(cmd.Name)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].Name] > scores[ranked[j].Name]
+	})
+	return ranked
+}
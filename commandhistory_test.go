@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fixedClock returns a clock that can be moved forward by tests
+func fixedClock(t *time.Time) func() time.Time {
+	return func() time.Time { return *t }
+}
+
+func TestCommandHistoryFrecency(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h, _ := NewCommandHistory(nil)
+	h.now = fixedClock(&now)
+
+	h.Record("Old")
+	h.Record("Old")
+	h.Record("Old")
+	now = now.Add(30 * 24 * time.Hour)
+	h.Record("Recent")
+
+	if got := h.Usage("Old").Count; got != 3 {
+		t.Errorf("Expected 3 recorded runs, got %d", got)
+	}
+	if h.Frecency("Recent") <= h.Frecency("Old") {
+		t.Errorf("A command run just now should outrank one run often long ago: %d vs %d",
+			h.Frecency("Recent"), h.Frecency("Old"))
+	}
+	if h.Frecency("Never") != 0 {
+		t.Error("Commands never run should score 0")
+	}
+}
+
+func TestCommandHistoryRank(t *testing.T) {
+	h, _ := NewCommandHistory(nil)
+	h.Record("C")
+	h.Record("C")
+	h.Record("B")
+
+	ranked := h.Rank([]Command{{Name: "A"}, {Name: "B"}, {Name: "C"}, {Name: "D"}})
+	var names string
+	for _, cmd := range ranked {
+		names += cmd.Name
+	}
+	if names != "CBAD" {
+		t.Errorf("Expected used commands first and the rest in order, got %s", names)
+	}
+}
+
+func TestFileHistoryStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+	store := NewFileHistoryStore(path)
+
+	h, err := NewCommandHistory(store)
+	if err != nil {
+		t.Fatalf("A missing file should load as empty history: %v", err)
+	}
+	if err := h.Record("Save File"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewCommandHistory(NewFileHistoryStore(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Usage("Save File").Count != 1 {
+		t.Error("History should survive a reload from the file")
+	}
+}
+
+func TestFileHistoryStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	os.WriteFile(path, []byte("not json"), 0o644)
+
+	h, err := NewCommandHistory(NewFileHistoryStore(path))
+	if err == nil {
+		t.Error("A corrupt file should report an error")
+	}
+	if h == nil || h.Record("x") != nil {
+		t.Error("History should remain usable after a failed load")
+	}
+}
+
+// failingStore is a HistoryStore whose saves always fail
+type failingStore struct{}
+
+func (failingStore) Load() (map[string]CommandUsage, error) { return nil, nil }
+func (failingStore) Save(map[string]CommandUsage) error     { return errors.New("disk full") }
+
+// memoryStore is a HistoryStore that keeps the last save
+type memoryStore struct {
+	usage map[string]CommandUsage
+	saves int
+}
+
+func (s *memoryStore) Load() (map[string]CommandUsage, error) { return s.usage, nil }
+func (s *memoryStore) Save(usage map[string]CommandUsage) error {
+	s.usage = usage
+	s.saves++
+	return nil
+}
+
+func TestCommandPaletteRanksByHistory(t *testing.T) {
+	commands := []Command{{Name: "Open File"}, {Name: "Save File"}, {Name: "Close Window"}}
+	history, _ := NewCommandHistory(failingStore{})
+	cp := NewCommandPalette(commands, WithCommandHistory(history))
+	cp.Focus()
+
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	cp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if history.Usage("Close Window").Count != 1 {
+		t.Fatal("Executing a command should record it even if saving fails")
+	}
+
+	cp.Show()
+	if cp.filtered[0].Name != "Close Window" {
+		t.Errorf("Recently run command should lead the empty query, got %q", cp.filtered[0].Name)
+	}
+	if len(cp.filtered) != 3 {
+		t.Errorf("Empty query should still list every command, got %d", len(cp.filtered))
+	}
+}
+
+func TestCommandPaletteReportsHistorySaveError(t *testing.T) {
+	history, _ := NewCommandHistory(failingStore{})
+	cp := NewCommandPalette([]Command{{Name: "Quit", Action: func() tea.Cmd {
+		return func() tea.Msg { return "quit" }
+	}}}, WithCommandHistory(history))
+	cp.Focus()
+	cp.Show()
+
+	_, cmd := cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if history.Usage("Quit").Count != 1 {
+		t.Fatal("Usage should be recorded before the save runs")
+	}
+	if cmd == nil {
+		t.Fatal("Executing a command should return its action and the history save")
+	}
+
+	var msgs []tea.Msg
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Action and save should be batched")
+	}
+	for _, c := range batch {
+		msgs = append(msgs, c())
+	}
+	var saveErr error
+	ranAction := false
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case HistorySaveErrorMsg:
+			saveErr = msg.Err
+		case string:
+			ranAction = msg == "quit"
+		}
+	}
+	if !ranAction {
+		t.Error("Command action should run even though the save fails")
+	}
+	if saveErr == nil || saveErr.Error() != "disk full" {
+		t.Errorf("Failed save should be reported with HistorySaveErrorMsg, got %v", saveErr)
+	}
+}
+
+func TestCommandHistoryRecordCmdSkipsStaleSnapshot(t *testing.T) {
+	store := &memoryStore{}
+	h, _ := NewCommandHistory(store)
+	first := h.RecordCmd("A")
+	second := h.RecordCmd("B")
+
+	second()
+	first()
+	if store.saves != 1 || store.usage["A"].Count != 1 || store.usage["B"].Count != 1 {
+		t.Errorf("An older snapshot must not overwrite a newer one, got %d saves of %v", store.saves, store.usage)
+	}
+}
+
+func TestCommandPaletteHistoryBlendsIntoFuzzyScore(t *testing.T) {
+	commands := []Command{{Name: "Open File"}, {Name: "Open Folder"}}
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("of")})
+	if cp.filtered[0].Name != "Open File" {
+		t.Fatalf("Equal matches should keep their order, got %q", cp.filtered[0].Name)
+	}
+
+	cp.history.Record("Open Folder")
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("of")})
+	if cp.filtered[0].Name != "Open Folder" {
+		t.Errorf("History should break ties between similar matches, got %q", cp.filtered[0].Name)
+	}
+
+	// History cannot lift a poor match above a clearly better one
+	cp = NewCommandPalette([]Command{{Name: "Undefined"}, {Name: "Format Document"}})
+	for i := 0; i < 100; i++ {
+		cp.history.Record("Undefined")
+	}
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("fd")})
+	if len(cp.filtered) != 2 || cp.filtered[0].Name != "Format Document" {
+		t.Errorf("A much better match should still win, got %v", cp.filtered)
+	}
+}
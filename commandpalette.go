@@ -2,30 +2,66 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Command represents an executable command in the command palette with metadata
 // for display and categorization.
+//
+// A command can also lead somewhere else in the palette instead of running
+// straight away: Children opens a sub-menu, and Args prompts for each argument
+// in turn before calling Run. Backspace on an empty query goes back a level.
 type Command struct {
-	Name        string          // Display name of the command
-	Description string          // Brief description of what the command does
-	Category    string          // Category for grouping (e.g., "File", "Edit", "View")
-	Action      func() tea.Cmd  // Function to execute when command is selected
-	Keybinding  string          // Optional keyboard shortcut (e.g., "Ctrl+S")
+	Name        string                      // Display name of the command
+	Description string                      // Brief description of what the command does
+	Category    string                      // Category for grouping (e.g., "File", "Edit", "View")
+	Action      func() tea.Cmd              // Function to execute when command is selected
+	Keybinding  string                      // Optional keyboard shortcut (e.g., "Ctrl+S")
+	Children    []Command                   // Sub-menu opened when the command is selected
+	Args        []CommandArg                // Arguments prompted for before Run is called
+	Run         func(args []string) tea.Cmd // Called with the entered Args; takes precedence over Action
+}
+
+// CompletionProvider returns suggestions for an argument given what has been
+// typed so far. The palette fuzzy-filters and ranks the suggestions, so a
+// provider may return every candidate or narrow large sets itself.
+type CompletionProvider func(input string) []string
+
+// CommandArg describes an argument a command prompts for in the palette
+type CommandArg struct {
+	Name        string             // Shown in the title while prompting
+	Placeholder string             // Input placeholder; defaults to the name
+	Complete    CompletionProvider // Optional suggestions for the argument
+}
+
+// paletteFrame is a level the palette has descended into: a sub-menu or an
+// argument prompt
+type paletteFrame struct {
+	title   string
+	command Command  // Command that opened the level
+	arg     int      // Index of the prompted argument, -1 for sub-menus
+	args    []string // Arguments entered before this prompt
+	query   string   // Input of the parent level, restored on going back
 }
 
 // CommandPalette is a fuzzy-searchable command launcher inspired by VS Code's command
 // palette. It provides a popup interface for quickly executing commands via keyboard.
 //
 // Features:
-//   - Fuzzy search filtering
+//   - Fuzzy search with results ranked by score and matches highlighted
+//   - Recently and frequently run commands ranked first
+//   - Prefix-scoped providers, e.g. "#" for files or ":" for lines
+//   - Async providers that stream results while a spinner shows
 //   - Keyboard navigation (↑↓ or j/k)
 //   - Category grouping
-//   - Keybinding hints
+//   - Keybinding hints, and a "?" listing of an app's bindings
 //   - Toggle visibility with Ctrl+K
 //
 // Example usage:
@@ -44,29 +80,132 @@ type CommandPalette struct {
 	textInput  textinput.Model
 	commands   []Command
 	filtered   []Command
+	matches    [][]int // Matched rune positions in each filtered command's name
 	selected   int
 	maxVisible int
+	history    *CommandHistory
+	stack      []paletteFrame // Sub-menus and prompts entered, innermost last
+	providers  []PaletteProvider
+	search     paletteSearch // Background search of an async provider
+	debounce   time.Duration
+	spinner    Spinner
+	keys       *KeyMap
+	hits       HitMap
+	clock      *FrameClock // Shared frame clock; nil runs a timer of its own
+}
+
+// CommandPaletteOption configures a CommandPalette
+type CommandPaletteOption func(*CommandPalette)
+
+// WithCommandHistory sets the history used to rank commands. Share one
+// history between palettes, or back it with a HistoryStore to keep it
+// between sessions. By default history is kept in memory.
+func WithCommandHistory(history *CommandHistory) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.history = history
+	}
 }
 
+// WithPaletteProvider registers a provider for queries starting with its prefix
+func WithPaletteProvider(provider PaletteProvider) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.AddProvider(provider)
+	}
+}
+
+// WithPaletteKeyMap replaces the default keymap
+func WithPaletteKeyMap(km *KeyMap) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.keys = km
+	}
+}
+
+// WithPaletteKeys lists the bindings of registry under the "?" prefix
+func WithPaletteKeys(registry *KeyRegistry) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.SetKeyRegistry(registry)
+	}
+}
+
+// DefaultCommandPaletteKeyMap returns the default CommandPalette bindings for
+// the actions "open", "close", "run", "up", "down", "complete" and "back".
+// Backspace only goes back a level when the query is empty.
+func DefaultCommandPaletteKeyMap() *KeyMap {
+	km := NewKeyMap("CommandPalette")
+	km.Bind("open", key.NewBinding(key.WithKeys("ctrl+k", "ctrl+p"), key.WithHelp("ctrl+k", "command palette")))
+	km.Bind("close", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close")))
+	km.Bind("run", key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")))
+	km.Bind("up", key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next")))
+	km.Bind("complete", key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "complete")))
+	km.Bind("back", key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "back")))
+	return km
+}
+
+// frecencyBonusLimit caps how much history adds to a fuzzy score, so a much
+// better match still outranks a frequently used command
+const frecencyBonusLimit = scoreMatch
+
 // NewCommandPalette creates a new command palette with the given list of commands.
 // The palette is initially hidden and can be shown/hidden with Show() and Hide(),
 // or toggled with Toggle().
 //
 // The palette displays up to 8 commands at a time and supports fuzzy searching.
-func NewCommandPalette(commands []Command) *CommandPalette {
+// Executed commands are recorded in its history: with an empty query the most
+// used commands come first, and history nudges fuzzy results of similar
+// quality.
+func NewCommandPalette(commands []Command, opts ...CommandPaletteOption) *CommandPalette {
 	ti := textinput.New()
 	ti.Placeholder = "Type to search commands..."
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 50
 
-	return &CommandPalette{
+	cp := &CommandPalette{
 		textInput:  ti,
 		commands:   commands,
 		filtered:   commands,
 		maxVisible: 8,
 		visible:    false,
+		debounce:   150 * time.Millisecond,
+		spinner:    SpinnerDots,
+		keys:       DefaultCommandPaletteKeyMap(),
 	}
+
+	for _, opt := range opts {
+		opt(cp)
+	}
+
+	if cp.history == nil {
+		cp.history, _ = NewCommandHistory(nil) // In-memory history cannot fail
+	}
+	cp.filtered = cp.history.Rank(commands)
+
+	return cp
+}
+
+// KeyMap returns the palette's bindings, which can be rebound
+func (cp *CommandPalette) KeyMap() *KeyMap {
+	return cp.keys
+}
+
+// ConsumesKey reports whether the palette handles msg: the open binding while
+// hidden, and typed text and its own bindings while visible
+func (cp *CommandPalette) ConsumesKey(msg tea.KeyMsg) bool {
+	if !cp.visible {
+		return cp.keys.Matches(msg, "open")
+	}
+	return typesText(msg) || cp.keys.bound(msg)
+}
+
+// SetKeyRegistry lists the bindings of registry under the "?" prefix
+func (cp *CommandPalette) SetKeyRegistry(registry *KeyRegistry) {
+	cp.AddProvider(NewKeyBindingProvider(registry))
+}
+
+// History returns the history used to rank commands
+func (cp *CommandPalette) History() *CommandHistory {
+	return cp.history
 }
 
 // Init initializes the command palette
@@ -76,6 +215,13 @@ func (cp *CommandPalette) Init() tea.Cmd {
 
 // Update handles messages
 func (cp *CommandPalette) Update(msg tea.Msg) (Component, tea.Cmd) {
+	cmd := cp.update(msg)
+	// Queries typed into an async provider start a debounced search
+	return cp, tea.Batch(cmd, cp.scheduleSearch())
+}
+
+// update handles a message and returns the command to run
+func (cp *CommandPalette) update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -85,61 +231,81 @@ func (cp *CommandPalette) Update(msg tea.Msg) (Component, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if !cp.focused {
-			return cp, nil
+			return nil
 		}
 
 		// Toggle visibility with Ctrl+K or Ctrl+P
-		if (msg.Type == tea.KeyCtrlK || msg.Type == tea.KeyCtrlP) && !cp.visible {
+		if cp.keys.Matches(msg, "open") && !cp.visible {
 			cp.Show()
-			return cp, nil
+			return nil
 		}
 
 		if !cp.visible {
-			return cp, nil
+			return nil
 		}
 
-		switch msg.Type {
-		case tea.KeyEsc:
-			cp.Hide()
-			return cp, nil
-
-		case tea.KeyEnter:
+		action, _ := cp.keys.Lookup(msg)
+		switch action {
+		case "close":
 			cp.Hide()
-			if len(cp.filtered) > 0 && cp.selected < len(cp.filtered) {
-				selectedCmd := cp.filtered[cp.selected]
-				if selectedCmd.Action != nil {
-					return cp, selectedCmd.Action()
-				}
-			}
-			return cp, nil
-
-		case tea.KeyUp:
-			if cp.selected > 0 {
-				cp.selected--
+			return nil
+
+		case "run":
+			return cp.execute(cp.selected)
+
+		case "up":
+			cp.moveSelection(-1)
+			return nil
+
+		case "down":
+			cp.moveSelection(1)
+			return nil
+
+		case "complete":
+			// Complete the input with the selected suggestion, or the first
+			if cp.prompting() && len(cp.filtered) > 0 {
+				cp.textInput.SetValue(cp.filtered[max(cp.selected, 0)].Name)
+				cp.textInput.CursorEnd()
+				cp.queryChanged()
+				cp.resetSelection()
 			}
-			return cp, nil
+			return nil
 
-		case tea.KeyDown:
-			if cp.selected < len(cp.filtered)-1 {
-				cp.selected++
+		case "back":
+			if cp.textInput.Value() == "" && len(cp.stack) > 0 {
+				cp.Back()
+				return nil
 			}
-			return cp, nil
+			fallthrough
 
 		default:
 			// Update text input and filter commands
 			cp.textInput, cmd = cp.textInput.Update(msg)
-			cp.filterCommands()
-			cp.selected = 0 // Reset selection on new input
-			return cp, cmd
+			cp.queryChanged()
+			cp.resetSelection() // Reset selection on new input
+			return cmd
 		}
+
+	case tea.MouseMsg:
+		if !cp.visible {
+			return nil
+		}
+		return cp.handleMouse(msg)
+
+	case paletteSearchMsg, paletteResultsMsg, paletteTickMsg, FrameMsg:
+		return cp.handleSearchMsg(msg)
 	}
 
 	if cp.visible && cp.focused {
+		// Only refilter when the query changed, since providers may be slow
+		before := cp.textInput.Value()
 		cp.textInput, cmd = cp.textInput.Update(msg)
-		cp.filterCommands()
+		if cp.textInput.Value() != before {
+			cp.queryChanged()
+		}
 	}
 
-	return cp, cmd
+	return cmd
 }
 
 // View renders the command palette
@@ -149,6 +315,7 @@ func (cp *CommandPalette) View() string {
 	}
 
 	var b strings.Builder
+	cp.hits.Reset()
 
 	// Calculate dimensions
 	paletteWidth := min(60, cp.width-4)
@@ -167,11 +334,8 @@ func (cp *CommandPalette) View() string {
 	// Title bar
 	b.WriteString(strings.Repeat(" ", startX))
 	b.WriteString("\033[1;44m") // Blue background
-	title := " Command Palette "
-	padding := (paletteWidth - len(title)) / 2
-	b.WriteString(strings.Repeat(" ", padding))
-	b.WriteString(title)
-	b.WriteString(strings.Repeat(" ", paletteWidth-padding-len(title)))
+	title := textwidth.TruncateWith(" "+cp.title()+" ", paletteWidth, "… ")
+	b.WriteString(textwidth.Center(title, paletteWidth))
 	b.WriteString("\033[0m\n")
 
 	// Search input
@@ -182,9 +346,7 @@ func (cp *CommandPalette) View() string {
 
 	b.WriteString(strings.Repeat(" ", startX))
 	b.WriteString("\033[2m│\033[0m ")
-	inputView := cp.textInput.View()
-	b.WriteString(inputView)
-	b.WriteString(strings.Repeat(" ", paletteWidth-len(stripANSI(inputView))-4))
+	b.WriteString(textwidth.PadRight(cp.textInput.View(), paletteWidth-4))
 	b.WriteString(" \033[2m│\033[0m\n")
 
 	b.WriteString(strings.Repeat(" ", startX))
@@ -203,21 +365,26 @@ func (cp *CommandPalette) View() string {
 		b.WriteString(strings.Repeat(" ", startX))
 		b.WriteString("\033[2m│\033[0m ")
 		noResults := "No commands found"
-		b.WriteString(noResults)
-		b.WriteString(strings.Repeat(" ", paletteWidth-len(noResults)-4))
+		switch {
+		case cp.prompting():
+			noResults = "Press Enter to use the typed value"
+		case cp.search.loading:
+			noResults = "Searching…"
+		case cp.search.err != nil:
+			noResults = "\033[31mSearch failed: " + cp.search.err.Error() + "\033[0m"
+		}
+		b.WriteString(textwidth.Fit(noResults, paletteWidth-4))
 		b.WriteString(" \033[2m│\033[0m\n")
 	} else {
 		for i, cmd := range visibleCommands {
+			// Title, top border, input and separator occupy the first four rows
+			cp.hits.Add("command", i, Bounds{X: startX, Y: 4 + i, Width: paletteWidth, Height: 1})
 			b.WriteString(strings.Repeat(" ", startX))
 
 			if i == cp.selected {
 				// Selected item - highlighted
 				b.WriteString("\033[2m│\033[0m\033[7m ▸ ") // Inverted
-				cmdLine := fmt.Sprintf("%-30s", cmd.Name)
-				if len(cmdLine) > 30 {
-					cmdLine = cmdLine[:27] + "..."
-				}
-				b.WriteString(cmdLine)
+				b.WriteString(highlightMatches(cmd.Name, cp.matchesAt(i), 30))
 
 				if cmd.Keybinding != "" {
 					b.WriteString(" \033[2m")
@@ -226,17 +393,13 @@ func (cp *CommandPalette) View() string {
 				}
 
 				// Pad to width
-				currentLen := 33 + len(cmd.Keybinding)
+				currentLen := 33 + textwidth.Width(cmd.Keybinding)
 				b.WriteString(strings.Repeat(" ", paletteWidth-currentLen-3))
 				b.WriteString("\033[0m\033[2m│\033[0m\n")
 			} else {
 				// Normal item
 				b.WriteString("\033[2m│\033[0m   ")
-				cmdLine := fmt.Sprintf("%-30s", cmd.Name)
-				if len(cmdLine) > 30 {
-					cmdLine = cmdLine[:27] + "..."
-				}
-				b.WriteString(cmdLine)
+				b.WriteString(highlightMatches(cmd.Name, cp.matchesAt(i), 30))
 
 				if cmd.Keybinding != "" {
 					b.WriteString(" \033[2m")
@@ -245,7 +408,7 @@ func (cp *CommandPalette) View() string {
 				}
 
 				// Pad to width
-				currentLen := 33 + len(cmd.Keybinding)
+				currentLen := 33 + textwidth.Width(cmd.Keybinding)
 				b.WriteString(strings.Repeat(" ", paletteWidth-currentLen-3))
 				b.WriteString("\033[2m│\033[0m\n")
 			}
@@ -256,8 +419,17 @@ func (cp *CommandPalette) View() string {
 	b.WriteString(strings.Repeat(" ", startX))
 	b.WriteString("\033[2m└")
 	footer := fmt.Sprintf(" %d commands ", len(cp.filtered))
+	if provider, _ := cp.activeProvider(); provider != nil && len(cp.stack) == 0 {
+		footer = fmt.Sprintf(" %d results ", len(cp.filtered))
+	}
+	if cp.prompting() {
+		footer = fmt.Sprintf(" %d suggestions ", len(cp.filtered))
+	}
+	if cp.search.loading {
+		footer = " " + cp.spinner.GetFrame(cp.search.frame) + footer
+	}
 	b.WriteString(footer)
-	b.WriteString(strings.Repeat("─", paletteWidth-len(footer)-2))
+	b.WriteString(strings.Repeat("─", paletteWidth-textwidth.Width(footer)-2))
 	b.WriteString("┘\033[0m\n")
 
 	return b.String()
@@ -283,8 +455,10 @@ func (cp *CommandPalette) Focused() bool {
 // Show displays the command palette
 func (cp *CommandPalette) Show() {
 	cp.visible = true
+	cp.stack = nil
+	cp.textInput.Placeholder = "Type to search commands..."
 	cp.textInput.SetValue("")
-	cp.filtered = cp.commands
+	cp.queryChanged()
 	cp.selected = 0
 	cp.textInput.Focus()
 }
@@ -293,6 +467,7 @@ func (cp *CommandPalette) Show() {
 func (cp *CommandPalette) Hide() {
 	cp.visible = false
 	cp.textInput.Blur()
+	cp.cancelSearch()
 }
 
 // IsVisible returns whether the palette is currently visible
@@ -300,26 +475,321 @@ func (cp *CommandPalette) IsVisible() bool {
 	return cp.visible
 }
 
-// filterCommands filters the command list based on search query
-func (cp *CommandPalette) filterCommands() {
-	query := strings.ToLower(strings.TrimSpace(cp.textInput.Value()))
+// execute selects the filtered item at index. Commands with children open
+// their sub-menu and commands with arguments start prompting; anything else
+// hides the palette and runs. In a prompt the typed text becomes the
+// argument, unless a suggestion was selected with the arrow keys or mouse.
+func (cp *CommandPalette) execute(index int) tea.Cmd {
+	if cp.prompting() {
+		value := cp.textInput.Value()
+		if index >= 0 && index < len(cp.filtered) {
+			value = cp.filtered[index].Name
+		}
+		return cp.submitArg(value)
+	}
 
-	if query == "" {
-		cp.filtered = cp.commands
+	if index < 0 || index >= len(cp.filtered) {
+		cp.Hide()
+		return nil
+	}
+	selectedCmd := cp.filtered[index]
+
+	switch {
+	case len(selectedCmd.Children) > 0:
+		cp.push(paletteFrame{title: selectedCmd.Name, command: selectedCmd, arg: -1})
+		return nil
+	case len(selectedCmd.Args) > 0:
+		cp.push(paletteFrame{title: selectedCmd.Name, command: selectedCmd, arg: 0})
+		return nil
+	}
+
+	ranked := cp.ranked()
+	cp.Hide()
+	var save tea.Cmd
+	if ranked {
+		// History is saved in the background; a failed save must not block
+		// the command
+		save = cp.history.RecordCmd(selectedCmd.Name)
+	}
+	if selectedCmd.Action != nil {
+		return tea.Batch(selectedCmd.Action(), save)
+	}
+	return save
+}
+
+// submitArg records value for the prompted argument and moves on to the next
+// argument, or runs the command once all arguments are entered
+func (cp *CommandPalette) submitArg(value string) tea.Cmd {
+	frame := cp.stack[len(cp.stack)-1]
+	args := append(append([]string(nil), frame.args...), value)
+
+	if len(args) < len(frame.command.Args) {
+		cp.push(paletteFrame{title: frame.command.Name, command: frame.command, arg: len(args), args: args})
+		return nil
+	}
+
+	cp.Hide()
+	save := cp.history.RecordCmd(frame.command.Name)
+	switch {
+	case frame.command.Run != nil:
+		return tea.Batch(frame.command.Run(args), save)
+	case frame.command.Action != nil:
+		return tea.Batch(frame.command.Action(), save)
+	}
+	return save
+}
+
+// push enters a sub-menu or argument prompt
+func (cp *CommandPalette) push(frame paletteFrame) {
+	frame.query = cp.textInput.Value()
+	cp.stack = append(cp.stack, frame)
+	cp.textInput.Placeholder = cp.placeholder()
+	cp.textInput.SetValue("")
+	cp.queryChanged()
+	cp.resetSelection()
+}
+
+// Back leaves the current sub-menu or argument prompt, restoring the query
+// that was typed before entering it
+func (cp *CommandPalette) Back() {
+	if len(cp.stack) == 0 {
+		return
+	}
+	query := cp.stack[len(cp.stack)-1].query
+	cp.stack = cp.stack[:len(cp.stack)-1]
+	cp.textInput.Placeholder = cp.placeholder()
+	cp.textInput.SetValue(query)
+	cp.textInput.CursorEnd()
+	cp.queryChanged()
+	cp.resetSelection()
+}
+
+// prompting reports whether the palette is asking for an argument
+func (cp *CommandPalette) prompting() bool {
+	return len(cp.stack) > 0 && cp.stack[len(cp.stack)-1].arg >= 0
+}
+
+// resetSelection selects the first item. In a prompt nothing is selected, so
+// Enter submits the typed text until a suggestion is picked.
+func (cp *CommandPalette) resetSelection() {
+	cp.selected = 0
+	if cp.prompting() {
+		cp.selected = -1
+	}
+}
+
+// moveSelection moves the selection by delta, staying within the filtered
+// items. In a prompt it can move above the first suggestion, back to the
+// typed text.
+func (cp *CommandPalette) moveSelection(delta int) {
+	lowest := 0
+	if cp.prompting() {
+		lowest = -1
+	}
+	cp.selected = max(lowest, min(cp.selected+delta, len(cp.filtered)-1))
+}
+
+// title returns the title bar text with a breadcrumb of the levels entered
+func (cp *CommandPalette) title() string {
+	title := "Command Palette"
+	if provider, _ := cp.activeProvider(); provider != nil && len(cp.stack) == 0 {
+		title += " › " + provider.Name
+	}
+	for _, frame := range cp.stack {
+		if frame.arg <= 0 {
+			title += " › " + frame.title
+		}
+	}
+	if cp.prompting() {
+		frame := cp.stack[len(cp.stack)-1]
+		title += " › " + frame.command.Args[frame.arg].Name
+	}
+	return title
+}
+
+// placeholder returns the input placeholder for the current level
+func (cp *CommandPalette) placeholder() string {
+	if len(cp.stack) == 0 {
+		return "Type to search commands..."
+	}
+	frame := cp.stack[len(cp.stack)-1]
+	if frame.arg < 0 {
+		return "Type to search " + frame.title + "..."
+	}
+	arg := frame.command.Args[frame.arg]
+	if arg.Placeholder != "" {
+		return arg.Placeholder
+	}
+	return arg.Name
+}
+
+// source returns what the current level lists and the query to filter it
+// by: commands or a provider's items at the top level, sub-menu entries, or
+// argument suggestions. Exact items are shown unfiltered. An async
+// provider's items are the results received so far; queryChanged starts
+// the search for them.
+func (cp *CommandPalette) source() (items []Command, query string, exact bool) {
+	query = strings.TrimSpace(cp.textInput.Value())
+	if len(cp.stack) == 0 {
+		provider, rest := cp.activeProvider()
+		rest = strings.TrimSpace(rest)
+		switch {
+		case provider == nil:
+			return cp.commands, rest, false
+		case provider.Search != nil:
+			return cp.search.items, rest, provider.Exact
+		}
+		return provider.Items(rest), rest, provider.Exact
+	}
+
+	frame := cp.stack[len(cp.stack)-1]
+	if frame.arg < 0 {
+		return frame.command.Children, query, false
+	}
+
+	complete := frame.command.Args[frame.arg].Complete
+	if complete == nil {
+		return nil, query, false
+	}
+	for _, suggestion := range complete(cp.textInput.Value()) {
+		items = append(items, Command{Name: suggestion})
+	}
+	return items, query, false
+}
+
+// ranked reports whether the current level lists commands, which are ranked
+// and recorded with the command history
+func (cp *CommandPalette) ranked() bool {
+	if len(cp.stack) > 0 {
+		return !cp.prompting()
+	}
+	provider, _ := cp.activeProvider()
+	return provider == nil
+}
+
+// handleMouse runs a clicked command and moves the selection with the wheel
+func (cp *CommandPalette) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		cp.moveSelection(-1)
+		return nil
+	case tea.MouseButtonWheelDown:
+		cp.moveSelection(1)
+		return nil
+	}
+
+	if !isLeftClick(msg) {
+		return nil
+	}
+
+	zone, ok := cp.hits.Test(msg.X, msg.Y)
+	if !ok || zone.ID != "command" {
+		return nil
+	}
+	cp.selected = zone.Index
+	return cp.execute(zone.Index)
+}
+
+// queryChanged starts or cancels the background search to match a new query
+// or level, then filters for it. Searches only run at the top level; inside a
+// sub-menu or prompt the search is left as it was.
+func (cp *CommandPalette) queryChanged() {
+	if len(cp.stack) == 0 {
+		provider, rest := cp.activeProvider()
+		if provider != nil && provider.Search != nil {
+			cp.requestSearch(*provider, strings.TrimSpace(rest))
+		} else {
+			cp.cancelSearch()
+		}
+	}
+	cp.filterCommands()
+}
+
+// filterCommands fuzzy matches the search query against each command and
+// ranks the matches by score. Names are matched first; commands whose name
+// does not match can still be found by description or category, ranked
+// below name matches of the same quality. For command lists, frecency from
+// the history is added to each score, and alone orders the list when the
+// query is empty.
+func (cp *CommandPalette) filterCommands() {
+	items, query, exact := cp.source()
+	ranked := cp.ranked()
+
+	if query == "" || exact {
+		if ranked {
+			cp.filtered = cp.history.Rank(items)
+		} else {
+			cp.filtered = items // Keep the provider's order
+		}
+		cp.matches = nil
 		return
 	}
 
-	var filtered []Command
-	for _, cmd := range cp.commands {
-		// Simple substring matching (could be improved with fuzzy search)
-		if strings.Contains(strings.ToLower(cmd.Name), query) ||
-			strings.Contains(strings.ToLower(cmd.Description), query) ||
-			strings.Contains(strings.ToLower(cmd.Category), query) {
-			filtered = append(filtered, cmd)
+	type scored struct {
+		cmd       Command
+		score     int
+		positions []int
+	}
+	var results []scored
+	for _, cmd := range items {
+		bonus := 0
+		if ranked {
+			bonus = min(cp.history.Frecency(cmd.Name), frecencyBonusLimit)
+		}
+		if score, positions, ok := FuzzyMatch(query, cmd.Name); ok {
+			results = append(results, scored{cmd, score + bonus, positions})
+			continue
+		}
+		best, found := 0, false
+		for _, field := range []string{cmd.Description, cmd.Category} {
+			if score, _, ok := FuzzyMatch(query, field); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			results = append(results, scored{cmd, best/2 + bonus, nil})
 		}
 	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	cp.filtered = make([]Command, len(results))
+	cp.matches = make([][]int, len(results))
+	for i, r := range results {
+		cp.filtered[i] = r.cmd
+		cp.matches[i] = r.positions
+	}
+}
 
-	cp.filtered = filtered
+// matchesAt returns the matched name positions of the filtered command at index
+func (cp *CommandPalette) matchesAt(index int) []int {
+	if index < len(cp.matches) {
+		return cp.matches[index]
+	}
+	return nil
+}
+
+// highlightMatches pads or truncates name to width columns and emphasizes
+// the runes at positions. Emphasis only resets bold and color, so an
+// enclosing inverted selection stays intact.
+func highlightMatches(name string, positions []int, width int) string {
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(name) {
+		if matched[i] {
+			b.WriteString("\033[1;33m")
+			b.WriteRune(r)
+			b.WriteString("\033[22;39m")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return textwidth.PadRight(textwidth.TruncateWith(b.String(), width, "..."), width)
 }
 
 // Helper functions
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -614,3 +615,254 @@ func TestCommandPaletteNavigationWithEmptyResults(t *testing.T) {
 		t.Error("Filtered list should remain empty")
 	}
 }
+
+func TestCommandPaletteFuzzyRanking(t *testing.T) {
+	commands := []Command{
+		{Name: "Edit Profile"},
+		{Name: "Toggle Sidebar"},
+		{Name: "Open File"},
+	}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ofi")})
+
+	if len(cp.filtered) != 2 {
+		t.Fatalf("Expected 2 fuzzy matches for 'ofi', got %d", len(cp.filtered))
+	}
+	if cp.filtered[0].Name != "Open File" {
+		t.Errorf("Word boundary match should rank first, got %q", cp.filtered[0].Name)
+	}
+}
+
+func TestCommandPaletteNameRanksAboveDescription(t *testing.T) {
+	commands := []Command{
+		{Name: "Reload", Description: "Refresh the theme"},
+		{Name: "Change Theme"},
+	}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("theme")})
+
+	if len(cp.filtered) != 2 || cp.filtered[0].Name != "Change Theme" {
+		t.Errorf("Name match should rank above description match, got %v", cp.filtered)
+	}
+}
+
+func TestCommandPaletteHighlightsMatches(t *testing.T) {
+	cp := NewCommandPalette([]Command{{Name: "Open File"}, {Name: "Other"}})
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("of")})
+
+	view := cp.View()
+	if !strings.Contains(view, "\033[1;33mO\033[22;39mpen \033[1;33mF\033[22;39mile") {
+		t.Errorf("Matched characters should be highlighted:\n%q", view)
+	}
+	if !strings.Contains(textwidth.Strip(view), "▸ Open File") {
+		t.Error("Highlighting should not change the visible text")
+	}
+}
+
+func TestHighlightMatchesWidth(t *testing.T) {
+	got := textwidth.Strip(highlightMatches("Open", []int{0}, 10))
+	if got != "Open      " {
+		t.Errorf("Short names should be padded, got %q", got)
+	}
+
+	got = textwidth.Strip(highlightMatches("Ünïcödé command name here", []int{24}, 10))
+	if got != "Ünïcödé..." {
+		t.Errorf("Long names should be truncated by rune, got %q", got)
+	}
+}
+
+func TestCommandPaletteSubMenu(t *testing.T) {
+	var chosen string
+	theme := func(name string) Command {
+		return Command{Name: name, Action: func() tea.Cmd { chosen = name; return nil }}
+	}
+	commands := []Command{
+		{Name: "Switch Theme", Children: []Command{theme("Dark"), theme("Light"), theme("Solarized")}},
+		{Name: "Save File"},
+	}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !cp.IsVisible() || len(cp.filtered) != 3 {
+		t.Fatalf("Selecting a command with children should list them, got %d items", len(cp.filtered))
+	}
+	if !strings.Contains(textwidth.Strip(cp.View()), "Command Palette › Switch Theme") {
+		t.Error("Title should show the sub-menu breadcrumb")
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sol")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if chosen != "Solarized" || cp.IsVisible() {
+		t.Errorf("Expected Solarized to run and the palette to close, got %q", chosen)
+	}
+}
+
+func TestCommandPaletteBackspaceGoesBack(t *testing.T) {
+	commands := []Command{
+		{Name: "Switch Theme", Children: []Command{{Name: "Dark"}}},
+		{Name: "Save File"},
+	}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sw")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if len(cp.stack) != 1 {
+		t.Fatal("Backspace with text should edit the query, not go back")
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if len(cp.stack) != 0 {
+		t.Fatal("Backspace on an empty query should leave the sub-menu")
+	}
+	if cp.textInput.Value() != "sw" {
+		t.Errorf("Going back should restore the previous query, got %q", cp.textInput.Value())
+	}
+	if len(cp.filtered) != 1 || cp.filtered[0].Name != "Switch Theme" {
+		t.Error("Going back should restore the previous results")
+	}
+}
+
+func TestCommandPaletteArgumentPrompts(t *testing.T) {
+	var got []string
+	commands := []Command{{
+		Name: "Go to Line",
+		Args: []CommandArg{
+			{Name: "File", Complete: func(string) []string { return []string{"main.go", "model.go", "README.md"} }},
+			{Name: "Line", Placeholder: "Line number"},
+		},
+		Run: func(args []string) tea.Cmd { got = args; return nil },
+	}}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !cp.prompting() || len(cp.filtered) != 3 {
+		t.Fatalf("Expected a prompt with 3 suggestions, got %d", len(cp.filtered))
+	}
+	view := textwidth.Strip(cp.View())
+	if !strings.Contains(view, "Go to Line › File") || !strings.Contains(view, "3 suggestions") {
+		t.Errorf("Prompt should name the argument and count suggestions:\n%s", view)
+	}
+
+	// Suggestions are fuzzy filtered and Tab completes the selected one
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mo")})
+	if len(cp.filtered) != 2 || cp.filtered[0].Name != "model.go" {
+		t.Errorf("Expected model.go to rank first for 'mo', got %v", cp.filtered)
+	}
+	cp.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if cp.textInput.Value() != "model.go" {
+		t.Errorf("Tab should complete the suggestion, got %q", cp.textInput.Value())
+	}
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The second argument has no provider, so the typed text is used
+	if cp.textInput.Placeholder != "Line number" {
+		t.Errorf("Expected the second argument's placeholder, got %q", cp.textInput.Placeholder)
+	}
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("42")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(got) != 2 || got[0] != "model.go" || got[1] != "42" {
+		t.Errorf("Run should receive the entered arguments, got %v", got)
+	}
+	if cp.IsVisible() {
+		t.Error("Palette should close once all arguments are entered")
+	}
+	if cp.History().Usage("Go to Line").Count != 1 {
+		t.Error("Running a command with arguments should record it in history")
+	}
+}
+
+func TestCommandPaletteBackspaceDropsArgument(t *testing.T) {
+	commands := []Command{{
+		Name: "Rename",
+		Args: []CommandArg{{Name: "From"}, {Name: "To"}},
+	}}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if cp.textInput.Value() != "a" || cp.title() != "Command Palette › Rename › From" {
+		t.Errorf("Backspace should return to the first argument with its value, got %q at %q",
+			cp.textInput.Value(), cp.title())
+	}
+
+	// Showing again starts over at the top level
+	cp.Show()
+	if len(cp.stack) != 0 || cp.prompting() {
+		t.Error("Show should reset to the command list")
+	}
+}
+
+func TestCommandPalettePromptSubmitsTypedText(t *testing.T) {
+	var got []string
+	commands := []Command{{
+		Name: "Open",
+		Args: []CommandArg{{Name: "File", Complete: func(string) []string { return []string{"main.go", "model.go"} }}},
+		Run:  func(args []string) tea.Cmd { got = args; return nil },
+	}}
+
+	cp := NewCommandPalette(commands)
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Text that fuzzy matches a suggestion is still submitted as typed
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mn")})
+	if len(cp.filtered) == 0 {
+		t.Fatal("Expected 'mn' to match a suggestion")
+	}
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(got) != 1 || got[0] != "mn" {
+		t.Errorf("Enter should submit the typed text, got %v", got)
+	}
+
+	// Moving onto a suggestion submits it instead, and moving back up returns
+	// to the typed text
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mo")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	cp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	cp.Update(tea.KeyMsg{Type: tea.KeyUp})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(got) != 1 || got[0] != "model.go" {
+		t.Errorf("Enter should submit the selected suggestion, got %v", got)
+	}
+
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mo")})
+	cp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	cp.Update(tea.KeyMsg{Type: tea.KeyUp})
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(got) != 1 || got[0] != "mo" {
+		t.Errorf("Moving above the suggestions should submit the typed text, got %v", got)
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// Components are addressed by stable string IDs, so adding or removing one
+// doesn't change how the others are found. IDs are chosen with
+// AddComponentWithID or InsertBeforeWithID, or generated otherwise.
+
+// AddComponentWithID adds a component under id. It reports false, adding
+// nothing, if id is empty or already used.
+func (a *Application) AddComponentWithID(id string, c Component) bool {
+	if id == "" || a.indexOf(id) >= 0 {
+		return false
+	}
+	a.insert(len(a.components), id, c)
+	return true
+}
+
+// InsertBefore adds a component in front of the component with ID before and
+// returns its generated ID. It returns "" if before is not found.
+func (a *Application) InsertBefore(before string, c Component) string {
+	i := a.indexOf(before)
+	if i < 0 {
+		return ""
+	}
+	id := a.generateID()
+	a.insert(i, id, c)
+	return id
+}
+
+// InsertBeforeWithID adds a component under id in front of the component
+// with ID before. It reports false, adding nothing, if before is not found or
+// id is empty or already used.
+func (a *Application) InsertBeforeWithID(before, id string, c Component) bool {
+	i := a.indexOf(before)
+	if i < 0 || id == "" || a.indexOf(id) >= 0 {
+		return false
+	}
+	a.insert(i, id, c)
+	return true
+}
+
+// Get returns the component with the given ID
+func (a *Application) Get(id string) (Component, bool) {
+	i := a.indexOf(id)
+	if i < 0 {
+		return nil, false
+	}
+	return a.components[i], true
+}
+
+// Remove removes the component with the given ID, reporting whether it was
+// found. If it was focused, focus moves to the component that took its place,
+// or to the new last component.
+func (a *Application) Remove(id string) bool {
+	i := a.indexOf(id)
+	if i < 0 {
+		return false
+	}
+
+	c := a.components[i]
+	if km, ok := c.(KeyMapper); ok {
+		a.keys.Unregister(km.KeyMap())
+	}
+	a.components = append(a.components[:i], a.components[i+1:]...)
+	a.ids = append(a.ids[:i], a.ids[i+1:]...)
+	a.bounds = a.bounds[:0] // Stale until the next View
+
+	switch {
+	case i < a.focused:
+		a.focused--
+	case i == a.focused:
+		c.Blur()
+		a.focused = min(i, len(a.components)-1)
+		if a.focused >= 0 {
+			a.components[a.focused].Focus()
+		}
+	}
+	a.syncActiveKeys()
+	a.syncOverlays()
+	a.relayout()
+	return true
+}
+
+// FocusByID focuses the component with the given ID, reporting whether it
+// was found
+func (a *Application) FocusByID(id string) bool {
+	i := a.indexOf(id)
+	if i < 0 {
+		return false
+	}
+	a.FocusComponent(i)
+	return true
+}
+
+// FocusedID returns the ID of the focused component, or "" if none is
+func (a *Application) FocusedID() string {
+	if a.focused < 0 || a.focused >= len(a.ids) {
+		return ""
+	}
+	return a.ids[a.focused]
+}
+
+// IDs returns the component IDs in display order
+func (a *Application) IDs() []string {
+	return append([]string(nil), a.ids...)
+}
+
+// indexOf returns the index of the component with the given ID, or -1
+func (a *Application) indexOf(id string) int {
+	for i, existing := range a.ids {
+		if existing == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateID returns an unused ID of the form "component-N"
+func (a *Application) generateID() string {
+	for {
+		a.nextID++
+		id := fmt.Sprintf("component-%d", a.nextID)
+		if a.indexOf(id) < 0 {
+			return id
+		}
+	}
+}
+
+// insert adds c under id at index i, wiring it to the application's keys and
+// frame clock. The first component added is focused.
+func (a *Application) insert(i int, id string, c Component) {
+	a.components = append(a.components[:i], append([]Component{c}, a.components[i:]...)...)
+	a.ids = append(a.ids[:i], append([]string{id}, a.ids[i:]...)...)
+	a.bounds = a.bounds[:0] // Stale until the next View
+
+	if anim, ok := c.(Animated); ok {
+		anim.SetFrameClock(a.clock)
+	}
+	if km, ok := c.(KeyMapper); ok {
+		a.keys.Register(km.KeyMap())
+	}
+	if u, ok := c.(keyRegistryUser); ok {
+		u.SetKeyRegistry(a.keys)
+	}
+
+	switch {
+	case a.focused == -1:
+		a.focused = i
+		c.Focus()
+	case i <= a.focused:
+		a.focused++
+	}
+	a.syncActiveKeys()
+	a.relayout()
+}
+
+// relayout gives components their regions again after one is added or
+// removed, since regions are assigned in order. The commands they return are
+// kept until the next Init or Update can return them.
+func (a *Application) relayout() {
+	if a.root != nil && a.width > 0 {
+		a.pending = append(a.pending, a.resizeRegions())
+	}
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestApplicationComponentIDs(t *testing.T) {
+	app := NewApplication()
+	header := NewStatusBar()
+	id := app.AddComponent(header)
+	if id == "" {
+		t.Fatal("AddComponent should return a generated ID")
+	}
+	if !app.AddComponentWithID("input", NewTextInput()) {
+		t.Fatal("AddComponentWithID should accept a new ID")
+	}
+	if app.AddComponentWithID("input", NewStatusBar()) {
+		t.Error("AddComponentWithID should reject a duplicate ID")
+	}
+
+	if c, ok := app.Get(id); !ok || c != header {
+		t.Error("Get should return the component added under the generated ID")
+	}
+	if _, ok := app.Get("missing"); ok {
+		t.Error("Get should not find an unknown ID")
+	}
+	if got := strings.Join(app.IDs(), ","); got != id+",input" {
+		t.Errorf("IDs() = %s", got)
+	}
+}
+
+func TestApplicationInsertBeforeKeepsFocus(t *testing.T) {
+	app := NewApplication()
+	app.AddComponentWithID("header", NewStatusBar())
+	app.AddComponentWithID("input", NewTextInput())
+	app.FocusByID("input")
+
+	block := NewToolBlock("Bash", "ls", nil)
+	if !app.InsertBeforeWithID("input", "tool-1", block) {
+		t.Fatal("InsertBeforeWithID should insert before an existing ID")
+	}
+	if id := app.InsertBefore("missing", NewStatusBar()); id != "" {
+		t.Error("InsertBefore should fail for an unknown ID")
+	}
+
+	if got := strings.Join(app.IDs(), ","); got != "header,tool-1,input" {
+		t.Errorf("IDs() = %s", got)
+	}
+	if app.FocusedID() != "input" {
+		t.Errorf("Inserting should not move focus, focused %q", app.FocusedID())
+	}
+	if block.Focused() {
+		t.Error("The inserted component should not be focused")
+	}
+}
+
+func TestApplicationRemove(t *testing.T) {
+	app := NewApplication()
+	app.AddComponentWithID("header", NewStatusBar())
+	block := NewToolBlock("Bash", "ls", nil)
+	app.AddComponentWithID("tool-1", block)
+	input := NewTextInput()
+	app.AddComponentWithID("input", input)
+
+	app.FocusByID("input")
+	if !app.Remove("tool-1") {
+		t.Fatal("Remove should find the component")
+	}
+	if app.Remove("tool-1") {
+		t.Error("Removing twice should report false")
+	}
+	if app.FocusedID() != "input" || !input.Focused() {
+		t.Errorf("Removing an earlier component should keep focus, focused %q", app.FocusedID())
+	}
+	for _, km := range app.Keys().KeyMaps() {
+		if km == block.KeyMap() {
+			t.Error("Remove should unregister the component's keymap")
+		}
+	}
+
+	// Removing the focused component moves focus to its neighbour
+	app.Remove("input")
+	if app.FocusedID() != "header" {
+		t.Errorf("Focus should move to the remaining component, got %q", app.FocusedID())
+	}
+	if input.Focused() {
+		t.Error("The removed component should be blurred")
+	}
+
+	app.Remove("header")
+	if app.FocusedID() != "" {
+		t.Errorf("No component should be focused, got %q", app.FocusedID())
+	}
+	if _, cmd := app.Update(runeKey("x")); cmd != nil {
+		t.Error("An empty application should ignore keys")
+	}
+}
+
+func TestApplicationRemoveRelayouts(t *testing.T) {
+	app := NewApplication()
+	app.SetLayout(LayoutHelpers.HeaderContentFooterLayout(3, 1))
+	header := &sizeRecorder{view: "header"}
+	content := &sizeRecorder{view: "content"}
+	app.AddComponentWithID("header", header)
+	app.AddComponentWithID("content", content)
+	app.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+
+	app.Remove("header")
+	if content.height != 3 {
+		t.Errorf("After removal the content should take the first region, height %d", content.height)
+	}
+}
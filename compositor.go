@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/SCKelemen/layout"
+	"github.com/SCKelemen/tui/internal/textwidth"
+)
+
+// cellMetrics measures text in terminal cells so that 1ch resolves to exactly
+// one column when laying out a screen.
+type cellMetrics struct{}
+
+func (cellMetrics) Measure(text string, style layout.TextStyle) (advance, ascent, descent float64) {
+	return float64(textwidth.Width(text)), 0, 1
+}
+
+// layoutRegions lays out root for a width x height screen and returns the
+// absolute cell bounds of every leaf node, in depth-first order.
+func layoutRegions(root *layout.Node, width, height int) []Bounds {
+	ctx := layout.NewLayoutContext(float64(width), float64(height), 16).WithTextMetrics(cellMetrics{})
+	layout.Layout(root, layout.Tight(float64(width), float64(height)), ctx)
+
+	var regions []Bounds
+	var walk func(node *layout.Node, offsetX, offsetY float64)
+	walk = func(node *layout.Node, offsetX, offsetY float64) {
+		x := offsetX + node.Rect.X
+		y := offsetY + node.Rect.Y
+		if len(node.Children) == 0 {
+			// Round edges rather than sizes so adjacent regions never gap or overlap
+			x0, y0 := int(math.Round(x)), int(math.Round(y))
+			x1, y1 := int(math.Round(x+node.Rect.Width)), int(math.Round(y+node.Rect.Height))
+			regions = append(regions, Bounds{X: x0, Y: y0, Width: max(x1-x0, 0), Height: max(y1-y0, 0)})
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, x, y)
+		}
+	}
+	walk(root, 0, 0)
+
+	return regions
+}
+
+// framePane is a rendered view placed at a position on screen
+type framePane struct {
+	bounds Bounds
+	view   string
+}
+
+// composeFrame draws panes into a width x height frame. Each pane's view is
+// clipped and padded to its bounds; cells not covered by any pane are blank.
+// Panes must not overlap.
+func composeFrame(width, height int, panes []framePane) string {
+	rows := make([][]framePane, height)
+	for _, p := range panes {
+		lines := strings.Split(strings.TrimSuffix(p.view, "\n"), "\n")
+		for r := 0; r < p.bounds.Height; r++ {
+			y := p.bounds.Y + r
+			if y < 0 || y >= height {
+				continue
+			}
+			line := ""
+			if r < len(lines) {
+				line = lines[r]
+			}
+			rows[y] = append(rows[y], framePane{
+				bounds: Bounds{X: p.bounds.X, Y: y, Width: p.bounds.Width, Height: 1},
+				view:   line,
+			})
+		}
+	}
+
+	out := make([]string, height)
+	for y, segments := range rows {
+		sort.Slice(segments, func(i, j int) bool {
+			return segments[i].bounds.X < segments[j].bounds.X
+		})
+
+		var b strings.Builder
+		col := 0
+		for _, seg := range segments {
+			if seg.bounds.X < col || seg.bounds.X >= width {
+				continue
+			}
+			b.WriteString(strings.Repeat(" ", seg.bounds.X-col))
+			segWidth := min(seg.bounds.Width, width-seg.bounds.X)
+			b.WriteString(textwidth.Fit(seg.view, segWidth))
+			col = seg.bounds.X + segWidth
+		}
+		if col < width {
+			b.WriteString(strings.Repeat(" ", width-col))
+		}
+		out[y] = b.String()
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// overlayView draws overlay on top of base, line by line. Non-empty overlay
+// lines replace the base line at the same row.
+func overlayView(base, overlay string) string {
+	baseLines := strings.Split(base, "\n")
+	overlayLines := strings.Split(overlay, "\n")
+
+	// Merge lines - overlay takes precedence where it has content
+	maxLines := len(baseLines)
+	if len(overlayLines) > maxLines {
+		maxLines = len(overlayLines)
+	}
+
+	result := make([]string, maxLines)
+	for i := 0; i < maxLines; i++ {
+		if i < len(overlayLines) && overlayLines[i] != "" {
+			result[i] = overlayLines[i]
+		} else if i < len(baseLines) {
+			result[i] = baseLines[i]
+		} else {
+			result[i] = ""
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
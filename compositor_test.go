@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposeFrameSideBySide(t *testing.T) {
+	frame := composeFrame(10, 2, []framePane{
+		{bounds: Bounds{X: 0, Y: 0, Width: 4, Height: 2}, view: "left\nL2\n"},
+		{bounds: Bounds{X: 4, Y: 0, Width: 6, Height: 2}, view: "right"},
+	})
+
+	lines := strings.Split(frame, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(lines))
+	}
+	if lines[0] != "leftright " {
+		t.Errorf("Row 0 = %q", lines[0])
+	}
+	if lines[1] != "L2        " {
+		t.Errorf("Row 1 = %q", lines[1])
+	}
+}
+
+func TestComposeFrameClipsTallViews(t *testing.T) {
+	frame := composeFrame(5, 3, []framePane{
+		{bounds: Bounds{X: 0, Y: 1, Width: 5, Height: 1}, view: "one\ntwo\nthree"},
+	})
+
+	lines := strings.Split(frame, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(lines))
+	}
+	if lines[0] != "     " || lines[1] != "one  " || lines[2] != "     " {
+		t.Errorf("Unexpected frame %q", lines)
+	}
+}
+
+func TestLayoutRegionsSidebar(t *testing.T) {
+	regions := layoutRegions(LayoutHelpers.SidebarLayout(20), 100, 40)
+
+	if len(regions) != 2 {
+		t.Fatalf("Expected 2 regions, got %d", len(regions))
+	}
+
+	want := []Bounds{
+		{X: 0, Y: 0, Width: 20, Height: 40},
+		{X: 20, Y: 0, Width: 80, Height: 40},
+	}
+	for i, w := range want {
+		if regions[i] != w {
+			t.Errorf("Region %d = %+v, want %+v", i, regions[i], w)
+		}
+	}
+}
+
+func TestLayoutRegionsNestedOffsets(t *testing.T) {
+	root := LayoutHelpers.HeaderContentFooterLayout(3, 1)
+	content := LayoutHelpers.SidebarLayout(20)
+	content.Style.Width = root.Children[1].Style.Width
+	content.Style.Height = root.Children[1].Style.Height
+	content.Style.FlexGrow = 1
+	root.Children[1] = content
+
+	regions := layoutRegions(root, 100, 40)
+
+	if len(regions) != 4 {
+		t.Fatalf("Expected 4 leaf regions, got %d", len(regions))
+	}
+
+	// The sidebar's children are positioned relative to the content row
+	if regions[1] != (Bounds{X: 0, Y: 3, Width: 20, Height: 36}) {
+		t.Errorf("Sidebar region = %+v", regions[1])
+	}
+	if regions[2] != (Bounds{X: 20, Y: 3, Width: 80, Height: 36}) {
+		t.Errorf("Main region = %+v", regions[2])
+	}
+	if regions[3] != (Bounds{X: 0, Y: 39, Width: 100, Height: 1}) {
+		t.Errorf("Footer region = %+v", regions[3])
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	design "github.com/SCKelemen/design-system"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -18,6 +20,11 @@ type ConfirmationBlock struct {
 	filepath    string   // Full file path
 	description string   // e.g., "Create file ../yaml-lsp/data/test-issues.yaml"
 	code        []string // Code lines to preview
+	language    string   // Language of the code, selects the lexer for highlighting
+
+	// Syntax highlighting
+	lexer  Lexer       // Explicit lexer, overrides language
+	syntax SyntaxTheme // Highlight colors (nil = default theme)
 
 	// Confirmation options
 	options       []string // e.g., ["Yes", "Yes, allow all edits...", "No"]
@@ -29,6 +36,11 @@ type ConfirmationBlock struct {
 	showPreview  int  // Number of code lines to show (0 = all)
 	confirmed    bool // Whether user has confirmed
 	confirmedIdx int  // Which option was selected (-1 = none)
+
+	keys *KeyMap
+
+	// Option rows from the last render, for mouse hit testing
+	hits HitMap
 }
 
 // ConfirmationBlockOption configures a ConfirmationBlock
@@ -69,6 +81,34 @@ func WithConfirmCodeLines(lines []string) ConfirmationBlockOption {
 	}
 }
 
+// WithConfirmLanguage enables syntax highlighting of the code preview
+func WithConfirmLanguage(language string) ConfirmationBlockOption {
+	return func(cb *ConfirmationBlock) {
+		cb.language = language
+	}
+}
+
+// WithConfirmLexer highlights the code preview with a custom lexer
+func WithConfirmLexer(lexer Lexer) ConfirmationBlockOption {
+	return func(cb *ConfirmationBlock) {
+		cb.lexer = lexer
+	}
+}
+
+// WithConfirmDesignTokens applies design-system colors to syntax highlighting
+func WithConfirmDesignTokens(tokens *design.DesignTokens) ConfirmationBlockOption {
+	return func(cb *ConfirmationBlock) {
+		cb.syntax = SyntaxThemeFromTokens(tokens)
+	}
+}
+
+// WithConfirmTheme applies a named design-system theme to syntax highlighting
+func WithConfirmTheme(theme string) ConfirmationBlockOption {
+	return func(cb *ConfirmationBlock) {
+		cb.syntax = SyntaxThemeFromTokens(designTokensForTheme(theme))
+	}
+}
+
 // WithConfirmOptions sets the confirmation options
 func WithConfirmOptions(options []string) ConfirmationBlockOption {
 	return func(cb *ConfirmationBlock) {
@@ -97,6 +137,26 @@ func WithConfirmFooterHints(hints []string) ConfirmationBlockOption {
 	}
 }
 
+// WithConfirmKeyMap replaces the default keymap
+func WithConfirmKeyMap(km *KeyMap) ConfirmationBlockOption {
+	return func(cb *ConfirmationBlock) {
+		cb.keys = km
+	}
+}
+
+// DefaultConfirmationBlockKeyMap returns the default ConfirmationBlock
+// bindings for the actions "up", "down", "confirm", "cancel" and "select",
+// which picks an option by its number
+func DefaultConfirmationBlockKeyMap() *KeyMap {
+	km := NewKeyMap("ConfirmationBlock")
+	km.Bind("up", key.NewBinding(key.WithKeys("up", "k", "shift+tab"), key.WithHelp("↑", "previous option")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down", "j", "tab"), key.WithHelp("↓", "next option")))
+	km.Bind("confirm", key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "confirm")))
+	km.Bind("cancel", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")))
+	km.Bind("select", key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "choose option")))
+	return km
+}
+
 // NewConfirmationBlock creates a new confirmation block
 func NewConfirmationBlock(opts ...ConfirmationBlockOption) *ConfirmationBlock {
 	cb := &ConfirmationBlock{
@@ -112,6 +172,7 @@ func NewConfirmationBlock(opts ...ConfirmationBlockOption) *ConfirmationBlock {
 			"Esc to cancel",
 			"Tab to add additional instructions",
 		},
+		keys: DefaultConfirmationBlockKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -138,26 +199,30 @@ func (cb *ConfirmationBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return cb, nil
 		}
 
-		switch msg.String() {
-		case "up", "k", "shift+tab":
+		action, _ := cb.keys.Lookup(msg)
+		switch action {
+		case "up":
 			cb.selectedIndex--
 			if cb.selectedIndex < 0 {
 				cb.selectedIndex = len(cb.options) - 1
 			}
-		case "down", "j", "tab":
+		case "down":
 			cb.selectedIndex++
 			if cb.selectedIndex >= len(cb.options) {
 				cb.selectedIndex = 0
 			}
-		case "enter":
+		case "confirm":
 			cb.confirmed = true
 			cb.confirmedIdx = cb.selectedIndex
 			// Could return a custom message here
-		case "esc":
+		case "cancel":
 			cb.confirmed = true
 			cb.confirmedIdx = -1 // Cancelled
-		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		case "select":
 			// Quick select by number
+			if len(msg.Runes) != 1 {
+				break
+			}
 			idx := int(msg.Runes[0] - '1')
 			if idx >= 0 && idx < len(cb.options) {
 				cb.selectedIndex = idx
@@ -165,6 +230,16 @@ func (cb *ConfirmationBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 				cb.confirmedIdx = idx
 			}
 		}
+
+	case tea.MouseMsg:
+		if cb.confirmed || !isLeftClick(msg) {
+			return cb, nil
+		}
+		if zone, ok := cb.hits.Test(msg.X, msg.Y); ok && zone.ID == "option" {
+			cb.selectedIndex = zone.Index
+			cb.confirmed = true
+			cb.confirmedIdx = zone.Index
+		}
 	}
 
 	return cb, nil
@@ -177,6 +252,7 @@ func (cb *ConfirmationBlock) View() string {
 	}
 
 	var b strings.Builder
+	cb.hits.Reset()
 
 	// Header: ⏺ Operation(filepath)
 	icon := cb.getOperationIcon()
@@ -220,6 +296,8 @@ func (cb *ConfirmationBlock) View() string {
 
 	// Render options
 	for i, opt := range cb.options {
+		row := strings.Count(b.String(), "\n")
+		cb.hits.Add("option", i, Bounds{X: 0, Y: row, Width: width, Height: 1})
 		if i == cb.selectedIndex && cb.focused && !cb.confirmed {
 			// Selected option (highlighted with ❯)
 			b.WriteString(fmt.Sprintf(" \033[36m❯ %d. %s\033[0m\n", i+1, opt))
@@ -264,6 +342,17 @@ func (cb *ConfirmationBlock) Focused() bool {
 	return cb.focused
 }
 
+// KeyMap returns the block's bindings, which can be rebound
+func (cb *ConfirmationBlock) KeyMap() *KeyMap {
+	return cb.keys
+}
+
+// ConsumesKey reports whether the block handles msg, which it only does
+// until an option is chosen
+func (cb *ConfirmationBlock) ConsumesKey(msg tea.KeyMsg) bool {
+	return !cb.confirmed && cb.keys.bound(msg)
+}
+
 // IsConfirmed returns whether the user has made a choice
 func (cb *ConfirmationBlock) IsConfirmed() bool {
 	return cb.confirmed
@@ -324,9 +413,10 @@ func (cb *ConfirmationBlock) renderCode() string {
 	lineNumWidth := len(fmt.Sprintf("%d", maxLineNum))
 
 	// Render lines
+	code := highlightCode(cb.code[:linesToShow], cb.lexer, cb.language, cb.syntax)
 	for i := 0; i < linesToShow; i++ {
 		lineNum := cb.startLine + i
-		b.WriteString(fmt.Sprintf(" %*d %s\n", lineNumWidth, lineNum, cb.code[i]))
+		b.WriteString(fmt.Sprintf(" %*d %s\n", lineNumWidth, lineNum, code[i]))
 	}
 
 	// Show "... more lines" indicator if truncated
@@ -3,6 +3,8 @@ package tui
 import (
 	"strings"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/SCKelemen/cli/renderer"
 	"github.com/SCKelemen/color"
@@ -51,6 +53,11 @@ type Dashboard struct {
 
 	// Title
 	title string
+
+	// Card positions from the last render, for mouse hit testing
+	hits HitMap
+
+	keys *KeyMap
 }
 
 // DashboardOption configures a Dashboard
@@ -93,6 +100,26 @@ func WithCards(cards ...*StatCard) DashboardOption {
 	}
 }
 
+// WithDashboardKeyMap replaces the default keymap
+func WithDashboardKeyMap(km *KeyMap) DashboardOption {
+	return func(d *Dashboard) {
+		d.keys = km
+	}
+}
+
+// DefaultDashboardKeyMap returns the default Dashboard bindings for the
+// actions "up", "down", "left", "right", "open" and "clear"
+func DefaultDashboardKeyMap() *KeyMap {
+	km := NewKeyMap("Dashboard")
+	km.Bind("up", key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")))
+	km.Bind("left", key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")))
+	km.Bind("right", key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")))
+	km.Bind("open", key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")))
+	km.Bind("clear", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear selection")))
+	return km
+}
+
 // NewDashboard creates a new dashboard with the given configuration options.
 //
 // Defaults:
@@ -115,6 +142,7 @@ func NewDashboard(opts ...DashboardOption) *Dashboard {
 		focusedCardIndex:  -1, // No card focused initially
 		selectedCardIndex: -1, // No card selected initially
 		detailModal:       NewDetailModal(),
+		keys:              DefaultDashboardKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -180,20 +208,30 @@ func (d *Dashboard) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return d, nil
 		}
 
-		switch msg.String() {
-		case "up", "k":
+		action, _ := d.keys.Lookup(msg)
+		switch action {
+		case "up":
 			d.moveFocusUp()
-		case "down", "j":
+		case "down":
 			d.moveFocusDown()
-		case "left", "h":
+		case "left":
 			d.moveFocusLeft()
-		case "right", "l":
+		case "right":
 			d.moveFocusRight()
-		case "enter":
+		case "open":
 			d.openDetailModal()
-		case "esc":
+		case "clear":
 			d.clearSelection()
 		}
+
+	case tea.MouseMsg:
+		// The detail modal has no clickable parts; ignore clicks behind it
+		if d.detailModal.IsVisible() || !isLeftClick(msg) {
+			return d, nil
+		}
+		if zone, ok := d.hits.Test(msg.X, msg.Y); ok && zone.ID == "card" {
+			d.setFocusedCard(zone.Index)
+		}
 	}
 
 	return d, nil
@@ -216,37 +254,12 @@ func (d *Dashboard) View() string {
 	// If modal is visible, overlay it on top
 	if d.detailModal.IsVisible() {
 		modalView := d.detailModal.View()
-		return d.overlayModal(dashboardView, modalView)
+		return overlayView(dashboardView, modalView)
 	}
 
 	return dashboardView
 }
 
-// overlayModal overlays the modal view on top of the dashboard view
-func (d *Dashboard) overlayModal(base, overlay string) string {
-	baseLines := strings.Split(base, "\n")
-	overlayLines := strings.Split(overlay, "\n")
-
-	// Merge lines - overlay takes precedence where it has content
-	maxLines := len(baseLines)
-	if len(overlayLines) > maxLines {
-		maxLines = len(overlayLines)
-	}
-
-	result := make([]string, maxLines)
-	for i := 0; i < maxLines; i++ {
-		if i < len(overlayLines) && overlayLines[i] != "" {
-			result[i] = overlayLines[i]
-		} else if i < len(baseLines) {
-			result[i] = baseLines[i]
-		} else {
-			result[i] = ""
-		}
-	}
-
-	return strings.Join(result, "\n")
-}
-
 // Focus is called when this component receives focus
 func (d *Dashboard) Focus() {
 	d.focused = true
@@ -262,6 +275,17 @@ func (d *Dashboard) Focused() bool {
 	return d.focused
 }
 
+// KeyMap returns the dashboard's bindings, which can be rebound
+func (d *Dashboard) KeyMap() *KeyMap {
+	return d.keys
+}
+
+// ConsumesKey reports whether the dashboard handles msg. An open detail modal
+// takes every key.
+func (d *Dashboard) ConsumesKey(msg tea.KeyMsg) bool {
+	return d.detailModal.IsVisible() || d.keys.bound(msg)
+}
+
 // moveFocusUp moves focus to the card above
 func (d *Dashboard) moveFocusUp() {
 	if len(d.cards) == 0 {
@@ -459,6 +483,8 @@ func (d *Dashboard) renderWithLayout() string {
 // renderSimple provides string-based rendering with grid-like layout
 func (d *Dashboard) renderSimple() string {
 	var b strings.Builder
+	d.hits.Reset()
+	y := 0
 
 	// Render title if present
 	if d.title != "" {
@@ -467,19 +493,21 @@ func (d *Dashboard) renderSimple() string {
 		b.WriteString("╮\n")
 
 		// Center title
-		titlePadding := (d.width - len(d.title) - 2) / 2
+		titleWidth := textwidth.Width(d.title)
+		titlePadding := (d.width - titleWidth - 2) / 2
 		if titlePadding < 0 {
 			titlePadding = 0
 		}
 		b.WriteString("│")
 		b.WriteString(strings.Repeat(" ", titlePadding))
 		b.WriteString("\033[1m" + d.title + "\033[0m") // Bold
-		b.WriteString(strings.Repeat(" ", d.width-len(d.title)-titlePadding-2))
+		b.WriteString(strings.Repeat(" ", max(d.width-titleWidth-titlePadding-2, 0)))
 		b.WriteString("│\n")
 
 		b.WriteString("╰")
 		b.WriteString(strings.Repeat("─", d.width-2))
 		b.WriteString("╯\n")
+		y += 3
 	}
 
 	// Calculate columns
@@ -515,6 +543,14 @@ func (d *Dashboard) renderSimple() string {
 			}
 		}
 
+		// Record where each card in this row lands
+		x := 0
+		for j, card := range rowCards {
+			d.hits.Add("card", i+j, Bounds{X: x, Y: y, Width: card.width, Height: maxCardHeight})
+			x += card.width + int(d.gap)
+		}
+		y += maxCardHeight
+
 		// Render row line by line
 		for line := 0; line < maxCardHeight; line++ {
 			for j, view := range cardViews {
@@ -541,6 +577,7 @@ func (d *Dashboard) renderSimple() string {
 			for g := 0; g < int(d.gap); g++ {
 				b.WriteString("\n")
 			}
+			y += int(d.gap)
 		}
 	}
 
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	design "github.com/SCKelemen/design-system"
 )
@@ -48,6 +50,8 @@ type DetailModal struct {
 
 	// Additional details
 	history []string // Historical data points
+
+	keys *KeyMap
 }
 
 // DetailModalOption configures a DetailModal
@@ -74,6 +78,21 @@ func WithHistory(history []string) DetailModalOption {
 	}
 }
 
+// WithDetailModalKeyMap replaces the default keymap
+func WithDetailModalKeyMap(km *KeyMap) DetailModalOption {
+	return func(m *DetailModal) {
+		m.keys = km
+	}
+}
+
+// DefaultDetailModalKeyMap returns the default DetailModal bindings for the
+// action "close"
+func DefaultDetailModalKeyMap() *KeyMap {
+	km := NewKeyMap("DetailModal")
+	km.Bind("close", key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "close")))
+	return km
+}
+
 // NewDetailModal creates a new detail modal with the given configuration options.
 //
 // The modal is initially hidden and must be shown with Show(). Use SetContent to
@@ -88,6 +107,7 @@ func NewDetailModal(opts ...DetailModalOption) *DetailModal {
 		tokens:  design.DefaultTheme(),
 		visible: false,
 		history: []string{},
+		keys:    DefaultDetailModalKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -97,6 +117,17 @@ func NewDetailModal(opts ...DetailModalOption) *DetailModal {
 	return m
 }
 
+// KeyMap returns the modal's bindings, which can be rebound
+func (m *DetailModal) KeyMap() *KeyMap {
+	return m.keys
+}
+
+// ConsumesKey reports whether the modal handles msg, which it only does
+// while visible
+func (m *DetailModal) ConsumesKey(msg tea.KeyMsg) bool {
+	return m.visible && m.keys.bound(msg)
+}
+
 // Init initializes the modal
 func (m *DetailModal) Init() tea.Cmd {
 	return nil
@@ -121,8 +152,7 @@ func (m *DetailModal) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "esc", "q":
+		if m.keys.Matches(msg, "close") {
 			m.Hide()
 		}
 	}
@@ -251,8 +281,7 @@ func (m *DetailModal) renderModalContent(width, height int) string {
 	b.WriteString("║ ")
 	titleLine := fmt.Sprintf("\033[1m%s\033[0m", m.title)
 	closeHint := "[ESC to close]"
-	titleLen := len(m.title) // Visible length without ANSI
-	spacing := contentWidth - titleLen - len(closeHint)
+	spacing := contentWidth - textwidth.Width(m.title) - textwidth.Width(closeHint)
 	if spacing < 1 {
 		spacing = 1
 	}
@@ -476,20 +505,5 @@ func (m *DetailModal) calculateStats() (min, max, avg float64) {
 
 // visibleLength calculates the visible length of a string (excluding ANSI codes)
 func (m *DetailModal) visibleLength(str string) int {
-	inEscape := false
-	count := 0
-	for _, ch := range str {
-		if ch == '\033' {
-			inEscape = true
-			continue
-		}
-		if inEscape {
-			if ch == 'm' {
-				inEscape = false
-			}
-			continue
-		}
-		count++
-	}
-	return count
+	return textwidth.Width(str)
 }
@@ -0,0 +1,652 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DiffAlgorithm selects how line differences are computed
+type DiffAlgorithm int
+
+const (
+	DiffMyers    DiffAlgorithm = iota // Minimal edit script (default)
+	DiffPatience                      // Anchors on lines unique to both sides; keeps moved blocks readable
+)
+
+// DiffHunk is a run of changes together with its surrounding context lines
+type DiffHunk struct {
+	OldStart int        // First old line covered (line before the hunk if OldLines is 0)
+	OldLines int        // Number of old lines covered
+	NewStart int        // First new line covered (line before the hunk if NewLines is 0)
+	NewLines int        // Number of new lines covered
+	Lines    []DiffLine // Context, removed and added lines in display order
+}
+
+// Header returns the unified diff hunk header, e.g. "@@ -3,7 +3,8 @@"
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// ComputeDiff compares two sequences of lines and returns the edit script as
+// diff lines with old and new line numbers. Within each changed region,
+// removed lines come before added lines.
+func ComputeDiff(oldLines, newLines []string, algorithm DiffAlgorithm) []DiffLine {
+	ops := diffScript(oldLines, newLines, algorithm)
+	ops = groupChanges(ops)
+
+	result := make([]DiffLine, 0, len(ops))
+	oldIdx, newIdx := 0, 0
+	for _, op := range ops {
+		switch op {
+		case DiffUnchanged:
+			result = append(result, DiffLine{
+				Type:       DiffUnchanged,
+				Content:    newLines[newIdx],
+				LineNum:    newIdx + 1,
+				OldLineNum: oldIdx + 1,
+				NewLineNum: newIdx + 1,
+			})
+			oldIdx++
+			newIdx++
+		case DiffRemoved:
+			result = append(result, DiffLine{
+				Type:       DiffRemoved,
+				Content:    oldLines[oldIdx],
+				LineNum:    oldIdx + 1,
+				OldLineNum: oldIdx + 1,
+			})
+			oldIdx++
+		case DiffAdded:
+			result = append(result, DiffLine{
+				Type:       DiffAdded,
+				Content:    newLines[newIdx],
+				LineNum:    newIdx + 1,
+				NewLineNum: newIdx + 1,
+			})
+			newIdx++
+		}
+	}
+
+	return result
+}
+
+// DiffHunks groups diff lines into hunks, keeping up to context unchanged
+// lines around each change. Hunks whose context would touch are merged. A
+// negative context keeps every line in a single hunk.
+func DiffHunks(lines []DiffLine, context int) []DiffHunk {
+	numbered := numberDiffLines(lines, 1, 1)
+	return buildHunks(numbered, hunkSpans(numbered, context))
+}
+
+// hunkSpan is a half-open range of indices into a diff line slice
+type hunkSpan struct {
+	start, end int
+}
+
+// hunkSpans returns the index ranges of the hunks in numbered lines. Hunks
+// never cross a jump in line numbers, such as between the hunks of a parsed
+// patch.
+func hunkSpans(lines []DiffLine, context int) []hunkSpan {
+	segments, segmentOf := lineSegments(lines)
+	if context < 0 {
+		return segments
+	}
+
+	var spans []hunkSpan
+	lastSegment := -1
+	for i, line := range lines {
+		if line.Type == DiffUnchanged {
+			continue
+		}
+		seg := segments[segmentOf[i]]
+		start := max(i-context, seg.start)
+		end := min(i+context+1, seg.end)
+		if n := len(spans); n > 0 && segmentOf[i] == lastSegment && start <= spans[n-1].end {
+			spans[n-1].end = max(spans[n-1].end, end)
+			continue
+		}
+		spans = append(spans, hunkSpan{start, end})
+		lastSegment = segmentOf[i]
+	}
+	return spans
+}
+
+// lineSegments splits numbered lines into runs with contiguous line numbers
+// and returns the runs and the run index of every line
+func lineSegments(lines []DiffLine) ([]hunkSpan, []int) {
+	var segments []hunkSpan
+	segmentOf := make([]int, len(lines))
+	oldNext, newNext := -1, -1
+
+	for i, line := range lines {
+		jump := i == 0
+		if line.Type != DiffAdded {
+			jump = jump || (oldNext >= 0 && line.OldLineNum != oldNext)
+			oldNext = line.OldLineNum + 1
+		} else if line.anchored {
+			jump = jump || (oldNext >= 0 && line.oldBefore+1 != oldNext)
+			oldNext = line.oldBefore + 1
+		}
+		if line.Type != DiffRemoved {
+			jump = jump || (newNext >= 0 && line.NewLineNum != newNext)
+			newNext = line.NewLineNum + 1
+		} else if line.anchored {
+			jump = jump || (newNext >= 0 && line.newBefore+1 != newNext)
+			newNext = line.newBefore + 1
+		}
+		if jump {
+			segments = append(segments, hunkSpan{i, i})
+		}
+		segments[len(segments)-1].end = i + 1
+		segmentOf[i] = len(segments) - 1
+	}
+	return segments, segmentOf
+}
+
+// buildHunks converts spans over numbered lines into hunks
+func buildHunks(lines []DiffLine, spans []hunkSpan) []DiffHunk {
+	hunks := make([]DiffHunk, 0, len(spans))
+	for _, span := range spans {
+		h := DiffHunk{Lines: lines[span.start:span.end]}
+
+		// Without old or new lines, the start is the line just before the hunk
+		h.OldStart, h.NewStart = positionBefore(lines, span.start)
+		for _, line := range h.Lines {
+			if line.Type != DiffAdded {
+				if h.OldLines == 0 {
+					h.OldStart = line.OldLineNum
+				}
+				h.OldLines++
+			}
+			if line.Type != DiffRemoved {
+				if h.NewLines == 0 {
+					h.NewStart = line.NewLineNum
+				}
+				h.NewLines++
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// positionBefore returns the old and new line numbers just before index i,
+// taken from the anchor of a parsed line at i, or else by looking ahead to
+// the next numbered line on each side and falling back to the last one
+// before i
+func positionBefore(lines []DiffLine, i int) (oldNum, newNum int) {
+	oldNum, newNum = -1, -1
+	if i < len(lines) && lines[i].anchored {
+		switch lines[i].Type {
+		case DiffAdded:
+			oldNum = lines[i].oldBefore
+		case DiffRemoved:
+			newNum = lines[i].newBefore
+		}
+	}
+	for j := i; j < len(lines) && (oldNum < 0 || newNum < 0); j++ {
+		if oldNum < 0 && lines[j].OldLineNum > 0 {
+			oldNum = lines[j].OldLineNum - 1
+		}
+		if newNum < 0 && lines[j].NewLineNum > 0 {
+			newNum = lines[j].NewLineNum - 1
+		}
+	}
+	for j := i - 1; j >= 0 && (oldNum < 0 || newNum < 0); j-- {
+		if oldNum < 0 && lines[j].OldLineNum > 0 {
+			oldNum = lines[j].OldLineNum
+		}
+		if newNum < 0 && lines[j].NewLineNum > 0 {
+			newNum = lines[j].NewLineNum
+		}
+	}
+	return max(oldNum, 0), max(newNum, 0)
+}
+
+// numberDiffLines returns a copy of lines with OldLineNum and NewLineNum
+// filled in. Numbers already present are kept and counting continues from
+// them; missing numbers are counted from oldStart and newStart.
+func numberDiffLines(lines []DiffLine, oldStart, newStart int) []DiffLine {
+	out := make([]DiffLine, len(lines))
+	oldNext, newNext := oldStart, newStart
+	for i, line := range lines {
+		if line.Type != DiffAdded {
+			if line.OldLineNum > 0 {
+				oldNext = line.OldLineNum
+			}
+			line.OldLineNum = oldNext
+			oldNext++
+		}
+		if line.Type != DiffRemoved {
+			if line.NewLineNum > 0 {
+				newNext = line.NewLineNum
+			}
+			line.NewLineNum = newNext
+			newNext++
+		}
+		out[i] = line
+	}
+	return out
+}
+
+// diffScript returns the sequence of operations turning a into b. Each
+// DiffUnchanged consumes a line from both sides, DiffRemoved one from a and
+// DiffAdded one from b.
+func diffScript(a, b []string, algorithm DiffAlgorithm) []DiffType {
+	// Common prefix and suffix never need the full algorithm
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := repeatOp(DiffUnchanged, prefix)
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if algorithm == DiffPatience {
+		ops = append(ops, patienceScript(midA, midB)...)
+	} else {
+		ops = append(ops, myersScript(midA, midB)...)
+	}
+	return append(ops, repeatOp(DiffUnchanged, suffix)...)
+}
+
+// myersScript computes a shortest edit script with Myers' O(ND) algorithm,
+// in its linear-space form: the middle snake of an optimal path splits the
+// problem in two, so memory stays O(N+M) however different a and b are
+func myersScript(a, b []string) []DiffType {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return append(repeatOp(DiffRemoved, n), repeatOp(DiffAdded, m)...)
+	}
+
+	// Compare small integers instead of strings
+	ids := make(map[string]int, n)
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+
+	size := n + m + 2
+	md := &myers{
+		a:   intern(a),
+		b:   intern(b),
+		vf:  make([]int, 2*size+1),
+		vb:  make([]int, 2*size+1),
+		ops: make([]DiffType, 0, n+m),
+	}
+	md.compare(0, n, 0, m)
+	return md.ops
+}
+
+// myers holds the state of a linear-space Myers diff: the interned lines,
+// the furthest-reaching x per diagonal in each direction, reused across
+// recursive calls, and the script built so far
+type myers struct {
+	a, b   []int
+	vf, vb []int
+	ops    []DiffType
+}
+
+// compare appends the script turning a[a0:a1] into b[b0:b1]
+func (md *myers) compare(a0, a1, b0, b1 int) {
+	prefix := 0
+	for a0 < a1 && b0 < b1 && md.a[a0] == md.b[b0] {
+		a0++
+		b0++
+		prefix++
+	}
+	suffix := 0
+	for a0 < a1 && b0 < b1 && md.a[a1-1] == md.b[b1-1] {
+		a1--
+		b1--
+		suffix++
+	}
+	md.ops = append(md.ops, repeatOp(DiffUnchanged, prefix)...)
+
+	switch {
+	case a0 == a1 || b0 == b1:
+		md.ops = append(md.ops, repeatOp(DiffRemoved, a1-a0)...)
+		md.ops = append(md.ops, repeatOp(DiffAdded, b1-b0)...)
+	default:
+		// Without a common prefix or suffix there are at least two edits,
+		// so both halves are smaller problems
+		x, y, u, v := md.middleSnake(a0, a1, b0, b1)
+		md.compare(a0, x, b0, y)
+		md.ops = append(md.ops, repeatOp(DiffUnchanged, u-x)...)
+		md.compare(u, a1, v, b1)
+	}
+
+	md.ops = append(md.ops, repeatOp(DiffUnchanged, suffix)...)
+}
+
+// middleSnake searches from both ends of a[a0:a1] and b[b0:b1] at once and
+// returns the snake, from (x, y) to (u, v), where the searches meet on an
+// optimal path
+func (md *myers) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	offset := len(md.vf) / 2
+	vf, vb := md.vf, md.vb
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	for d := 0; d <= (n+m+1)/2; d++ {
+		// Forward: vf[k] is the furthest x reached on diagonal k = x-y
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && md.a[a0+x] == md.b[b0+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+
+			if rk := delta - k; odd && rk >= -(d-1) && rk <= d-1 && x+vb[offset+rk] >= n {
+				return a0 + startX, b0 + startY, a0 + x, b0 + y
+			}
+		}
+
+		// Backward, on the reversed sequences: vb[rk] is the furthest
+		// distance from the end reached on reversed diagonal rk
+		for rk := -d; rk <= d; rk += 2 {
+			var rx int
+			if rk == -d || (rk != d && vb[offset+rk-1] < vb[offset+rk+1]) {
+				rx = vb[offset+rk+1]
+			} else {
+				rx = vb[offset+rk-1] + 1
+			}
+			ry := rx - rk
+			startX, startY := rx, ry
+			for rx < n && ry < m && md.a[a1-1-rx] == md.b[b1-1-ry] {
+				rx++
+				ry++
+			}
+			vb[offset+rk] = rx
+
+			if k := delta - rk; !odd && k >= -d && k <= d && vf[offset+k]+rx >= n {
+				return a1 - rx, b1 - ry, a1 - startX, b1 - startY
+			}
+		}
+	}
+
+	return a0, b0, a0, b0 // Unreachable: the searches meet by d = (n+m+1)/2
+}
+
+// patienceScript anchors the diff on lines that occur exactly once in both a
+// and b, taking the longest increasing run of such matches, and recurses into
+// the gaps between anchors. Regions without unique lines fall back to Myers.
+func patienceScript(a, b []string) []DiffType {
+	if len(a) == 0 || len(b) == 0 {
+		return append(repeatOp(DiffRemoved, len(a)), repeatOp(DiffAdded, len(b))...)
+	}
+
+	anchors := uniqueAnchors(a, b)
+	if len(anchors) == 0 {
+		return myersScript(a, b)
+	}
+
+	var ops []DiffType
+	prevA, prevB := 0, 0
+	for _, anchor := range anchors {
+		ops = append(ops, diffScript(a[prevA:anchor[0]], b[prevB:anchor[1]], DiffPatience)...)
+		ops = append(ops, DiffUnchanged)
+		prevA, prevB = anchor[0]+1, anchor[1]+1
+	}
+	return append(ops, diffScript(a[prevA:], b[prevB:], DiffPatience)...)
+}
+
+// uniqueAnchors returns index pairs of lines unique to both a and b that form
+// the longest sequence increasing on both sides (patience sorting)
+func uniqueAnchors(a, b []string) [][2]int {
+	type count struct {
+		a, b int
+		bIdx int
+	}
+	counts := map[string]*count{}
+	for _, line := range a {
+		if counts[line] == nil {
+			counts[line] = &count{}
+		}
+		counts[line].a++
+	}
+	for j, line := range b {
+		if c := counts[line]; c != nil {
+			c.b++
+			c.bIdx = j
+		}
+	}
+
+	var matches [][2]int
+	for i, line := range a {
+		if c := counts[line]; c.a == 1 && c.b == 1 {
+			matches = append(matches, [2]int{i, c.bIdx})
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// Patience sort on b indices: piles hold the index of their top match,
+	// back[i] links each match to the top of the previous pile when placed
+	var piles []int
+	back := make([]int, len(matches))
+	for i, match := range matches {
+		p := sort.Search(len(piles), func(p int) bool {
+			return matches[piles[p]][1] > match[1]
+		})
+		back[i] = -1
+		if p > 0 {
+			back[i] = piles[p-1]
+		}
+		if p == len(piles) {
+			piles = append(piles, i)
+		} else {
+			piles[p] = i
+		}
+	}
+
+	anchors := make([][2]int, len(piles))
+	for i, idx := len(piles)-1, piles[len(piles)-1]; i >= 0; i, idx = i-1, back[idx] {
+		anchors[i] = matches[idx]
+	}
+	return anchors
+}
+
+// groupChanges reorders each run of changes so removals precede additions
+func groupChanges(ops []DiffType) []DiffType {
+	out := make([]DiffType, 0, len(ops))
+	for i := 0; i < len(ops); {
+		if ops[i] == DiffUnchanged {
+			out = append(out, DiffUnchanged)
+			i++
+			continue
+		}
+		removed, added := 0, 0
+		for ; i < len(ops) && ops[i] != DiffUnchanged; i++ {
+			if ops[i] == DiffRemoved {
+				removed++
+			} else {
+				added++
+			}
+		}
+		out = append(out, repeatOp(DiffRemoved, removed)...)
+		out = append(out, repeatOp(DiffAdded, added)...)
+	}
+	return out
+}
+
+// repeatOp returns n copies of op
+func repeatOp(op DiffType, n int) []DiffType {
+	ops := make([]DiffType, n)
+	for i := range ops {
+		ops[i] = op
+	}
+	return ops
+}
+
+// wordChanges compares two versions of a line word by word and returns the
+// rune ranges that differ in each. ok is false when the lines share too little
+// for emphasis to help, such as a complete rewrite.
+func wordChanges(oldLine, newLine string) (oldRanges, newRanges [][2]int, ok bool) {
+	oldWords, newWords := wordTokens(oldLine), wordTokens(newLine)
+	ops := diffScript(oldWords, newWords, DiffMyers)
+
+	// Measure similarity on non-space text so shared indentation does not count
+	common, oldPos, newPos := 0, 0, 0
+	for _, op := range ops {
+		switch op {
+		case DiffUnchanged:
+			if strings.TrimSpace(oldWords[oldPos]) != "" {
+				common += utf8.RuneCountInString(oldWords[oldPos])
+			}
+			oldPos++
+			newPos++
+		case DiffRemoved:
+			oldPos++
+		case DiffAdded:
+			newPos++
+		}
+	}
+	longest := max(nonSpaceLen(oldLine), nonSpaceLen(newLine))
+	if longest == 0 || common*2 < longest {
+		return nil, nil, false
+	}
+
+	oldRanges = changedRanges(ops, oldWords, DiffRemoved, DiffAdded)
+	newRanges = changedRanges(ops, newWords, DiffAdded, DiffRemoved)
+	return oldRanges, newRanges, len(oldRanges) > 0 || len(newRanges) > 0
+}
+
+// changedRanges returns the rune ranges of words with type changed on the
+// side described by words. Ranges separated only by whitespace are merged.
+func changedRanges(ops []DiffType, words []string, changed, other DiffType) [][2]int {
+	var ranges [][2]int
+	pos, idx := 0, 0
+	gapIsSpace := false // Only whitespace since the last range ended
+	for _, op := range ops {
+		if op == other {
+			continue
+		}
+		word := words[idx]
+		n := utf8.RuneCountInString(word)
+		if op == changed {
+			if last := len(ranges) - 1; last >= 0 && gapIsSpace {
+				ranges[last][1] = pos + n
+			} else {
+				ranges = append(ranges, [2]int{pos, pos + n})
+			}
+			gapIsSpace = true
+		} else if strings.TrimSpace(word) != "" {
+			gapIsSpace = false
+		}
+		pos += n
+		idx++
+	}
+	return ranges
+}
+
+// wordTokens splits a line into words, runs of whitespace and single
+// punctuation characters
+func wordTokens(s string) []string {
+	var tokens []string
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isWordRune(runes[i]):
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+		case unicode.IsSpace(runes[i]):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		tokens = append(tokens, string(runes[i:j]))
+		i = j
+	}
+	return tokens
+}
+
+// isWordRune reports whether r belongs to an identifier-like word
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// nonSpaceLen counts the runes in s that are not whitespace
+func nonSpaceLen(s string) int {
+	n := 0
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// emphasizeRanges shows the given rune ranges of a line's visible text in
+// reverse video. ANSI sequences already in the line are preserved; reverse
+// video is re-applied after each one so full resets inside a range do not
+// end the emphasis early.
+func emphasizeRanges(s string, ranges [][2]int) string {
+	if len(ranges) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	visible, r := 0, 0
+	inRange, inEscape := false, false
+	for _, c := range s {
+		if inEscape {
+			b.WriteRune(c)
+			if c == 'm' {
+				inEscape = false
+				if inRange {
+					b.WriteString("\033[7m")
+				}
+			}
+			continue
+		}
+		if c == '\x1b' {
+			inEscape = true
+			b.WriteRune(c)
+			continue
+		}
+
+		if inRange && visible == ranges[r][1] {
+			b.WriteString("\033[27m")
+			inRange = false
+			r++
+		}
+		if !inRange && r < len(ranges) && visible == ranges[r][0] {
+			b.WriteString("\033[7m")
+			inRange = true
+		}
+		b.WriteRune(c)
+		visible++
+	}
+	if inRange {
+		b.WriteString("\033[27m")
+	}
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+)
+
+// applyDiff rebuilds both sides of a diff to check it is a valid edit script
+func applyDiff(lines []DiffLine) (oldLines, newLines []string) {
+	for _, line := range lines {
+		if line.Type != DiffAdded {
+			oldLines = append(oldLines, line.Content)
+		}
+		if line.Type != DiffRemoved {
+			newLines = append(newLines, line.Content)
+		}
+	}
+	return oldLines, newLines
+}
+
+// changeCount returns the number of added and removed lines
+func changeCount(lines []DiffLine) int {
+	n := 0
+	for _, line := range lines {
+		if line.Type != DiffUnchanged {
+			n++
+		}
+	}
+	return n
+}
+
+func TestComputeDiffSeparateChanges(t *testing.T) {
+	oldLines := []string{"a", "b", "c", "d", "e", "f", "g"}
+	newLines := []string{"a", "B", "c", "d", "e", "F", "g"}
+
+	lines := ComputeDiff(oldLines, newLines, DiffMyers)
+
+	if got := changeCount(lines); got != 4 {
+		t.Errorf("Expected 4 changed lines for two edits, got %d", got)
+	}
+
+	// Unchanged lines between the edits stay unchanged
+	if lines[3].Type != DiffUnchanged || lines[3].Content != "c" {
+		t.Errorf("Expected c unchanged between edits, got %+v", lines[3])
+	}
+}
+
+func TestComputeDiffLineNumbers(t *testing.T) {
+	lines := ComputeDiff([]string{"a", "b", "c"}, []string{"a", "x", "y", "c"}, DiffMyers)
+
+	want := []DiffLine{
+		{Type: DiffUnchanged, Content: "a", LineNum: 1, OldLineNum: 1, NewLineNum: 1},
+		{Type: DiffRemoved, Content: "b", LineNum: 2, OldLineNum: 2},
+		{Type: DiffAdded, Content: "x", LineNum: 2, NewLineNum: 2},
+		{Type: DiffAdded, Content: "y", LineNum: 3, NewLineNum: 3},
+		{Type: DiffUnchanged, Content: "c", LineNum: 4, OldLineNum: 3, NewLineNum: 4},
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(want), len(lines), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("Line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestComputeDiffIsValidAndMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	random := func() []string {
+		out := make([]string, rng.Intn(12))
+		for i := range out {
+			out[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return out
+	}
+
+	for i := 0; i < 200; i++ {
+		a, b := random(), random()
+
+		for _, alg := range []DiffAlgorithm{DiffMyers, DiffPatience} {
+			lines := ComputeDiff(a, b, alg)
+			gotOld, gotNew := applyDiff(lines)
+			if strings.Join(gotOld, ",") != strings.Join(a, ",") || strings.Join(gotNew, ",") != strings.Join(b, ",") {
+				t.Fatalf("Algorithm %d produced an invalid diff for %v -> %v", alg, a, b)
+			}
+		}
+
+		// Myers must match the edit distance given by the LCS
+		if got, want := changeCount(ComputeDiff(a, b, DiffMyers)), len(a)+len(b)-2*lcsLength(a, b); got != want {
+			t.Fatalf("Myers diff of %v -> %v has %d changes, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestMyersDiffFullRewriteMemory(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 6000; i++ {
+		a = append(a, "old "+strconv.Itoa(i))
+		b = append(b, "new "+strconv.Itoa(i))
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines := ComputeDiff(a, b, DiffMyers)
+	runtime.ReadMemStats(&after)
+
+	if changeCount(lines) != 12000 {
+		t.Errorf("Full rewrite has %d changes, want 12000", changeCount(lines))
+	}
+	// Linear space: far below the gigabytes a full trace would need
+	if mb := (after.TotalAlloc - before.TotalAlloc) >> 20; mb > 64 {
+		t.Errorf("Diffing 6,000 rewritten lines allocated %d MB", mb)
+	}
+}
+
+// lcsLength is a reference dynamic-programming LCS
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func TestPatienceDiffAnchorsOnUniqueLines(t *testing.T) {
+	oldLines := []string{"}", "func a() {", "}", "func b() {", "}"}
+	newLines := []string{"}", "func b() {", "}", "func a() {", "}"}
+
+	lines := ComputeDiff(oldLines, newLines, DiffPatience)
+
+	// Only one of the swapped functions can be kept; the other moves
+	kept := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line.Content, "func") && line.Type == DiffUnchanged {
+			kept++
+		}
+	}
+	if kept != 1 {
+		t.Errorf("Expected exactly one function line anchored, got %d: %+v", kept, lines)
+	}
+}
+
+func TestDiffHunksCollapseUnchangedRegions(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, strings.Repeat("x", i))
+	}
+	newLines := append([]string(nil), oldLines...)
+	newLines[2] = "changed near top"
+	newLines[16] = "changed near bottom"
+
+	hunks := DiffHunks(ComputeDiff(oldLines, newLines, DiffMyers), 2)
+
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(hunks))
+	}
+	if got := hunks[0].Header(); got != "@@ -1,5 +1,5 @@" {
+		t.Errorf("First hunk header = %q", got)
+	}
+	if got := hunks[1].Header(); got != "@@ -15,5 +15,5 @@" {
+		t.Errorf("Second hunk header = %q", got)
+	}
+
+	// Larger context merges the hunks
+	if got := len(DiffHunks(ComputeDiff(oldLines, newLines, DiffMyers), 7)); got != 1 {
+		t.Errorf("Expected hunks to merge with context 7, got %d", got)
+	}
+
+	// Negative context keeps everything
+	all := DiffHunks(ComputeDiff(oldLines, newLines, DiffMyers), -1)
+	if len(all) != 1 || all[0].OldLines != 20 {
+		t.Errorf("Expected a single hunk covering all lines, got %+v", all)
+	}
+}
+
+func TestDiffHunkHeaderForPureInsertion(t *testing.T) {
+	lines := ComputeDiff([]string{"a", "b"}, []string{"a", "new", "b"}, DiffMyers)
+	hunks := DiffHunks(lines, 0)
+
+	if len(hunks) != 1 {
+		t.Fatalf("Expected 1 hunk, got %d", len(hunks))
+	}
+	if got := hunks[0].Header(); got != "@@ -1,0 +2,1 @@" {
+		t.Errorf("Insertion header = %q, want @@ -1,0 +2,1 @@", got)
+	}
+}
+
+func TestDiffHunksNumberManualLines(t *testing.T) {
+	hunks := DiffHunks([]DiffLine{
+		{Type: DiffUnchanged, Content: "a"},
+		{Type: DiffRemoved, Content: "b"},
+		{Type: DiffAdded, Content: "c"},
+	}, 3)
+
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -1,2 +1,2 @@" {
+		t.Errorf("Unexpected hunks for unnumbered lines: %+v", hunks)
+	}
+	if hunks[0].Lines[2].NewLineNum != 2 {
+		t.Errorf("Expected added line numbered 2, got %d", hunks[0].Lines[2].NewLineNum)
+	}
+}
+
+func TestWordChangesRename(t *testing.T) {
+	oldRanges, newRanges, ok := wordChanges("total := sum(values)", "total := sum(items)")
+	if !ok {
+		t.Fatal("Similar lines should be compared word by word")
+	}
+
+	if len(oldRanges) != 1 || oldRanges[0] != [2]int{13, 19} {
+		t.Errorf("Expected old range over \"values\", got %v", oldRanges)
+	}
+	if len(newRanges) != 1 || newRanges[0] != [2]int{13, 18} {
+		t.Errorf("Expected new range over \"items\", got %v", newRanges)
+	}
+}
+
+func TestWordChangesMergesAcrossSpaces(t *testing.T) {
+	_, newRanges, ok := wordChanges("a b c d e f", "a x y d e f")
+	if !ok {
+		t.Fatal("Expected lines to be paired")
+	}
+	if len(newRanges) != 1 || newRanges[0] != [2]int{2, 5} {
+		t.Errorf("Adjacent changed words should form one range, got %v", newRanges)
+	}
+}
+
+func TestWordChangesSkipsRewrites(t *testing.T) {
+	if _, _, ok := wordChanges("    return nil", "    panic(err)"); ok {
+		t.Error("Unrelated lines should not be emphasized")
+	}
+}
+
+func TestEmphasizeRanges(t *testing.T) {
+	got := emphasizeRanges("foo bar", [][2]int{{4, 7}})
+	if got != "foo \033[7mbar\033[27m" {
+		t.Errorf("Unexpected emphasis %q", got)
+	}
+
+	// Resets from syntax highlighting inside a range keep the emphasis
+	styled := "\033[35mfoo\033[0m bar"
+	got = emphasizeRanges(styled, [][2]int{{0, 5}})
+	if got != "\033[35m\033[7mfoo\033[0m\033[7m b\033[27mar" {
+		t.Errorf("Unexpected emphasis over styled text %q", got)
+	}
+	if textwidth.Strip(got) != "foo bar" {
+		t.Error("Emphasis should not change visible text")
+	}
+}
@@ -4,14 +4,27 @@ import (
 	"fmt"
 	"strings"
 
+	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // DiffLine represents a single line in a diff
 type DiffLine struct {
-	Type    DiffType // Added, Removed, or Unchanged
-	Content string   // Line content
-	LineNum int      // Line number (for context)
+	Type       DiffType // Added, Removed, or Unchanged
+	Content    string   // Line content
+	LineNum    int      // Line number (for context)
+	OldLineNum int      // Line number in the old file (0 for added lines)
+	NewLineNum int      // Line number in the new file (0 for removed lines)
+	NoNewline  bool     // Line is the last in its file and has no trailing newline
+
+	// Position of an added or removed line on its other side, taken from a
+	// parsed hunk header: the old line an addition follows or the new line a
+	// removal follows. It anchors hunks that only add or only remove lines.
+	anchored  bool
+	oldBefore int
+	newBefore int
 }
 
 // DiffType indicates the type of diff line
@@ -23,7 +36,18 @@ const (
 	DiffRemoved                    // Line was removed (-)
 )
 
-// DiffBlock displays code changes with +/- indicators
+// DiffViewMode selects how a DiffBlock lays out changes
+type DiffViewMode int
+
+const (
+	DiffViewUnified DiffViewMode = iota // Single column with +/- lines
+	DiffViewSplit                       // Old on the left, new on the right
+)
+
+// DiffBlock displays code changes with +/- indicators.
+// In split mode the old and new sides are shown next to each other when the
+// block is at least splitMinWidth columns wide; narrower blocks fall back to
+// the unified layout. Press "s" while focused to switch modes.
 type DiffBlock struct {
 	width   int
 	height  int
@@ -36,11 +60,32 @@ type DiffBlock struct {
 	lines       []DiffLine // Diff lines
 	oldStart    int        // Starting line number in old file
 	newStart    int        // Starting line number in new file
+	language    string     // Language of the file, selects the lexer for highlighting
+
+	// Syntax highlighting
+	lexer  Lexer       // Explicit lexer, overrides language
+	syntax SyntaxTheme // Highlight colors (nil = default theme)
 
 	// Display state
-	expanded     bool // Whether diff is shown or collapsed
-	showContext  int  // Number of context lines to show around changes (default 3)
-	maxLines     int  // Maximum lines to show when expanded (0 = show all)
+	expanded    bool          // Whether diff is shown or collapsed
+	showContext int           // Number of context lines to show around changes (default 3, negative = all)
+	maxLines    int           // Maximum lines to show when expanded (0 = show all)
+	algorithm   DiffAlgorithm // Algorithm used by NewDiffBlockFromStrings
+	viewMode    DiffViewMode  // Unified or split layout
+	splitMin    int           // Minimum width for the split layout (default 100)
+	wordDiff    bool          // Emphasize changed words in paired removed/added lines (default true)
+
+	// Hunk staging
+	staging   bool                                   // Whether hunks can be accepted and rejected
+	current   int                                    // Selected hunk while staging
+	decisions map[int]HunkDecision                   // Decision per hunk index (missing = pending)
+	edits     map[int][]string                       // Replacement lines for edited hunks
+	onEdit    func(index int, hunk DiffHunk) tea.Cmd // Called when the user asks to edit a hunk
+
+	keys *KeyMap
+
+	// Header position from the last render, for mouse hit testing
+	hits HitMap
 }
 
 // DiffBlockOption configures a DiffBlock
@@ -74,6 +119,34 @@ func WithDiffLines(lines []DiffLine) DiffBlockOption {
 	}
 }
 
+// WithDiffLanguage enables syntax highlighting of line content
+func WithDiffLanguage(language string) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.language = language
+	}
+}
+
+// WithDiffLexer highlights line content with a custom lexer
+func WithDiffLexer(lexer Lexer) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.lexer = lexer
+	}
+}
+
+// WithDiffDesignTokens applies design-system colors to syntax highlighting
+func WithDiffDesignTokens(tokens *design.DesignTokens) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.syntax = SyntaxThemeFromTokens(tokens)
+	}
+}
+
+// WithDiffTheme applies a named design-system theme to syntax highlighting
+func WithDiffTheme(theme string) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.syntax = SyntaxThemeFromTokens(designTokensForTheme(theme))
+	}
+}
+
 // WithDiffExpanded sets whether the block starts expanded
 func WithDiffExpanded(expanded bool) DiffBlockOption {
 	return func(db *DiffBlock) {
@@ -81,13 +154,62 @@ func WithDiffExpanded(expanded bool) DiffBlockOption {
 	}
 }
 
-// WithDiffContext sets number of context lines
+// WithDiffContext sets the number of unchanged lines kept around each change.
+// Unchanged regions farther from a change are collapsed between hunks; a
+// negative value shows every line.
 func WithDiffContext(n int) DiffBlockOption {
 	return func(db *DiffBlock) {
 		db.showContext = n
 	}
 }
 
+// WithDiffAlgorithm sets the algorithm used by NewDiffBlockFromStrings
+func WithDiffAlgorithm(algorithm DiffAlgorithm) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.algorithm = algorithm
+	}
+}
+
+// WithDiffViewMode sets the initial layout (unified or split)
+func WithDiffViewMode(mode DiffViewMode) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.viewMode = mode
+	}
+}
+
+// WithDiffSplitMinWidth sets the narrowest width at which split mode is used
+func WithDiffSplitMinWidth(width int) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.splitMin = width
+	}
+}
+
+// WithDiffWordHighlight sets whether changed words inside modified lines are
+// emphasized. A removed line and the added line that replaces it are compared
+// word by word, and the differing words are shown in reverse video.
+func WithDiffWordHighlight(enabled bool) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.wordDiff = enabled
+	}
+}
+
+// WithDiffStaging enables interactive hunk staging, like `git add -p`. While
+// focused, j/k move between hunks, y accepts, n rejects, e edits and u resets
+// the selected hunk; A and R accept or reject every hunk.
+func WithDiffStaging(enabled bool) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.staging = enabled
+	}
+}
+
+// WithDiffHunkEditor sets the function called when the user presses e on a
+// hunk. It typically opens an editor and later calls EditHunk with the result.
+func WithDiffHunkEditor(fn func(index int, hunk DiffHunk) tea.Cmd) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.onEdit = fn
+	}
+}
+
 // WithDiffMaxLines sets maximum lines to show
 func WithDiffMaxLines(max int) DiffBlockOption {
 	return func(db *DiffBlock) {
@@ -95,20 +217,57 @@ func WithDiffMaxLines(max int) DiffBlockOption {
 	}
 }
 
+// WithDiffKeyMap replaces the default keymap
+func WithDiffKeyMap(km *KeyMap) DiffBlockOption {
+	return func(db *DiffBlock) {
+		db.keys = km
+	}
+}
+
+// diffStagingActions are the DiffBlock actions that only apply while staging
+var diffStagingActions = []string{"next-hunk", "prev-hunk", "accept", "reject", "reset", "accept-all", "reject-all", "edit"}
+
+// DefaultDiffBlockKeyMap returns the default DiffBlock bindings for the
+// actions "toggle" and "view-mode", and the staging actions "next-hunk",
+// "prev-hunk", "accept", "reject", "reset", "accept-all", "reject-all" and
+// "edit"
+func DefaultDiffBlockKeyMap() *KeyMap {
+	km := NewKeyMap("DiffBlock")
+	km.Bind("next-hunk", key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("j", "next hunk")))
+	km.Bind("prev-hunk", key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("k", "previous hunk")))
+	km.Bind("accept", key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "accept")))
+	km.Bind("reject", key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "reject")))
+	km.Bind("reset", key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo")))
+	km.Bind("accept-all", key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "accept all")))
+	km.Bind("reject-all", key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "reject all")))
+	km.Bind("edit", key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")))
+	km.Bind("toggle", key.NewBinding(key.WithKeys("ctrl+o", "enter", " "), key.WithHelp("ctrl+o", "expand")))
+	km.Bind("view-mode", key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "split view")))
+	return km
+}
+
 // NewDiffBlock creates a new diff block component
 func NewDiffBlock(opts ...DiffBlockOption) *DiffBlock {
 	db := &DiffBlock{
 		operation:   "Edit",
 		showContext: 3,
+		splitMin:    100,
+		wordDiff:    true,
 		expanded:    false,
 		oldStart:    1,
 		newStart:    1,
+		keys:        DefaultDiffBlockKeyMap(),
 	}
 
 	for _, opt := range opts {
 		opt(db)
 	}
 
+	// Staging keys are only listed and matched while staging
+	for _, action := range diffStagingActions {
+		db.keys.SetEnabled(action, db.staging)
+	}
+
 	return db
 }
 
@@ -117,69 +276,11 @@ func NewDiffBlockFromStrings(old, new string, opts ...DiffBlockOption) *DiffBloc
 	oldLines := strings.Split(old, "\n")
 	newLines := strings.Split(new, "\n")
 
-	// Simple line-by-line diff (can be enhanced with proper diff algorithm)
-	diffLines := simpleDiff(oldLines, newLines)
-
 	db := NewDiffBlock(opts...)
-	db.lines = diffLines
+	db.lines = ComputeDiff(oldLines, newLines, db.algorithm)
 	return db
 }
 
-// simpleDiff creates a simple line-by-line diff
-func simpleDiff(oldLines, newLines []string) []DiffLine {
-	var result []DiffLine
-
-	// Find common prefix
-	commonPrefix := 0
-	for commonPrefix < len(oldLines) && commonPrefix < len(newLines) && oldLines[commonPrefix] == newLines[commonPrefix] {
-		result = append(result, DiffLine{
-			Type:    DiffUnchanged,
-			Content: oldLines[commonPrefix],
-			LineNum: commonPrefix + 1,
-		})
-		commonPrefix++
-	}
-
-	// Find common suffix
-	commonSuffix := 0
-	oldRemaining := len(oldLines) - commonPrefix
-	newRemaining := len(newLines) - commonPrefix
-	for commonSuffix < oldRemaining && commonSuffix < newRemaining &&
-		oldLines[len(oldLines)-1-commonSuffix] == newLines[len(newLines)-1-commonSuffix] {
-		commonSuffix++
-	}
-
-	// Add removed lines
-	for i := commonPrefix; i < len(oldLines)-commonSuffix; i++ {
-		result = append(result, DiffLine{
-			Type:    DiffRemoved,
-			Content: oldLines[i],
-			LineNum: i + 1,
-		})
-	}
-
-	// Add added lines
-	for i := commonPrefix; i < len(newLines)-commonSuffix; i++ {
-		result = append(result, DiffLine{
-			Type:    DiffAdded,
-			Content: newLines[i],
-			LineNum: i + 1,
-		})
-	}
-
-	// Add common suffix
-	for i := 0; i < commonSuffix; i++ {
-		idx := len(oldLines) - commonSuffix + i
-		result = append(result, DiffLine{
-			Type:    DiffUnchanged,
-			Content: oldLines[idx],
-			LineNum: idx + 1,
-		})
-	}
-
-	return result
-}
-
 // Init initializes the diff block
 func (db *DiffBlock) Init() tea.Cmd {
 	return nil
@@ -197,9 +298,31 @@ func (db *DiffBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return db, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+o", "enter", " ":
+		action, _ := db.keys.Lookup(msg)
+		if db.staging {
+			if handled, cmd := db.handleStagingKey(action); handled {
+				return db, cmd
+			}
+		}
+
+		switch action {
+		case "toggle":
 			db.Toggle()
+		case "view-mode":
+			db.ToggleViewMode()
+		}
+
+	case tea.MouseMsg:
+		if !isLeftClick(msg) {
+			return db, nil
+		}
+		if zone, ok := db.hits.Test(msg.X, msg.Y); ok {
+			switch zone.ID {
+			case "header":
+				db.Toggle()
+			case "hunk":
+				db.current = zone.Index
+			}
 		}
 	}
 
@@ -220,6 +343,8 @@ func (db *DiffBlock) View() string {
 	if db.filename != "" {
 		b.WriteString(fmt.Sprintf("(\033[36m%s\033[0m)", db.filename))
 	}
+	db.hits.Reset()
+	db.hits.Add("header", 0, Bounds{X: 0, Y: 0, Width: textwidth.Width(b.String()), Height: 1})
 	b.WriteString("\n")
 
 	// Summary line with stats
@@ -272,6 +397,26 @@ func (db *DiffBlock) Collapse() {
 	db.expanded = false
 }
 
+// ToggleViewMode switches between the unified and split layouts
+func (db *DiffBlock) ToggleViewMode() {
+	if db.viewMode == DiffViewSplit {
+		db.viewMode = DiffViewUnified
+	} else {
+		db.viewMode = DiffViewSplit
+	}
+}
+
+// ViewMode returns the selected layout. The block renders unified while it is
+// narrower than the split minimum width, whatever the selected mode.
+func (db *DiffBlock) ViewMode() DiffViewMode {
+	return db.viewMode
+}
+
+// splitActive reports whether the split layout is used at the current width
+func (db *DiffBlock) splitActive() bool {
+	return db.viewMode == DiffViewSplit && db.width >= db.splitMin
+}
+
 // IsExpanded returns whether the diff is currently expanded
 func (db *DiffBlock) IsExpanded() bool {
 	return db.expanded
@@ -290,99 +435,286 @@ func (db *DiffBlock) countChanges() (added, removed int) {
 	return
 }
 
-// renderCollapsed shows first few changes with context
-func (db *DiffBlock) renderCollapsed() string {
-	var b strings.Builder
+// Hunks returns the changes grouped into hunks using the block's context setting
+func (db *DiffBlock) Hunks() []DiffHunk {
+	numbered := numberDiffLines(db.lines, db.oldStart, db.newStart)
+	return buildHunks(numbered, hunkSpans(numbered, db.showContext))
+}
 
-	// Show first few changes with surrounding context
-	shownLines := 0
-	maxPreview := 15 // Show more lines to include context
+// renderCollapsed shows the first few hunks
+func (db *DiffBlock) renderCollapsed() string {
+	maxPreview := 15 // Show enough lines to include context
+	hint := "(\033[3m" + db.keys.hint("toggle") + " to expand\033[0m\033[2m)"
+	return db.renderHunks(maxPreview, hint)
+}
 
-	// Track whether we've shown a change yet
-	hasChanges := false
+// renderExpanded shows every hunk, up to maxLines diff lines
+func (db *DiffBlock) renderExpanded() string {
+	return db.renderHunks(db.maxLines, "(truncated)")
+}
 
-	for i, line := range db.lines {
-		// Show context before/after changes
-		if line.Type == DiffUnchanged {
-			// Only show context if we're near a change
-			showContext := false
-
-			// Check if there's a change within N lines
-			contextWindow := 2
-			for j := i - contextWindow; j <= i+contextWindow; j++ {
-				if j >= 0 && j < len(db.lines) && db.lines[j].Type != DiffUnchanged {
-					showContext = true
-					break
-				}
-			}
+// renderHunks renders hunks until limit rows have been shown (0 = no limit),
+// followed by a "more lines" hint. Hunk headers are shown whenever unchanged
+// lines were collapsed.
+func (db *DiffBlock) renderHunks(limit int, hint string) string {
+	var b strings.Builder
 
-			if !showContext && hasChanges {
-				// We've moved past the changes, stop
-				break
-			}
+	numbered := numberDiffLines(db.lines, db.oldStart, db.newStart)
+	spans := hunkSpans(numbered, db.showContext)
+	hunks := buildHunks(numbered, spans)
+	collapsed := len(spans) != 1 || spans[0].end-spans[0].start != len(db.lines)
+	contents, styled := db.highlightContents()
+	if db.wordDiff {
+		db.emphasizeWordChanges(contents)
+	}
 
-			if !showContext {
-				continue
-			}
+	rows := make([][]string, len(spans))
+	total, shown := 0, 0
+	for h, span := range spans {
+		if db.splitActive() {
+			rows[h] = db.splitRows(numbered, contents, styled, span)
 		} else {
-			hasChanges = true
+			rows[h] = db.unifiedRows(contents, styled, span)
 		}
+		total += len(rows[h])
+	}
 
-		if shownLines >= maxPreview {
+	for h := range spans {
+		if limit > 0 && shown >= limit {
 			break
 		}
+		if db.staging {
+			// The block header and summary occupy the first two rows
+			row := 2 + strings.Count(b.String(), "\n")
+			db.hits.Add("hunk", h, Bounds{X: 0, Y: row, Width: max(db.width, 1), Height: 1})
+			b.WriteString(db.renderStagingHeader(h, hunks[h]))
+		} else if collapsed {
+			b.WriteString(fmt.Sprintf("     \033[36m%s\033[0m\n", hunks[h].Header()))
+		}
+		for _, row := range rows[h] {
+			if limit > 0 && shown >= limit {
+				break
+			}
+			b.WriteString(row)
+			shown++
+		}
+	}
 
-		b.WriteString(db.renderDiffLine(line))
-		shownLines++
+	// Show "… more lines" if truncated
+	if shown < total {
+		b.WriteString(fmt.Sprintf("     \033[2m… +%d more lines %s\033[0m\n", total-shown, hint))
 	}
 
-	// Show expansion hint if there are more lines
-	if len(db.lines) > shownLines {
-		remaining := len(db.lines) - shownLines
-		b.WriteString(fmt.Sprintf("     \033[2m… +%d more lines (\033[3mctrl+o to expand\033[0m\033[2m)\033[0m\n", remaining))
+	if db.staging && db.focused {
+		b.WriteString(fmt.Sprintf("     \033[2m%s accept · %s reject · %s edit · %s undo · %s/%s move\033[0m\n",
+			db.keys.hint("accept"), db.keys.hint("reject"), db.keys.hint("edit"),
+			db.keys.hint("reset"), db.keys.hint("next-hunk"), db.keys.hint("prev-hunk")))
 	}
 
 	return b.String()
 }
 
-// renderExpanded shows the full diff with context
-func (db *DiffBlock) renderExpanded() string {
-	var b strings.Builder
+// unifiedRows renders the lines of a span as a single +/- column
+func (db *DiffBlock) unifiedRows(contents []string, styled bool, span hunkSpan) []string {
+	rows := make([]string, 0, span.end-span.start)
+	for i := span.start; i < span.end; i++ {
+		row := db.renderDiffLine(db.lines[i], contents[i], styled)
+		if db.lines[i].NoNewline {
+			row += "         \033[2m\\ No newline at end of file\033[0m\n"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
 
-	linesToShow := len(db.lines)
-	if db.maxLines > 0 && linesToShow > db.maxLines {
-		linesToShow = db.maxLines
+// splitRows renders the lines of a span side by side. Unchanged lines appear
+// on both sides; within each change, removed lines are paired with added lines
+// in order and the shorter side is padded with blank cells.
+func (db *DiffBlock) splitRows(numbered []DiffLine, contents []string, styled bool, span hunkSpan) []string {
+	// "  " margin + left cell + "│ " + right cell
+	leftWidth := (db.width - 4) / 2
+	rightWidth := db.width - 4 - leftWidth
+
+	maxNum := 0
+	for _, line := range numbered[span.start:span.end] {
+		maxNum = max(maxNum, max(line.OldLineNum, line.NewLineNum))
 	}
+	numWidth := len(fmt.Sprintf("%d", maxNum))
 
-	for i := 0; i < linesToShow; i++ {
-		b.WriteString(db.renderDiffLine(db.lines[i]))
+	row := func(left, right int) string {
+		return "  " + db.splitCell(numbered, contents, styled, left, DiffRemoved, numWidth, leftWidth) +
+			"\033[2m│\033[0m " + db.splitCell(numbered, contents, styled, right, DiffAdded, numWidth, rightWidth) + "\n"
 	}
 
-	// Show "… more lines" if truncated
-	if db.maxLines > 0 && len(db.lines) > db.maxLines {
-		remaining := len(db.lines) - db.maxLines
-		b.WriteString(fmt.Sprintf("     \033[2m… +%d more lines (truncated)\033[0m\n", remaining))
+	var rows []string
+	for i := span.start; i < span.end; {
+		if db.lines[i].Type == DiffUnchanged {
+			rows = append(rows, row(i, i))
+			i++
+			continue
+		}
+
+		removedStart := i
+		for i < span.end && db.lines[i].Type == DiffRemoved {
+			i++
+		}
+		addedStart := i
+		for i < span.end && db.lines[i].Type == DiffAdded {
+			i++
+		}
+		removed, added := addedStart-removedStart, i-addedStart
+		for k := 0; k < max(removed, added); k++ {
+			left, right := -1, -1
+			if k < removed {
+				left = removedStart + k
+			}
+			if k < added {
+				right = addedStart + k
+			}
+			rows = append(rows, row(left, right))
+		}
 	}
+	return rows
+}
 
-	return b.String()
+// splitCell renders line i as one side of a split row, exactly width columns
+// wide. side is DiffRemoved for the old (left) side and DiffAdded for the new
+// (right) side; i < 0 renders an empty cell. styled reports whether contents
+// carry syntax colors.
+func (db *DiffBlock) splitCell(numbered []DiffLine, contents []string, styled bool, i int, side DiffType, numWidth, width int) string {
+	if i < 0 {
+		return textwidth.Fit("", width)
+	}
+
+	line := numbered[i]
+	num := line.NewLineNum
+	if side == DiffRemoved {
+		num = line.OldLineNum
+	}
+	content := strings.ReplaceAll(contents[i], "\t", "    ")
+	if line.NoNewline {
+		content += " \033[2m(no newline)\033[0m"
+	}
+
+	var cell string
+	switch {
+	case line.Type == DiffUnchanged:
+		cell = fmt.Sprintf("%*d  %s", numWidth, num, content)
+	case styled:
+		// Highlighted content carries its own colors, so only the sign is tinted
+		cell = fmt.Sprintf("%*d %s\033[0m%s", numWidth, num, diffSign(line.Type), content)
+	default:
+		cell = fmt.Sprintf("%*d %s%s\033[0m", numWidth, num, diffSign(line.Type), content)
+	}
+	return textwidth.Fit(cell, width)
+}
+
+// diffSign returns the colored +/- sign for a changed line, leaving the color
+// active for the content that follows
+func diffSign(t DiffType) string {
+	if t == DiffAdded {
+		return "\033[32m+"
+	}
+	return "\033[31m-"
+}
+
+// highlightContents returns the content of every diff line with syntax
+// highlighting applied, and whether highlighting was applied. The old and new
+// sides are tokenized separately so a comment or string opened on a removed
+// line does not leak into added lines. Without a lexer the contents are
+// returned as-is.
+func (db *DiffBlock) highlightContents() ([]string, bool) {
+	contents := make([]string, len(db.lines))
+	lexer := db.lexer
+	if lexer == nil {
+		lexer = LexerFor(db.language)
+	}
+	if lexer == nil {
+		for i, line := range db.lines {
+			contents[i] = line.Content
+		}
+		return contents, false
+	}
+
+	theme := db.syntax
+	if theme == nil {
+		theme = defaultSyntaxTheme()
+	}
+
+	var oldState, newState LexerState
+	for i, line := range db.lines {
+		switch line.Type {
+		case DiffRemoved:
+			contents[i], oldState = highlightLine(line.Content, lexer, theme, oldState)
+		case DiffAdded:
+			contents[i], newState = highlightLine(line.Content, lexer, theme, newState)
+		default:
+			contents[i], newState = highlightLine(line.Content, lexer, theme, newState)
+			oldState = newState
+		}
+	}
+	return contents, true
+}
+
+// emphasizeWordChanges pairs each removed line with the added line in the same
+// position of its change block and, when the two are similar, emphasizes the
+// words that differ in both
+func (db *DiffBlock) emphasizeWordChanges(contents []string) {
+	for i := 0; i < len(db.lines); {
+		if db.lines[i].Type != DiffRemoved {
+			i++
+			continue
+		}
+
+		removedStart := i
+		for i < len(db.lines) && db.lines[i].Type == DiffRemoved {
+			i++
+		}
+		addedStart := i
+		for i < len(db.lines) && db.lines[i].Type == DiffAdded {
+			i++
+		}
+
+		pairs := min(addedStart-removedStart, i-addedStart)
+		for k := 0; k < pairs; k++ {
+			old, new := removedStart+k, addedStart+k
+			oldRanges, newRanges, ok := wordChanges(db.lines[old].Content, db.lines[new].Content)
+			if !ok {
+				continue
+			}
+			contents[old] = emphasizeRanges(contents[old], oldRanges)
+			contents[new] = emphasizeRanges(contents[new], newRanges)
+		}
+	}
 }
 
-// renderDiffLine renders a single diff line with line numbers (unified diff format)
-func (db *DiffBlock) renderDiffLine(line DiffLine) string {
+// renderDiffLine renders a single diff line with line numbers (unified diff format).
+// content is the line's text; styled reports whether it carries syntax colors.
+func (db *DiffBlock) renderDiffLine(line DiffLine, content string, styled bool) string {
 	// Line number format: right-aligned, 6 chars wide total
 	lineNumStr := fmt.Sprintf("%6d", line.LineNum)
 
+	if styled {
+		// Highlighted content carries its own colors, so only the sign is tinted
+		switch line.Type {
+		case DiffAdded:
+			return fmt.Sprintf("  %s \033[32m+\033[0m%s\n", lineNumStr, content)
+		case DiffRemoved:
+			return fmt.Sprintf("  %s \033[31m-\033[0m%s\n", lineNumStr, content)
+		}
+	}
+
 	switch line.Type {
 	case DiffAdded:
 		// Green + with line number: "    22 +    content"
-		return fmt.Sprintf("  %s \033[32m+%s\033[0m\n", lineNumStr, line.Content)
+		return fmt.Sprintf("  %s \033[32m+%s\033[0m\n", lineNumStr, content)
 	case DiffRemoved:
 		// Red - with line number: "    22 -    content"
-		return fmt.Sprintf("  %s \033[31m-%s\033[0m\n", lineNumStr, line.Content)
+		return fmt.Sprintf("  %s \033[31m-%s\033[0m\n", lineNumStr, content)
 	case DiffUnchanged:
 		// No prefix, just line number: "    22     content"
-		return fmt.Sprintf("  %s  %s\n", lineNumStr, line.Content)
+		return fmt.Sprintf("  %s  %s\n", lineNumStr, content)
 	default:
-		return fmt.Sprintf("        %s\n", line.Content)
+		return fmt.Sprintf("        %s\n", content)
 	}
 }
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -540,3 +542,157 @@ func TestDiffBlockLineNumbering(t *testing.T) {
 		}
 	}
 }
+
+// TestDiffBlockCollapsesUnchangedRegions tests that context limits the rendered lines
+func TestDiffBlockCollapsesUnchangedRegions(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 30; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+	}
+	newLines := append([]string(nil), oldLines...)
+	newLines[1] = "first change"
+	newLines[25] = "second change"
+
+	db := NewDiffBlockFromStrings(
+		strings.Join(oldLines, "\n"),
+		strings.Join(newLines, "\n"),
+		WithDiffContext(1),
+		WithDiffExpanded(true),
+	)
+	view := db.View()
+
+	if !strings.Contains(view, "@@ -1,3 +1,3 @@") || !strings.Contains(view, "@@ -25,3 +25,3 @@") {
+		t.Errorf("Expanded view should show a header per hunk:\n%s", view)
+	}
+	if strings.Contains(view, "line 14") {
+		t.Error("Unchanged lines far from changes should be collapsed")
+	}
+	if len(db.Hunks()) != 2 {
+		t.Errorf("Expected 2 hunks, got %d", len(db.Hunks()))
+	}
+
+	// Negative context shows the whole file without headers
+	full := NewDiffBlockFromStrings(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"),
+		WithDiffContext(-1), WithDiffExpanded(true)).View()
+	if !strings.Contains(full, "line 14") || strings.Contains(full, "@@") {
+		t.Error("Negative context should render every line without hunk headers")
+	}
+}
+
+// TestDiffBlockWithDiffAlgorithm tests the algorithm option
+func TestDiffBlockWithDiffAlgorithm(t *testing.T) {
+	db := NewDiffBlockFromStrings("a\nb", "b\na", WithDiffAlgorithm(DiffPatience))
+
+	if db.algorithm != DiffPatience {
+		t.Errorf("Expected patience algorithm, got %d", db.algorithm)
+	}
+	if added, removed := db.countChanges(); added != 1 || removed != 1 {
+		t.Errorf("Expected 1 added and 1 removed line, got %d and %d", added, removed)
+	}
+}
+
+// TestDiffBlockSplitView tests side-by-side rendering on wide terminals
+func TestDiffBlockSplitView(t *testing.T) {
+	db := NewDiffBlockFromStrings(
+		"keep\nold one\nold two\nend",
+		"keep\nnew one\nend",
+		WithDiffViewMode(DiffViewSplit),
+		WithDiffExpanded(true),
+	)
+	db.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	lines := strings.Split(textwidth.Strip(db.View()), "\n")
+
+	var paired, unpaired string
+	for _, line := range lines {
+		if strings.Contains(line, "old one") {
+			paired = line
+		}
+		if strings.Contains(line, "old two") {
+			unpaired = line
+		}
+	}
+
+	if !strings.Contains(paired, "│") || !strings.Contains(paired, "new one") {
+		t.Errorf("Removed and added lines should share a row, got %q", paired)
+	}
+	left, right, _ := strings.Cut(unpaired, "│")
+	if !strings.Contains(left, "-old two") || strings.TrimSpace(right) != "" {
+		t.Errorf("Unpaired removal should leave the right side blank, got %q", unpaired)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "│") && len([]rune(line)) != 120 {
+			t.Errorf("Split rows should fill the width, got %d columns: %q", len([]rune(line)), line)
+		}
+	}
+}
+
+// TestDiffBlockSplitViewLineNumbers tests that each side shows its own numbers
+func TestDiffBlockSplitViewLineNumbers(t *testing.T) {
+	db := NewDiffBlockFromStrings("a\nb\nc", "a\nx\ny\nc", WithDiffViewMode(DiffViewSplit), WithDiffExpanded(true))
+	db.Update(tea.WindowSizeMsg{Width: 100})
+
+	for _, line := range strings.Split(textwidth.Strip(db.View()), "\n") {
+		if !strings.Contains(line, " c") || !strings.Contains(line, "│") {
+			continue
+		}
+		left, right, _ := strings.Cut(line, "│")
+		if !strings.Contains(left, "3  c") || !strings.Contains(right, "4  c") {
+			t.Errorf("Expected old line 3 and new line 4 for c, got %q", line)
+		}
+	}
+}
+
+// TestDiffBlockSplitFallsBackWhenNarrow tests the unified fallback
+func TestDiffBlockSplitFallsBackWhenNarrow(t *testing.T) {
+	db := NewDiffBlockFromStrings("old", "new",
+		WithDiffViewMode(DiffViewSplit), WithDiffSplitMinWidth(100), WithDiffExpanded(true))
+	db.Update(tea.WindowSizeMsg{Width: 80})
+
+	if strings.Contains(db.View(), "│") {
+		t.Error("Narrow blocks should render unified")
+	}
+	if db.ViewMode() != DiffViewSplit {
+		t.Error("Fallback should not change the selected mode")
+	}
+
+	db.Update(tea.WindowSizeMsg{Width: 100})
+	if !strings.Contains(db.View(), "│") {
+		t.Error("Block at the minimum width should render split")
+	}
+}
+
+// TestDiffBlockToggleViewModeKey tests switching modes with the keyboard
+func TestDiffBlockToggleViewModeKey(t *testing.T) {
+	db := NewDiffBlockFromStrings("old", "new")
+	db.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if db.ViewMode() != DiffViewUnified {
+		t.Error("Unfocused block should ignore the toggle key")
+	}
+
+	db.Focus()
+	db.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if db.ViewMode() != DiffViewSplit {
+		t.Error("s should switch to split mode")
+	}
+	db.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if db.ViewMode() != DiffViewUnified {
+		t.Error("s should switch back to unified mode")
+	}
+}
+
+// TestDiffBlockWordHighlight tests emphasis of changed words in paired lines
+func TestDiffBlockWordHighlight(t *testing.T) {
+	db := NewDiffBlockFromStrings("x := compute(a)", "x := compute(b)", WithDiffExpanded(true))
+	view := db.View()
+
+	if !strings.Contains(view, "\033[7ma\033[27m") || !strings.Contains(view, "\033[7mb\033[27m") {
+		t.Errorf("Changed words should be emphasized:\n%q", view)
+	}
+
+	plain := NewDiffBlockFromStrings("x := compute(a)", "x := compute(b)",
+		WithDiffExpanded(true), WithDiffWordHighlight(false)).View()
+	if strings.Contains(plain, "\033[7m") {
+		t.Error("Word highlighting should be disabled by the option")
+	}
+}
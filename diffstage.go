@@ -0,0 +1,348 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HunkDecision records what the user chose for a hunk while staging
+type HunkDecision int
+
+const (
+	HunkPending  HunkDecision = iota // Not decided yet; treated as rejected in results
+	HunkAccepted                     // Apply the hunk as shown
+	HunkRejected                     // Keep the old lines
+	HunkEdited                       // Apply the lines given to EditHunk instead
+)
+
+// handleStagingKey handles hunk navigation and decisions. It reports whether
+// the action was used so other bindings can still run.
+func (db *DiffBlock) handleStagingKey(action string) (bool, tea.Cmd) {
+	hunks := db.Hunks()
+	if len(hunks) == 0 {
+		return false, nil
+	}
+
+	switch action {
+	case "next-hunk":
+		db.NextHunk()
+	case "prev-hunk":
+		db.PrevHunk()
+	case "accept":
+		db.AcceptHunk(db.current)
+		db.NextHunk()
+	case "reject":
+		db.RejectHunk(db.current)
+		db.NextHunk()
+	case "reset":
+		db.ResetHunk(db.current)
+	case "accept-all":
+		db.AcceptAll()
+	case "reject-all":
+		db.RejectAll()
+	case "edit":
+		if db.onEdit != nil {
+			return true, db.onEdit(db.current, hunks[db.current])
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// CurrentHunk returns the index of the selected hunk
+func (db *DiffBlock) CurrentHunk() int {
+	return db.current
+}
+
+// NextHunk selects the next hunk, expanding the block so it is visible
+func (db *DiffBlock) NextHunk() {
+	db.selectHunk(db.current + 1)
+}
+
+// PrevHunk selects the previous hunk
+func (db *DiffBlock) PrevHunk() {
+	db.selectHunk(db.current - 1)
+}
+
+// selectHunk selects hunk i, clamped to the valid range
+func (db *DiffBlock) selectHunk(i int) {
+	n := len(db.Hunks())
+	if n == 0 {
+		return
+	}
+	db.current = max(0, min(i, n-1))
+	db.expanded = true
+}
+
+// AcceptHunk marks hunk i to be applied
+func (db *DiffBlock) AcceptHunk(i int) {
+	db.decide(i, HunkAccepted)
+}
+
+// RejectHunk marks hunk i to be left out
+func (db *DiffBlock) RejectHunk(i int) {
+	db.decide(i, HunkRejected)
+}
+
+// ResetHunk clears the decision and any edit for hunk i
+func (db *DiffBlock) ResetHunk(i int) {
+	delete(db.decisions, i)
+	delete(db.edits, i)
+}
+
+// EditHunk replaces what hunk i turns into: the hunk's old lines (context and
+// removed) become lines in the result.
+func (db *DiffBlock) EditHunk(i int, lines []string) {
+	if i < 0 || i >= len(db.Hunks()) {
+		return
+	}
+	if db.edits == nil {
+		db.edits = make(map[int][]string)
+	}
+	db.edits[i] = append([]string(nil), lines...)
+	db.decide(i, HunkEdited)
+}
+
+// AcceptAll accepts every hunk that has not been edited
+func (db *DiffBlock) AcceptAll() {
+	db.decideAll(HunkAccepted)
+}
+
+// RejectAll rejects every hunk that has not been edited
+func (db *DiffBlock) RejectAll() {
+	db.decideAll(HunkRejected)
+}
+
+// Decision returns the decision for hunk i
+func (db *DiffBlock) Decision(i int) HunkDecision {
+	return db.decisions[i]
+}
+
+// AllDecided reports whether every hunk has been accepted, rejected or edited
+func (db *DiffBlock) AllDecided() bool {
+	for i := range db.Hunks() {
+		if db.decisions[i] == HunkPending {
+			return false
+		}
+	}
+	return true
+}
+
+// decide records a decision for hunk i
+func (db *DiffBlock) decide(i int, d HunkDecision) {
+	if i < 0 || i >= len(db.Hunks()) {
+		return
+	}
+	if db.decisions == nil {
+		db.decisions = make(map[int]HunkDecision)
+	}
+	if d != HunkEdited {
+		delete(db.edits, i)
+	}
+	db.decisions[i] = d
+}
+
+// decideAll records a decision for every hunk that has not been edited
+func (db *DiffBlock) decideAll(d HunkDecision) {
+	for i := range db.Hunks() {
+		if db.decisions[i] != HunkEdited {
+			db.decide(i, d)
+		}
+	}
+}
+
+// PatchedLines returns the text with accepted and edited hunks applied and
+// all other hunks left as they were. The result covers the lines held by the
+// block, so it is the complete new file for blocks built with
+// NewDiffBlockFromStrings; for blocks parsed from a patch use ApplyTo.
+func (db *DiffBlock) PatchedLines() []string {
+	numbered := numberDiffLines(db.lines, db.oldStart, db.newStart)
+	spans := hunkSpans(numbered, db.showContext)
+
+	var out []string
+	i := 0
+	for h, span := range spans {
+		for ; i < span.start; i++ {
+			out = append(out, db.lines[i].Content)
+		}
+		if db.decisions[h] == HunkEdited {
+			out = append(out, db.edits[h]...)
+			i = span.end
+			continue
+		}
+		keep := DiffAdded // Side dropped when the hunk is not applied
+		if db.decisions[h] == HunkAccepted {
+			keep = DiffRemoved
+		}
+		for ; i < span.end; i++ {
+			if db.lines[i].Type != keep {
+				out = append(out, db.lines[i].Content)
+			}
+		}
+	}
+	for ; i < len(db.lines); i++ {
+		out = append(out, db.lines[i].Content)
+	}
+	return out
+}
+
+// PatchedText returns PatchedLines joined with newlines
+func (db *DiffBlock) PatchedText() string {
+	return strings.Join(db.PatchedLines(), "\n")
+}
+
+// AcceptedHunks returns the hunks to apply: accepted hunks as they are and
+// edited hunks rewritten against their replacement lines. New line numbers
+// account for hunks that were left out.
+func (db *DiffBlock) AcceptedHunks() []DiffHunk {
+	var out []DiffHunk
+	delta := 0 // New minus old lines of the hunks applied so far
+	for i, hunk := range db.Hunks() {
+		switch db.decisions[i] {
+		case HunkAccepted:
+		case HunkEdited:
+			hunk = editedHunk(hunk, db.edits[i])
+		default:
+			continue
+		}
+
+		hunk.NewStart = hunk.OldStart + delta
+		switch {
+		case hunk.OldLines == 0 && hunk.NewLines > 0:
+			hunk.NewStart++ // Old start is the line before an insertion
+		case hunk.OldLines > 0 && hunk.NewLines == 0:
+			hunk.NewStart-- // New start is the line before a deletion
+		}
+		delta += hunk.NewLines - hunk.OldLines
+		out = append(out, hunk)
+	}
+	return out
+}
+
+// editedHunk rewrites a hunk so its old lines become lines
+func editedHunk(hunk DiffHunk, lines []string) DiffHunk {
+	var oldSide []string
+	firstOld := hunk.OldStart + 1 // Insertion hunks start after OldStart
+	for _, line := range hunk.Lines {
+		if line.Type != DiffAdded {
+			if len(oldSide) == 0 {
+				firstOld = line.OldLineNum
+			}
+			oldSide = append(oldSide, line.Content)
+		}
+	}
+
+	diff := ComputeDiff(oldSide, lines, DiffMyers)
+	for i := range diff {
+		if diff[i].Type != DiffAdded {
+			diff[i].OldLineNum += firstOld - 1
+		}
+	}
+	return DiffHunk{
+		OldStart: hunk.OldStart,
+		OldLines: len(oldSide),
+		NewLines: len(lines),
+		Lines:    diff,
+	}
+}
+
+// AcceptedPatch returns a unified diff containing only the accepted and
+// edited hunks, suitable for `git apply`. It is empty when nothing was
+// accepted.
+func (db *DiffBlock) AcceptedPatch() string {
+	hunks := db.AcceptedHunks()
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if db.filename != "" {
+		oldName, newName := db.filename, db.filename
+		if before, after, ok := strings.Cut(db.filename, " → "); ok {
+			oldName, newName = before, after
+		}
+		b.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", oldName, newName))
+	}
+
+	for _, hunk := range hunks {
+		b.WriteString(hunk.Header())
+		b.WriteString("\n")
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case DiffAdded:
+				b.WriteString("+")
+			case DiffRemoved:
+				b.WriteString("-")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(line.Content)
+			b.WriteString("\n")
+			if line.NoNewline {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// ApplyTo applies the accepted and edited hunks to the original text of the
+// file. It fails if a hunk's old lines do not match the text at its position.
+func (db *DiffBlock) ApplyTo(original string) (string, error) {
+	lines := strings.Split(original, "\n")
+
+	var out []string
+	next := 0 // Index of the next original line to copy
+	for _, hunk := range db.AcceptedHunks() {
+		start := hunk.OldStart - 1
+		if hunk.OldLines == 0 {
+			start = hunk.OldStart // Insert after OldStart
+		}
+		if start < next || start+hunk.OldLines > len(lines) {
+			return "", fmt.Errorf("hunk %s does not fit the original text", hunk.Header())
+		}
+
+		out = append(out, lines[next:start]...)
+		pos := start
+		for _, line := range hunk.Lines {
+			if line.Type != DiffAdded {
+				if lines[pos] != line.Content {
+					return "", fmt.Errorf("hunk %s does not apply: line %d is %q, want %q",
+						hunk.Header(), pos+1, lines[pos], line.Content)
+				}
+				pos++
+			}
+			if line.Type != DiffRemoved {
+				out = append(out, line.Content)
+			}
+		}
+		next = pos
+	}
+	out = append(out, lines[next:]...)
+
+	return strings.Join(out, "\n"), nil
+}
+
+// renderStagingHeader renders a hunk header with a selection marker and the
+// hunk's decision
+func (db *DiffBlock) renderStagingHeader(i int, hunk DiffHunk) string {
+	marker := " "
+	if i == db.current && db.focused {
+		marker = "\033[1;36m❯\033[0m"
+	}
+
+	var badge string
+	switch db.decisions[i] {
+	case HunkAccepted:
+		badge = " \033[32m✓ accepted\033[0m"
+	case HunkRejected:
+		badge = " \033[31m✗ rejected\033[0m"
+	case HunkEdited:
+		badge = " \033[33m✎ edited\033[0m"
+	}
+
+	return fmt.Sprintf("   %s \033[36m%s\033[0m%s\n", marker, hunk.Header(), badge)
+}
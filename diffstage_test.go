@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagingBlock returns a focused staging block with two hunks far apart
+func stagingBlock() (*DiffBlock, string, string) {
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, "line "+string(rune('a'+i-1)))
+	}
+	newLines := append([]string(nil), oldLines...)
+	newLines[1] = "first change"
+	newLines = append(newLines[:15], append([]string{"inserted"}, newLines[15:]...)...)
+
+	old, new := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+	db := NewDiffBlockFromStrings(old, new, WithDiffStaging(true), WithDiffContext(1))
+	db.Focus()
+	return db, old, new
+}
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestDiffStagingNavigation(t *testing.T) {
+	db, _, _ := stagingBlock()
+
+	if len(db.Hunks()) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(db.Hunks()))
+	}
+
+	db.Update(runeKey("j"))
+	if db.CurrentHunk() != 1 {
+		t.Errorf("Expected hunk 1 after j, got %d", db.CurrentHunk())
+	}
+	if !db.IsExpanded() {
+		t.Error("Moving between hunks should expand the block")
+	}
+
+	db.Update(runeKey("j"))
+	if db.CurrentHunk() != 1 {
+		t.Error("Selection should stop at the last hunk")
+	}
+
+	db.Update(runeKey("k"))
+	if db.CurrentHunk() != 0 {
+		t.Errorf("Expected hunk 0 after k, got %d", db.CurrentHunk())
+	}
+}
+
+func TestDiffStagingDecisionsByKey(t *testing.T) {
+	db, _, _ := stagingBlock()
+
+	db.Update(runeKey("y"))
+	if db.Decision(0) != HunkAccepted {
+		t.Error("y should accept the selected hunk")
+	}
+	if db.CurrentHunk() != 1 {
+		t.Error("Accepting should advance to the next hunk")
+	}
+
+	db.Update(runeKey("n"))
+	if db.Decision(1) != HunkRejected {
+		t.Error("n should reject the selected hunk")
+	}
+	if !db.AllDecided() {
+		t.Error("All hunks should be decided")
+	}
+
+	db.Update(runeKey("u"))
+	if db.Decision(1) != HunkPending {
+		t.Error("u should reset the selected hunk")
+	}
+
+	view := textwidth.Strip(db.View())
+	if !strings.Contains(view, "✓ accepted") {
+		t.Errorf("View should show hunk decisions:\n%s", view)
+	}
+}
+
+func TestDiffStagingIgnoredWhenDisabled(t *testing.T) {
+	db := NewDiffBlockFromStrings("a", "b")
+	db.Focus()
+	db.Update(runeKey("y"))
+
+	if db.Decision(0) != HunkPending {
+		t.Error("Keys should not stage hunks unless staging is enabled")
+	}
+}
+
+func TestDiffStagingPatchedText(t *testing.T) {
+	db, old, new := stagingBlock()
+
+	if db.PatchedText() != old {
+		t.Error("With nothing accepted the result should be the old text")
+	}
+
+	db.AcceptAll()
+	if db.PatchedText() != new {
+		t.Error("With everything accepted the result should be the new text")
+	}
+
+	db.RejectHunk(0)
+	got := db.PatchedText()
+	if strings.Contains(got, "first change") || !strings.Contains(got, "inserted") {
+		t.Errorf("Only the second hunk should be applied:\n%s", got)
+	}
+}
+
+func TestDiffStagingEditHunk(t *testing.T) {
+	db, _, _ := stagingBlock()
+	db.EditHunk(0, []string{"line a", "my own change", "line c"})
+
+	if db.Decision(0) != HunkEdited {
+		t.Error("EditHunk should mark the hunk as edited")
+	}
+	got := db.PatchedLines()
+	if got[1] != "my own change" || got[2] != "line c" {
+		t.Errorf("Edited lines should replace the hunk, got %v", got[:4])
+	}
+
+	// Accepting all keeps the edit
+	db.AcceptAll()
+	if db.Decision(0) != HunkEdited {
+		t.Error("AcceptAll should not discard edits")
+	}
+}
+
+func TestDiffStagingEditorCallback(t *testing.T) {
+	editedIndex := -1
+	db, _, _ := stagingBlock()
+	db.onEdit = func(index int, hunk DiffHunk) tea.Cmd {
+		editedIndex = index
+		return nil
+	}
+
+	db.Update(runeKey("j"))
+	db.Update(runeKey("e"))
+	if editedIndex != 1 {
+		t.Errorf("Editor should be called for hunk 1, got %d", editedIndex)
+	}
+}
+
+func TestDiffStagingAcceptedPatch(t *testing.T) {
+	db, old, _ := stagingBlock()
+	db.filename = "notes.txt"
+	db.RejectHunk(0)
+	db.AcceptHunk(1)
+
+	patch := db.AcceptedPatch()
+	if !strings.HasPrefix(patch, "--- a/notes.txt\n+++ b/notes.txt\n@@ -15,2 +15,3 @@\n") {
+		t.Errorf("Unexpected sub-patch:\n%s", patch)
+	}
+
+	// The sub-patch round-trips through the parser and applies to the old text
+	blocks, err := NewDiffBlockFromUnified(patch)
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("Sub-patch should parse, got %d blocks (err=%v)", len(blocks), err)
+	}
+	blocks[0].AcceptAll()
+	got, err := blocks[0].ApplyTo(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := db.PatchedText(); got != want {
+		t.Errorf("Applying the sub-patch gave:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffStagingAcceptedPatchShiftsNewLines(t *testing.T) {
+	db, _, _ := stagingBlock()
+	db.EditHunk(0, []string{"line a", "line b", "extra", "line c"})
+	db.AcceptHunk(1)
+
+	hunks := db.AcceptedHunks()
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 accepted hunks, got %d", len(hunks))
+	}
+	if hunks[0].Header() != "@@ -1,3 +1,4 @@" {
+		t.Errorf("Edited hunk header = %q", hunks[0].Header())
+	}
+	if hunks[1].Header() != "@@ -15,2 +16,3 @@" {
+		t.Errorf("Second hunk should shift by the edited hunk's extra line, got %q", hunks[1].Header())
+	}
+}
+
+func TestDiffStagingApplyToZeroContextInsert(t *testing.T) {
+	// As produced by git diff -U0: insertions after lines 6 and 8, and a
+	// deletion of line 10
+	patch := "--- a/f.txt\n+++ b/f.txt\n@@ -6,0 +7 @@\n+new a\n@@ -8,0 +10,2 @@\n+new b\n+new c\n@@ -10 +12,0 @@\n-ten\n"
+	old := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten"
+
+	blocks, err := NewDiffBlockFromUnified(patch)
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("Patch should parse, got %d blocks (err=%v)", len(blocks), err)
+	}
+	db := blocks[0]
+	db.AcceptAll()
+
+	got, err := db.ApplyTo(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "one\ntwo\nthree\nfour\nfive\nsix\nnew a\nseven\neight\nnew b\nnew c\nnine"
+	if got != want {
+		t.Errorf("ApplyTo gave:\n%s\nwant:\n%s", got, want)
+	}
+
+	var headers []string
+	for _, hunk := range db.AcceptedHunks() {
+		headers = append(headers, hunk.Header())
+	}
+	if strings.Join(headers, " ") != "@@ -6,0 +7,1 @@ @@ -8,0 +10,2 @@ @@ -10,1 +12,0 @@" {
+		t.Errorf("Accepted hunk headers = %q", headers)
+	}
+}
+
+func TestDiffStagingApplyToMismatch(t *testing.T) {
+	db, _, _ := stagingBlock()
+	db.AcceptAll()
+
+	if _, err := db.ApplyTo("something else entirely"); err == nil {
+		t.Error("Applying to unrelated text should fail")
+	}
+}
+
+func TestDiffStagingMouseSelectsHunk(t *testing.T) {
+	db, _, _ := stagingBlock()
+	db.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	db.Expand()
+	db.View()
+
+	var second Bounds
+	for _, zone := range db.hits.Zones() {
+		if zone.ID == "hunk" && zone.Index == 1 {
+			second = zone.Bounds
+		}
+	}
+	db.Update(leftClick(4, second.Y))
+
+	if db.CurrentHunk() != 1 {
+		t.Errorf("Clicking a hunk header should select it, got %d", db.CurrentHunk())
+	}
+}
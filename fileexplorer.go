@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -18,6 +20,8 @@ type FileNode struct {
 	Children []*FileNode
 	Expanded bool
 	Parent   *FileNode
+
+	loaded bool // Children were read from disk, even if there were none
 }
 
 // FileExplorer displays a navigable file tree
@@ -32,6 +36,8 @@ type FileExplorer struct {
 	focused       bool
 	showHidden    bool
 	basePath      string
+	keys          *KeyMap
+	hits          HitMap
 }
 
 // FileExplorerOption configures a FileExplorer
@@ -44,6 +50,26 @@ func WithShowHidden(show bool) FileExplorerOption {
 	}
 }
 
+// WithFileExplorerKeyMap replaces the default keymap
+func WithFileExplorerKeyMap(km *KeyMap) FileExplorerOption {
+	return func(fe *FileExplorer) {
+		fe.keys = km
+	}
+}
+
+// DefaultFileExplorerKeyMap returns the default FileExplorer bindings for
+// the actions "up", "down", "collapse", "expand", "hidden" and "refresh"
+func DefaultFileExplorerKeyMap() *KeyMap {
+	km := NewKeyMap("FileExplorer")
+	km.Bind("up", key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")))
+	km.Bind("collapse", key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")))
+	km.Bind("expand", key.NewBinding(key.WithKeys("right", "l", "enter"), key.WithHelp("enter", "open")))
+	km.Bind("hidden", key.NewBinding(key.WithKeys("."), key.WithHelp(".", "toggle hidden")))
+	km.Bind("refresh", key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")))
+	return km
+}
+
 // NewFileExplorer creates a new file explorer starting at the given path
 func NewFileExplorer(path string, opts ...FileExplorerOption) *FileExplorer {
 	absPath, err := filepath.Abs(path)
@@ -55,6 +81,7 @@ func NewFileExplorer(path string, opts ...FileExplorerOption) *FileExplorer {
 		basePath:   absPath,
 		showHidden: false,
 		height:     20, // Default height
+		keys:       DefaultFileExplorerKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -90,26 +117,65 @@ func (fe *FileExplorer) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return fe, nil
 		}
 
-		switch msg.String() {
-		case "up", "k":
+		action, _ := fe.keys.Lookup(msg)
+		switch action {
+		case "up":
 			fe.moveUp()
-		case "down", "j":
+		case "down":
 			fe.moveDown()
-		case "left", "h":
+		case "collapse":
 			fe.collapse()
-		case "right", "l", "enter":
+		case "expand":
 			fe.expand()
-		case ".":
+		case "hidden":
 			fe.showHidden = !fe.showHidden
 			fe.refresh()
-		case "r":
+		case "refresh":
 			fe.refresh()
 		}
+
+	case tea.MouseMsg:
+		fe.handleMouse(msg)
 	}
 
 	return fe, nil
 }
 
+// handleMouse selects the clicked node and scrolls with the wheel. Clicking
+// the already-selected directory toggles it open or closed.
+func (fe *FileExplorer) handleMouse(msg tea.MouseMsg) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		fe.moveUp()
+		return
+	case tea.MouseButtonWheelDown:
+		fe.moveDown()
+		return
+	}
+
+	if !isLeftClick(msg) {
+		return
+	}
+
+	zone, ok := fe.hits.Test(msg.X, msg.Y)
+	if !ok || zone.ID != "node" || zone.Index >= len(fe.visibleNodes) {
+		return
+	}
+
+	node := fe.visibleNodes[zone.Index]
+	if node == fe.selected && node.IsDir {
+		if node.Expanded {
+			fe.collapse()
+		} else {
+			fe.expand()
+		}
+		return
+	}
+
+	fe.selectedIndex = zone.Index
+	fe.selected = node
+}
+
 // View renders the file explorer
 func (fe *FileExplorer) View() string {
 	if fe.width == 0 {
@@ -117,19 +183,13 @@ func (fe *FileExplorer) View() string {
 	}
 
 	var b strings.Builder
+	fe.hits.Reset()
 
 	// Header with current path
 	header := fmt.Sprintf("\033[1m📁 %s\033[0m", fe.basePath)
-	headerLen := len(stripANSI(header))
-	if headerLen > fe.width && fe.width > 10 {
-		startPos := len(fe.basePath) - (fe.width - 10)
-		if startPos < 0 {
-			startPos = 0
-		}
-		if startPos > len(fe.basePath) {
-			startPos = len(fe.basePath)
-		}
-		header = fmt.Sprintf("\033[1m📁 ...%s\033[0m", fe.basePath[startPos:])
+	if textwidth.Width(header) > fe.width && fe.width > 10 {
+		// Keep the end of the path, which names the directory
+		header = fmt.Sprintf("\033[1m📁 %s\033[0m", textwidth.TruncateStart(fe.basePath, fe.width-3, "..."))
 	} else if fe.width <= 10 {
 		// Very narrow width, just show truncated
 		header = "\033[1m📁 ...\033[0m"
@@ -201,12 +261,13 @@ func (fe *FileExplorer) View() string {
 		}
 
 		// Truncate if too long
-		if len(stripANSI(line)) > fe.width {
-			line = truncateANSI(line, fe.width-3) + "..."
-		}
+		line = textwidth.TruncateWith(line, fe.width, "...")
 
 		b.WriteString(line)
 		b.WriteString("\n")
+
+		// Row 0 is the header, so node rows start at 1
+		fe.hits.Add("node", i, Bounds{X: 0, Y: 1 + i - fe.scrollOffset, Width: fe.width, Height: 1})
 	}
 
 	// Scroll indicator
@@ -237,6 +298,11 @@ func (fe *FileExplorer) Focused() bool {
 	return fe.focused
 }
 
+// KeyMap returns the explorer's bindings, which can be rebound
+func (fe *FileExplorer) KeyMap() *KeyMap {
+	return fe.keys
+}
+
 // GetSelectedPath returns the path of the currently selected node
 func (fe *FileExplorer) GetSelectedPath() string {
 	if fe.selected != nil {
@@ -250,6 +316,66 @@ func (fe *FileExplorer) GetSelectedNode() *FileNode {
 	return fe.selected
 }
 
+// Files returns up to limit files under the root in tree order, loading
+// directories that have not been opened yet without expanding them
+func (fe *FileExplorer) Files(limit int) []*FileNode {
+	var files []*FileNode
+	var walk func(node *FileNode)
+	walk = func(node *FileNode) {
+		if len(files) >= limit {
+			return
+		}
+		if !node.IsDir {
+			files = append(files, node)
+			return
+		}
+		fe.ensureChildren(node)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(fe.root)
+	return files
+}
+
+// Reveal expands the directories leading to path and selects it. It reports
+// whether path was found under the root.
+func (fe *FileExplorer) Reveal(path string) bool {
+	rel, err := filepath.Rel(fe.basePath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	node := fe.root
+	if rel != "." {
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			fe.ensureChildren(node)
+			var next *FileNode
+			for _, child := range node.Children {
+				if child.Name == name {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return false
+			}
+			node.Expanded = true
+			node = next
+		}
+	}
+	fe.updateVisibleNodes()
+
+	for i, visible := range fe.visibleNodes {
+		if visible == node {
+			fe.selectedIndex = i
+			fe.selected = node
+			return true
+		}
+	}
+	return false
+}
+
 // moveUp moves selection up
 func (fe *FileExplorer) moveUp() {
 	if fe.selectedIndex > 0 {
@@ -275,10 +401,7 @@ func (fe *FileExplorer) expand() {
 	if fe.selected.IsDir {
 		if !fe.selected.Expanded {
 			// Load children if not already loaded
-			if len(fe.selected.Children) == 0 {
-				children := fe.loadChildren(fe.selected.Path, fe.selected)
-				fe.selected.Children = children
-			}
+			fe.ensureChildren(fe.selected)
 			fe.selected.Expanded = true
 			fe.updateVisibleNodes()
 		}
@@ -358,6 +481,16 @@ func (fe *FileExplorer) buildTree(path string, parent *FileNode) *FileNode {
 	return node
 }
 
+// ensureChildren loads a directory's children the first time they are
+// needed. Empty directories are only read once.
+func (fe *FileExplorer) ensureChildren(node *FileNode) {
+	if node.loaded || len(node.Children) > 0 {
+		return
+	}
+	node.Children = fe.loadChildren(node.Path, node)
+	node.loaded = true
+}
+
 // loadChildren loads child nodes for a directory
 func (fe *FileExplorer) loadChildren(path string, parent *FileNode) []*FileNode {
 	entries, err := os.ReadDir(path)
@@ -409,9 +542,7 @@ func (fe *FileExplorer) collectVisibleNodes(node *FileNode) {
 
 	if node.IsDir && node.Expanded {
 		// Ensure children are loaded
-		if len(node.Children) == 0 {
-			node.Children = fe.loadChildren(node.Path, node)
-		}
+		fe.ensureChildren(node)
 
 		for _, child := range node.Children {
 			fe.collectVisibleNodes(child)
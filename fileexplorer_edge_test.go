@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -240,7 +241,7 @@ func TestFileExplorerLongFilenames(t *testing.T) {
 	// Should truncate or wrap, not overflow
 	lines := strings.Split(view, "\n")
 	for _, line := range lines {
-		strippedLine := stripANSI(line)
+		strippedLine := textwidth.Strip(line)
 		if len(strippedLine) > 100 {
 			t.Errorf("Line should be truncated, got length %d", len(strippedLine))
 		}
@@ -2,6 +2,8 @@ package tui
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -78,3 +80,66 @@ func TestFileExplorerNavigation(t *testing.T) {
 		t.Error("Up key did not move selection back")
 	}
 }
+
+// fileTree creates a small directory tree for tests
+func fileTree(t *testing.T) string {
+	root := t.TempDir()
+	for _, path := range []string{"cmd/app/main.go", "go.mod", "internal/util.go", ".hidden/secret"} {
+		full := filepath.Join(root, path)
+		os.MkdirAll(filepath.Dir(full), 0o755)
+		os.WriteFile(full, nil, 0o644)
+	}
+	return root
+}
+
+func TestFileExplorerFiles(t *testing.T) {
+	fe := NewFileExplorer(fileTree(t))
+
+	var names []string
+	for _, node := range fe.Files(100) {
+		names = append(names, node.Name)
+	}
+	if strings.Join(names, ",") != "main.go,util.go,go.mod" {
+		t.Errorf("Expected files in tree order without hidden ones, got %v", names)
+	}
+	if len(fe.visibleNodes) != 4 {
+		t.Errorf("Listing files should not expand directories, got %d visible nodes", len(fe.visibleNodes))
+	}
+	if len(fe.Files(2)) != 2 {
+		t.Error("Files should stop at the limit")
+	}
+}
+
+func TestFileExplorerFilesReadsEmptyDirsOnce(t *testing.T) {
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, "empty"), 0o755)
+	fe := NewFileExplorer(root)
+
+	if len(fe.Files(100)) != 0 {
+		t.Fatal("Expected no files under an empty directory")
+	}
+	// A file created later is not seen, since the directory was already read
+	os.WriteFile(filepath.Join(root, "empty", "new.go"), nil, 0o644)
+	if len(fe.Files(100)) != 0 {
+		t.Error("Files should not read an empty directory again on every call")
+	}
+}
+
+func TestFileExplorerReveal(t *testing.T) {
+	root := fileTree(t)
+	fe := NewFileExplorer(root)
+
+	if !fe.Reveal(filepath.Join(root, "cmd", "app", "main.go")) {
+		t.Fatal("Reveal should find a nested file")
+	}
+	if fe.GetSelectedNode().Name != "main.go" {
+		t.Errorf("Reveal should select the file, got %q", fe.GetSelectedNode().Name)
+	}
+	if fe.getDepth(fe.GetSelectedNode()) != 2 || len(fe.visibleNodes) != 6 {
+		t.Errorf("Reveal should expand the directories leading to the file, got %d visible nodes", len(fe.visibleNodes))
+	}
+
+	if fe.Reveal(filepath.Join(root, "missing.go")) || fe.Reveal(filepath.Dir(root)) {
+		t.Error("Reveal should fail for paths that are missing or outside the root")
+	}
+}
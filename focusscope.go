@@ -0,0 +1,179 @@
+package tui
+
+// Overlay is implemented by components drawn over the others while visible,
+// like Modal, DetailModal and CommandPalette. When an overlay held by an
+// Application becomes visible, the application focuses it and pushes a focus
+// scope around it, so Tab and Shift+Tab can't reach the components
+// underneath. Once it hides, the scope is popped and focus returns to the
+// component focused before. Visibility is checked after every message the
+// application handles.
+type Overlay interface {
+	IsVisible() bool
+}
+
+// focusScope limits focus cycling to a group of components
+type focusScope struct {
+	ids     []string // Components focus cycles between
+	restore string   // Component focused before the scope was pushed
+	overlay string   // Overlay that opened the scope; "" for PushFocusScope
+}
+
+// PushFocusScope traps focus cycling inside the components with the given
+// IDs, in display order, and focuses the first of them. Clicks outside the
+// scope are ignored until PopFocusScope. It reports false, pushing nothing,
+// if none of the IDs are known.
+func (a *Application) PushFocusScope(ids ...string) bool {
+	return a.pushScope(focusScope{ids: ids}) != nil
+}
+
+// PopFocusScope removes the innermost focus scope and refocuses the component
+// focused before it was pushed. It reports false if there is no scope.
+func (a *Application) PopFocusScope() bool {
+	if len(a.scopes) == 0 {
+		return false
+	}
+	a.popScope()
+	return true
+}
+
+// pushScope pushes scope, keeping only known IDs, and focuses its first
+// component. It returns nil if no IDs are known.
+func (a *Application) pushScope(scope focusScope) *focusScope {
+	var known []string
+	for _, id := range scope.ids {
+		if a.indexOf(id) >= 0 {
+			known = append(known, id)
+		}
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	scope.ids = known
+	scope.restore = a.FocusedID()
+	a.scopes = append(a.scopes, scope)
+	if !a.inScope(a.focused) {
+		a.FocusByID(known[0])
+	}
+	return &a.scopes[len(a.scopes)-1]
+}
+
+// popScope removes the innermost scope and restores focus to the component
+// focused before it, if it still exists
+func (a *Application) popScope() {
+	scope := a.scopes[len(a.scopes)-1]
+	a.scopes = a.scopes[:len(a.scopes)-1]
+	if scope.restore != "" && scope.restore != a.FocusedID() {
+		a.FocusByID(scope.restore)
+	}
+}
+
+// inScope reports whether focus may move to the component at index i
+func (a *Application) inScope(i int) bool {
+	if len(a.scopes) == 0 {
+		return true
+	}
+	if i < 0 || i >= len(a.ids) {
+		return false
+	}
+	for _, id := range a.scopes[len(a.scopes)-1].ids {
+		if id == a.ids[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// focusable returns the indexes focus cycles between: those in the innermost
+// scope, or every component
+func (a *Application) focusable() []int {
+	var order []int
+	for i := range a.components {
+		if a.inScope(i) {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// cycleFocus moves focus step places through the focusable components,
+// wrapping around
+func (a *Application) cycleFocus(step int) {
+	order := a.focusable()
+	if len(order) == 0 {
+		return
+	}
+
+	pos := -1
+	for i, idx := range order {
+		if idx == a.focused {
+			pos = i
+		}
+	}
+	switch {
+	case pos >= 0:
+		pos = (pos + step + len(order)) % len(order)
+	case step < 0:
+		pos = len(order) - 1
+	default:
+		pos = 0
+	}
+	a.FocusComponent(order[pos])
+}
+
+// syncOverlays removes the scopes of overlays that were hidden or removed,
+// wherever they are in the stack, and pushes a scope for each overlay that
+// has become visible
+func (a *Application) syncOverlays() {
+	for i := len(a.scopes) - 1; i >= 0; i-- {
+		if a.scopeLive(a.scopes[i]) {
+			continue
+		}
+		if i == len(a.scopes)-1 {
+			a.popScope()
+			continue
+		}
+		// The scope above was pushed from inside this one, so it should
+		// return focus to where this one would have
+		a.scopes[i+1].restore = a.scopes[i].restore
+		a.scopes = append(a.scopes[:i], a.scopes[i+1:]...)
+	}
+
+	for i, c := range a.components {
+		o, ok := c.(Overlay)
+		if !ok || !o.IsVisible() || a.hasOverlayScope(a.ids[i]) {
+			continue
+		}
+		a.pushScope(focusScope{ids: []string{a.ids[i]}, overlay: a.ids[i]})
+	}
+}
+
+// scopeLive reports whether scope should stay: its overlay is still visible,
+// or some of its components still exist
+func (a *Application) scopeLive(scope focusScope) bool {
+	if scope.overlay != "" {
+		c, _ := a.Get(scope.overlay)
+		o, ok := c.(Overlay)
+		return ok && o.IsVisible()
+	}
+	return a.anyKnown(scope.ids)
+}
+
+// hasOverlayScope reports whether a scope was pushed for the overlay id
+func (a *Application) hasOverlayScope(id string) bool {
+	for _, scope := range a.scopes {
+		if scope.overlay == id {
+			return true
+		}
+	}
+	return false
+}
+
+// anyKnown reports whether any of ids belongs to a component
+func (a *Application) anyKnown(ids []string) bool {
+	for _, id := range ids {
+		if a.indexOf(id) >= 0 {
+			return true
+		}
+	}
+	return false
+}
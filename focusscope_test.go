@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOverlayTrapsFocus(t *testing.T) {
+	app := NewApplication()
+	first, second := NewStatusBar(), NewStatusBar()
+	app.AddComponentWithID("first", first)
+	app.AddComponentWithID("second", second)
+	modal := NewModal(WithModalType(ModalConfirm))
+	app.AddComponentWithID("modal", modal)
+	app.FocusByID("second")
+
+	modal.ShowConfirm("Delete", "Delete the file?", nil, nil)
+	app.Update(struct{}{}) // Any message lets the application notice
+
+	if app.FocusedID() != "modal" || !modal.Focused() {
+		t.Fatalf("A visible overlay should take focus, focused %q", app.FocusedID())
+	}
+
+	// Tab moves between the modal's buttons instead of leaving it
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if app.FocusedID() != "modal" {
+		t.Errorf("Tab should stay inside the overlay, focused %q", app.FocusedID())
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if modal.IsVisible() {
+		t.Fatal("Esc should close the modal")
+	}
+	if app.FocusedID() != "second" || !second.Focused() {
+		t.Errorf("Closing the overlay should restore focus, focused %q", app.FocusedID())
+	}
+}
+
+func TestPaletteOverlayRestoresFocus(t *testing.T) {
+	app := NewApplication()
+	input := NewTextInput()
+	palette := NewCommandPalette([]Command{{Name: "Save"}})
+	app.AddComponentWithID("input", input)
+	app.AddComponentWithID("palette", palette)
+
+	palette.Show()
+	app.Update(struct{}{})
+	if app.FocusedID() != "palette" || input.Focused() {
+		t.Fatalf("The visible palette should take focus, focused %q", app.FocusedID())
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.FocusedID() != "input" || !input.Focused() {
+		t.Errorf("Hiding the palette should refocus the input, focused %q", app.FocusedID())
+	}
+}
+
+func TestPushFocusScope(t *testing.T) {
+	app := NewApplication()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		app.AddComponentWithID(id, NewStatusBar())
+	}
+	app.FocusByID("a")
+
+	if app.PushFocusScope("missing") {
+		t.Error("A scope of unknown IDs should not be pushed")
+	}
+	if !app.PushFocusScope("b", "c") {
+		t.Fatal("PushFocusScope should accept known IDs")
+	}
+	if app.FocusedID() != "b" {
+		t.Errorf("Pushing a scope should focus its first component, focused %q", app.FocusedID())
+	}
+
+	var visited []string
+	for i := 0; i < 3; i++ {
+		app.Update(tea.KeyMsg{Type: tea.KeyTab})
+		visited = append(visited, app.FocusedID())
+	}
+	if want := []string{"c", "b", "c"}; !equalStrings(visited, want) {
+		t.Errorf("Tab visited %v, want %v", visited, want)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if app.FocusedID() != "b" {
+		t.Errorf("Shift+Tab should wrap inside the scope, focused %q", app.FocusedID())
+	}
+
+	if !app.PopFocusScope() || app.FocusedID() != "a" {
+		t.Errorf("PopFocusScope should restore focus, focused %q", app.FocusedID())
+	}
+	if app.PopFocusScope() {
+		t.Error("PopFocusScope with no scope should report false")
+	}
+}
+
+func TestHiddenOverlayScopeBelowPushedScope(t *testing.T) {
+	app := NewApplication()
+	for _, id := range []string{"a", "b", "c"} {
+		app.AddComponentWithID(id, NewStatusBar())
+	}
+	modal := NewModal(WithModalType(ModalConfirm))
+	app.AddComponentWithID("modal", modal)
+	app.FocusByID("a")
+
+	modal.ShowConfirm("Delete", "Delete the file?", nil, nil)
+	app.Update(struct{}{})
+	app.PushFocusScope("b", "c")
+
+	modal.Hide()
+	app.Update(struct{}{})
+	if len(app.scopes) != 1 || app.scopes[0].overlay != "" {
+		t.Fatalf("The hidden overlay's scope should be removed, got %d scopes", len(app.scopes))
+	}
+	if app.FocusedID() != "b" {
+		t.Errorf("Removing a scope below should not move focus, focused %q", app.FocusedID())
+	}
+
+	if !app.PopFocusScope() || app.FocusedID() != "a" {
+		t.Errorf("Popping the pushed scope should skip the hidden overlay, focused %q", app.FocusedID())
+	}
+}
+
+func TestFocusScopeBlocksClicksOutside(t *testing.T) {
+	app := NewApplication()
+	top := &sizeRecorder{view: "top"}
+	bottom := &sizeRecorder{view: "bottom"}
+	app.AddComponentWithID("top", top)
+	app.AddComponentWithID("bottom", bottom)
+	app.Update(tea.WindowSizeMsg{Width: 20, Height: 2})
+	app.View()
+
+	app.PushFocusScope("top")
+	app.Update(tea.MouseMsg{X: 1, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if bottom.Focused() {
+		t.Error("Clicks outside the scope should not move focus")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultFPS is the frame rate of an Application's frame clock
+const DefaultFPS = 30
+
+// FrameMsg is broadcast by an Application's frame clock once per frame while
+// any component is animating. Components advance their animations from Time,
+// so all of them move in step however many are running.
+type FrameMsg struct {
+	Time  time.Time
+	Frame int // Frames since the clock was created
+}
+
+// Broadcast delivers frames to every component
+func (FrameMsg) Broadcast() {}
+
+// Animated is implemented by components that animate on an Application's
+// shared frame clock instead of running their own timers. Application
+// hands its clock to the Animated components it holds.
+type Animated interface {
+	// SetFrameClock makes the component wake clock when it starts animating
+	SetFrameClock(clock *FrameClock)
+
+	// Animating reports whether the component still needs frames
+	Animating() bool
+}
+
+// FrameClock schedules FrameMsgs at a target frame rate. It only runs while
+// something is animating: components call Wake when they start, and the
+// Application stops scheduling frames once no component is Animating.
+type FrameClock struct {
+	interval time.Duration
+	running  bool // A frame is scheduled
+	frame    int
+}
+
+// NewFrameClock creates a stopped clock that runs at fps frames per second
+func NewFrameClock(fps int) *FrameClock {
+	c := &FrameClock{}
+	c.SetFPS(fps)
+	return c
+}
+
+// SetFPS changes the target frame rate; values below 1 mean DefaultFPS
+func (c *FrameClock) SetFPS(fps int) {
+	if fps < 1 {
+		fps = DefaultFPS
+	}
+	c.interval = time.Second / time.Duration(fps)
+}
+
+// FPS returns the target frame rate
+func (c *FrameClock) FPS() int {
+	return int(time.Second / c.interval)
+}
+
+// Interval returns the time between frames
+func (c *FrameClock) Interval() time.Duration {
+	return c.interval
+}
+
+// Running reports whether a frame is scheduled
+func (c *FrameClock) Running() bool {
+	return c.running
+}
+
+// Wake starts the clock if it is stopped. It returns nil while a frame is
+// already scheduled, so any number of components can call it.
+func (c *FrameClock) Wake() tea.Cmd {
+	if c.running {
+		return nil
+	}
+	c.running = true
+	return c.next()
+}
+
+// advance counts a frame as it is handled on the update loop and numbers
+// msg with it. The timer that sends frames only sets Time, since it runs on
+// another goroutine.
+func (c *FrameClock) advance(msg FrameMsg) FrameMsg {
+	c.frame++
+	msg.Frame = c.frame
+	return msg
+}
+
+// schedule returns the next frame if animating, and otherwise stops the clock
+func (c *FrameClock) schedule(animating bool) tea.Cmd {
+	if !animating {
+		c.running = false
+		return nil
+	}
+	return c.next()
+}
+
+// next schedules the next frame
+func (c *FrameClock) next() tea.Cmd {
+	return tea.Tick(c.interval, func(t time.Time) tea.Msg {
+		return FrameMsg{Time: t}
+	})
+}
+
+// frameDue reports whether interval has passed since *last, moving *last to
+// now when it has. Components use it to step animations slower than the
+// clock's frame rate.
+func frameDue(last *time.Time, now time.Time, interval time.Duration) bool {
+	if now.Sub(*last) < interval {
+		return false
+	}
+	*last = now
+	return true
+}
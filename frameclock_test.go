@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameClockWake(t *testing.T) {
+	c := NewFrameClock(0)
+	if c.FPS() != DefaultFPS {
+		t.Errorf("FPS() = %d, want default %d", c.FPS(), DefaultFPS)
+	}
+	c.SetFPS(10)
+	if c.Interval() != 100*time.Millisecond {
+		t.Errorf("Interval() = %v at 10 FPS", c.Interval())
+	}
+
+	if c.Wake() == nil || !c.Running() {
+		t.Fatal("Wake should start a stopped clock")
+	}
+	if c.Wake() != nil {
+		t.Error("Wake should not schedule a second frame while one is pending")
+	}
+
+	if c.schedule(true) == nil {
+		t.Error("The clock should keep running while something animates")
+	}
+	if c.schedule(false) != nil || c.Running() {
+		t.Error("The clock should stop when nothing animates")
+	}
+	if c.Wake() == nil {
+		t.Error("Wake should restart a stopped clock")
+	}
+}
+
+func TestApplicationSharesFrameClock(t *testing.T) {
+	app := NewApplication(WithFPS(60))
+	if app.Clock().FPS() != 60 {
+		t.Errorf("Clock FPS = %d, want 60", app.Clock().FPS())
+	}
+
+	var blocks []*ToolBlock
+	for i := 0; i < 50; i++ {
+		tb := NewToolBlock("Bash", "sleep 1", nil, WithStreaming())
+		tb.SetStatus(StatusRunning)
+		blocks = append(blocks, tb)
+		app.AddComponent(tb)
+	}
+
+	timers := 0
+	for _, tb := range blocks {
+		if tb.Init() != nil {
+			timers++
+		}
+	}
+	if timers != 1 {
+		t.Errorf("50 running blocks should schedule 1 frame, got %d", timers)
+	}
+
+	start := time.Now()
+	app.Update(FrameMsg{Time: start.Add(toolBlockFrameInterval), Frame: 1})
+	for i, tb := range blocks {
+		if tb.spinner != 1 {
+			t.Fatalf("Block %d spinner = %d, want every block to step together", i, tb.spinner)
+		}
+	}
+
+	// Frames faster than the spinner's interval don't step it
+	app.Update(FrameMsg{Time: start.Add(toolBlockFrameInterval + time.Millisecond), Frame: 2})
+	if blocks[0].spinner != 1 {
+		t.Errorf("Spinner stepped before its interval passed")
+	}
+
+	for _, tb := range blocks {
+		tb.StopStreaming()
+	}
+	_, cmd := app.Update(FrameMsg{Time: start.Add(time.Second), Frame: 3})
+	if cmd != nil || app.Clock().Running() {
+		t.Error("The clock should pause once nothing is animating")
+	}
+}
+
+func TestFrameClockDrivesStructuredDataAndActivityBar(t *testing.T) {
+	app := NewApplication()
+	sd := NewStructuredData("Task")
+	bar := NewActivityBar()
+	app.AddComponent(sd)
+	app.AddComponent(bar)
+
+	if sd.StartRunning() == nil {
+		t.Fatal("Starting an animation should wake the clock")
+	}
+	if bar.Start("Working") != nil {
+		t.Error("The clock is already running, so no second timer should start")
+	}
+
+	now := time.Now()
+	app.Update(FrameMsg{Time: now.Add(structuredDataFrameInterval)})
+	if sd.animationFrame != 1 || bar.spinner != 1 {
+		t.Errorf("Frame should step both animations, got %d and %d", sd.animationFrame, bar.spinner)
+	}
+}
+
+func TestFrameClockCountsFramesOnUpdate(t *testing.T) {
+	c := NewFrameClock(1000)
+	msg := c.Wake()()
+	if frame, ok := msg.(FrameMsg); !ok || frame.Frame != 0 || c.frame != 0 {
+		t.Fatalf("The timer should only carry the time, got %#v", msg)
+	}
+	if got := c.advance(msg.(FrameMsg)).Frame; got != 1 {
+		t.Errorf("Handling a frame should number it, got %d", got)
+	}
+	if got := c.advance(FrameMsg{}).Frame; got != 2 {
+		t.Errorf("Frames should be counted in order, got %d", got)
+	}
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"unicode"
+)
+
+// Fuzzy match scoring, modelled on fzf's algorithm. Every matched character
+// scores scoreMatch plus a bonus for where it sits in the text; gaps between
+// matched characters cost a penalty that grows with their length.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	// Characters right after a space or at the start of the text
+	bonusBoundaryWhite = scoreMatch/2 + 2
+	// Characters right after a delimiter such as / or _
+	bonusBoundaryDelimiter = scoreMatch/2 + 1
+	// Characters right after any other non-word character
+	bonusBoundary = scoreMatch / 2
+	// Non-word characters are worth matching as much as a boundary
+	bonusNonWord = scoreMatch / 2
+	// Upper case after lower case (camelCase) and digits after letters
+	bonusCamel123 = bonusBoundary + scoreGapExtension
+	// Each character of a consecutive run earns at least this much, so a run
+	// beats the same characters spread out
+	bonusConsecutive = -(scoreGapStart + scoreGapExtension)
+	// The first pattern character's bonus counts double
+	bonusFirstCharMultiplier = 2
+)
+
+// charClass groups characters for boundary bonuses
+type charClass int
+
+const (
+	charWhite charClass = iota
+	charNonWord
+	charDelimiter
+	charLower
+	charUpper
+	charLetter
+	charNumber
+)
+
+// classOf returns the class of r
+func classOf(r rune) charClass {
+	switch {
+	case unicode.IsSpace(r):
+		return charWhite
+	case r == '/' || r == ',' || r == ':' || r == ';' || r == '|' || r == '_' || r == '-' || r == '.':
+		return charDelimiter
+	case unicode.IsLower(r):
+		return charLower
+	case unicode.IsUpper(r):
+		return charUpper
+	case unicode.IsLetter(r):
+		return charLetter
+	case unicode.IsDigit(r):
+		return charNumber
+	default:
+		return charNonWord
+	}
+}
+
+// boundaryBonus returns the bonus for matching a character of class cur that
+// follows a character of class prev
+func boundaryBonus(prev, cur charClass) int {
+	if cur > charDelimiter {
+		switch prev {
+		case charWhite:
+			return bonusBoundaryWhite
+		case charDelimiter:
+			return bonusBoundaryDelimiter
+		case charNonWord:
+			return bonusBoundary
+		}
+	}
+	switch {
+	case prev == charLower && cur == charUpper,
+		prev != charNumber && cur == charNumber:
+		return bonusCamel123
+	case cur == charWhite:
+		return bonusBoundaryWhite
+	case cur == charNonWord, cur == charDelimiter:
+		return bonusNonWord
+	}
+	return 0
+}
+
+// FuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case. When they do it returns a score, higher for better
+// matches, and the rune indices of text that were matched. Matches at word
+// boundaries, camelCase humps and in consecutive runs score higher, so
+// "ofi" ranks "Open File" above "Profile". An empty pattern matches
+// everything with a score of 0.
+func FuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	pat := []rune(pattern)
+	if len(pat) == 0 {
+		return 0, nil, true
+	}
+	txt := []rune(text)
+	n, m := len(txt), len(pat)
+
+	folded := make([]rune, n)
+	for j, r := range txt {
+		folded[j] = unicode.ToLower(r)
+	}
+	for i, r := range pat {
+		pat[i] = unicode.ToLower(r)
+	}
+
+	// Quick subsequence check before doing any scoring
+	i := 0
+	for j := 0; j < n && i < m; j++ {
+		if folded[j] == pat[i] {
+			i++
+		}
+	}
+	if i < m {
+		return 0, nil, false
+	}
+
+	bonus := make([]int, n)
+	prev := charWhite
+	for j, r := range txt {
+		cur := classOf(r)
+		bonus[j] = boundaryBonus(prev, cur)
+		prev = cur
+	}
+
+	// score[i][j] is the best score with pat[i] matched at txt[j]; from[i][j]
+	// is where pat[i-1] was matched on that path. chunk[i][j] is the bonus
+	// at the start of the consecutive run ending at txt[j].
+	const none = -1 << 30
+	scores := make([][]int, m)
+	from := make([][]int, m)
+	chunk := make([][]int, m)
+	for i := range pat {
+		scores[i] = make([]int, n)
+		from[i] = make([]int, n)
+		chunk[i] = make([]int, n)
+		for j := range scores[i] {
+			scores[i][j] = none
+		}
+	}
+
+	for j := 0; j < n; j++ {
+		if folded[j] == pat[0] {
+			scores[0][j] = scoreMatch + bonus[j]*bonusFirstCharMultiplier
+			chunk[0][j] = bonus[j]
+			from[0][j] = -1
+		}
+	}
+
+	for i := 1; i < m; i++ {
+		gapBest, gapFrom := none, -1 // Best score reaching j across a gap
+		for j := i; j < n; j++ {
+			if j >= 2 {
+				if gapBest != none {
+					gapBest += scoreGapExtension
+				}
+				if s := scores[i-1][j-2]; s != none && s+scoreGapStart > gapBest {
+					gapBest, gapFrom = s+scoreGapStart, j-2
+				}
+			}
+			if folded[j] != pat[i] {
+				continue
+			}
+
+			if gapBest != none {
+				scores[i][j] = gapBest + scoreMatch + bonus[j]
+				from[i][j] = gapFrom
+				chunk[i][j] = bonus[j]
+			}
+			if s := scores[i-1][j-1]; s != none {
+				runBonus := chunk[i-1][j-1]
+				if bonus[j] >= bonusBoundary && bonus[j] > runBonus {
+					runBonus = bonus[j] // A new word starts a new run
+				}
+				consecutive := s + scoreMatch + max(runBonus, max(bonus[j], bonusConsecutive))
+				if consecutive >= scores[i][j] {
+					scores[i][j] = consecutive
+					from[i][j] = j - 1
+					chunk[i][j] = runBonus
+				}
+			}
+		}
+	}
+
+	end := -1
+	for j := m - 1; j < n; j++ {
+		if scores[m-1][j] != none && (end < 0 || scores[m-1][j] > scores[m-1][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions = make([]int, m)
+	for i, j := m-1, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+	return scores[m-1][end], positions, true
+}
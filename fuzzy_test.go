@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatchSubsequence(t *testing.T) {
+	if _, _, ok := FuzzyMatch("ofl", "Open File"); !ok {
+		t.Error("Characters in order should match")
+	}
+	if _, _, ok := FuzzyMatch("flo", "Open File"); ok {
+		t.Error("Characters out of order should not match")
+	}
+	if score, positions, ok := FuzzyMatch("", "anything"); !ok || score != 0 || positions != nil {
+		t.Error("Empty pattern should match with no score")
+	}
+}
+
+func TestFuzzyMatchIgnoresCase(t *testing.T) {
+	_, positions, ok := FuzzyMatch("OPEN", "openFile")
+	if !ok || !reflect.DeepEqual(positions, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected case-insensitive match at 0-3, got %v (ok=%v)", positions, ok)
+	}
+}
+
+func TestFuzzyMatchPrefersWordBoundaries(t *testing.T) {
+	_, positions, _ := FuzzyMatch("fi", "profile file")
+	if !reflect.DeepEqual(positions, []int{8, 9}) {
+		t.Errorf("Expected match at the word start [8 9], got %v", positions)
+	}
+
+	boundary, _, _ := FuzzyMatch("ofi", "Open File")
+	inside, _, _ := FuzzyMatch("ofi", "Profile")
+	if boundary <= inside {
+		t.Errorf("Word boundary match should outscore a mid-word match: %d vs %d", boundary, inside)
+	}
+}
+
+func TestFuzzyMatchPrefersCamelCase(t *testing.T) {
+	_, positions, _ := FuzzyMatch("gc", "toggleComment")
+	if !reflect.DeepEqual(positions, []int{3, 6}) {
+		t.Errorf("Expected the camelCase hump to match, got %v", positions)
+	}
+
+	camel, _, _ := FuzzyMatch("tc", "toggleComment")
+	plain, _, _ := FuzzyMatch("tc", "toggle comment")
+	buried, _, _ := FuzzyMatch("tc", "attic")
+	if camel <= buried || plain <= buried {
+		t.Errorf("Boundary matches should outscore buried ones: %d, %d vs %d", camel, plain, buried)
+	}
+}
+
+func TestFuzzyMatchPrefersConsecutive(t *testing.T) {
+	run, _, _ := FuzzyMatch("save", "Save All")
+	spread, _, _ := FuzzyMatch("save", "Show all venues")
+	if run <= spread {
+		t.Errorf("A consecutive run should outscore a spread match: %d vs %d", run, spread)
+	}
+}
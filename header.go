@@ -3,6 +3,7 @@ package tui
 import (
 	"strings"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 	design "github.com/SCKelemen/design-system"
 )
@@ -310,17 +311,14 @@ func (h *Header) getColumnContent(colIdx, row int) string {
 
 // alignContent aligns content within a given width
 func (h *Header) alignContent(content string, width int, align ColumnAlign) string {
-	contentWidth := len(content) // Simple length, should use text package for Unicode
+	contentWidth := textwidth.Width(content)
 
 	if contentWidth >= width {
 		// Truncate if too long
-		if contentWidth > width {
-			if width > 3 {
-				return content[:width-3] + "..."
-			}
-			return content[:width]
+		if width > 3 {
+			return textwidth.TruncateWith(content, width, "...")
 		}
-		return content
+		return textwidth.Truncate(content, width)
 	}
 
 	padding := width - contentWidth
@@ -0,0 +1,447 @@
+// Package textwidth measures, truncates and pads strings by the terminal
+// columns they occupy.
+//
+// ANSI escape sequences take no columns and are never cut. Text is split into
+// grapheme clusters as it is scanned and each cluster is measured with the
+// text package's terminal measurement, so CJK characters and emoji count as
+// two columns, combining marks and zero-width joiners as none, and no cluster
+// or multi-byte rune is ever split. Truncation stops measuring once the width
+// is reached, so cutting a long line costs no more than the part kept.
+package textwidth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SCKelemen/text"
+	"github.com/rivo/uniseg"
+)
+
+// measurer measures graphemes in terminal cells
+var measurer = text.NewTerminal()
+
+// Width returns the number of columns s occupies
+func Width(s string) int {
+	if isASCII(s) {
+		return len(s)
+	}
+	width := 0
+	segments(s, func(seg string, escape bool) {
+		if !escape {
+			eachGrapheme(seg, func(_ string, w int) bool {
+				width += w
+				return true
+			})
+		}
+	})
+	return width
+}
+
+// Strip removes ANSI escape sequences from s
+func Strip(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	segments(s, func(seg string, escape bool) {
+		if !escape {
+			b.WriteString(seg)
+		}
+	})
+	return b.String()
+}
+
+// Graphemes splits s into grapheme clusters
+func Graphemes(s string) []string {
+	var out []string
+	eachGrapheme(s, func(g string, _ int) bool {
+		out = append(out, g)
+		return true
+	})
+	return out
+}
+
+// Truncate cuts s to at most width columns. Escape sequences are kept, even
+// after the cut, so styles opened or closed in s still balance. A wide
+// grapheme that would straddle the edge is dropped.
+func Truncate(s string, width int) string {
+	out, _, _ := truncate(s, width)
+	return out
+}
+
+// TruncateWith cuts s to at most width columns, ending it with tail (such as
+// "…") when anything was cut
+func TruncateWith(s string, width int, tail string) string {
+	if _, _, cut := truncate(s, width); !cut {
+		return s
+	}
+	tailWidth := Width(tail)
+	if tailWidth > width {
+		return Truncate(tail, width)
+	}
+	return Truncate(s, width-tailWidth) + tail
+}
+
+// truncate cuts s to at most width columns and returns the result, its width
+// and whether anything was cut. Text is measured only up to the cut; after
+// it, escapes are picked out without segmenting the rest.
+func truncate(s string, width int) (string, int, bool) {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			n := EscapeLen(s[i:])
+			if n == 0 {
+				n = len(s) - i
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '\x1b')
+		if end < 0 {
+			end = len(s)
+		} else {
+			end += i
+		}
+		cut := false
+		eachGrapheme(s[i:end], func(g string, w int) bool {
+			if visible+w > width {
+				cut = true
+				return false
+			}
+			b.WriteString(g)
+			visible += w
+			return true
+		})
+		if cut {
+			// Keep the escapes that follow so styles still balance
+			for rest := s[end:]; rest != ""; {
+				esc := strings.IndexByte(rest, '\x1b')
+				if esc < 0 {
+					break
+				}
+				rest = rest[esc:]
+				n := EscapeLen(rest)
+				if n == 0 {
+					n = len(rest)
+				}
+				b.WriteString(rest[:n])
+				rest = rest[n:]
+			}
+			return b.String(), visible, true
+		}
+		i = end
+	}
+	return s, visible, false
+}
+
+// TruncateStart cuts s to at most width columns by removing text from its
+// start, beginning it with head (such as "…") when anything was cut. It
+// suits paths, where the end matters most.
+func TruncateStart(s string, width int, head string) string {
+	if Width(s) <= width {
+		return s
+	}
+	headWidth := Width(head)
+	if headWidth > width {
+		return Truncate(head, width)
+	}
+
+	var parts []string
+	segments(s, func(seg string, escape bool) {
+		if escape {
+			parts = append(parts, seg)
+			return
+		}
+		parts = append(parts, Graphemes(seg)...)
+	})
+
+	// Walk back from the end, keeping every escape and the graphemes that fit
+	var kept []string
+	visible := headWidth
+	cut := false
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i][0] == '\x1b' {
+			kept = append(kept, parts[i])
+			continue
+		}
+		w := graphemeWidth(parts[i])
+		if cut || visible+w > width {
+			cut = true
+			continue
+		}
+		kept = append(kept, parts[i])
+		visible += w
+	}
+
+	var b strings.Builder
+	b.WriteString(head)
+	for i := len(kept) - 1; i >= 0; i-- {
+		b.WriteString(kept[i])
+	}
+	return b.String()
+}
+
+// PadRight pads s with spaces on the right to width columns
+func PadRight(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft pads s with spaces on the left to width columns
+func PadLeft(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center pads s with spaces on both sides to width columns, putting any odd
+// space on the right
+func Center(s string, width int) string {
+	n := width - Width(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
+
+// Fit truncates or pads s to exactly width columns. Carriage returns are
+// removed, and styles are reset at the end so they cannot bleed into
+// whatever is drawn next to the line.
+func Fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\r", "")
+	fitted, visible, _ := truncate(s, width)
+	if strings.IndexByte(fitted, '\x1b') >= 0 {
+		fitted += "\033[0m"
+	}
+	if visible < width {
+		fitted += strings.Repeat(" ", width-visible)
+	}
+	return fitted
+}
+
+// Wrap word-wraps s to lines of at most width columns. Newlines always break,
+// and words wider than a line are broken between graphemes. Styles set by
+// SGR escapes are reset at the end of each line and reopened on the next, so
+// every line can be drawn on its own. A width of 0 or less only splits lines.
+func Wrap(s string, width int) []string {
+	if width <= 0 {
+		return strings.Split(s, "\n")
+	}
+
+	type piece struct {
+		s      string
+		width  int
+		escape bool
+	}
+
+	var (
+		lines     []string
+		line      strings.Builder
+		lineWidth int
+		word      []piece
+		wordWidth int
+		spaces    int
+		style     string // SGR sequences in effect after the placed text
+	)
+
+	endLine := func(style string) {
+		if style != "" {
+			line.WriteString("\033[0m")
+		}
+		lines = append(lines, line.String())
+		line.Reset()
+		line.WriteString(style)
+		lineWidth = 0
+	}
+
+	placeWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		if wordWidth > 0 {
+			if lineWidth > 0 && lineWidth+spaces+wordWidth > width {
+				endLine(style)
+			} else if lineWidth > 0 {
+				line.WriteString(strings.Repeat(" ", spaces))
+				lineWidth += spaces
+			}
+			spaces = 0
+		}
+		for _, p := range word {
+			if p.escape {
+				style = applySGR(style, p.s)
+			} else if lineWidth > 0 && lineWidth+p.width > width {
+				endLine(style)
+			}
+			line.WriteString(p.s)
+			lineWidth += p.width
+		}
+		word, wordWidth = word[:0], 0
+	}
+
+	segments(s, func(seg string, escape bool) {
+		if escape {
+			word = append(word, piece{s: seg, escape: true})
+			return
+		}
+		for _, g := range Graphemes(seg) {
+			switch g {
+			case "\n", "\r\n":
+				placeWord()
+				endLine(style)
+				spaces = 0
+			case " ", "\t":
+				placeWord()
+				spaces++
+			default:
+				if g[0] < 0x20 || g[0] == 0x7f {
+					continue
+				}
+				w := graphemeWidth(g)
+				word = append(word, piece{s: g, width: w})
+				wordWidth += w
+			}
+		}
+	})
+	placeWord()
+	endLine(style)
+	return lines
+}
+
+// applySGR returns the styles in effect after seq: an SGR reset clears them,
+// another SGR sequence adds to them, and anything else leaves them alone
+func applySGR(style, seq string) string {
+	if !strings.HasPrefix(seq, "\033[") || !strings.HasSuffix(seq, "m") {
+		return style
+	}
+	if seq == "\033[0m" || seq == "\033[m" {
+		return ""
+	}
+	return style + seq
+}
+
+// eachGrapheme calls fn with each grapheme cluster in s, which holds no
+// escapes, and its width, until fn returns false. Clusters are found one at
+// a time, so the cost grows with the part of s that is scanned.
+func eachGrapheme(s string, fn func(g string, width int) bool) {
+	state := -1
+	for s != "" {
+		// A printable ASCII byte not followed by a combining character is a
+		// cluster of its own
+		if c := s[0]; c >= 0x20 && c < 0x7f && (len(s) == 1 || s[1] < 0x80) {
+			if !fn(s[:1], 1) {
+				return
+			}
+			s, state = s[1:], -1
+			continue
+		}
+
+		var g string
+		g, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if !fn(g, graphemeWidth(g)) {
+			return
+		}
+	}
+}
+
+// graphemeWidth returns the columns a grapheme cluster occupies. Emoji
+// sequences are measured as a whole by the text package; any other cluster
+// is a base character extended by combining marks, and is as wide as its base.
+func graphemeWidth(g string) int {
+	base, size := utf8.DecodeRuneInString(g)
+	if size < len(g) && isEmojiSequence(g) {
+		return int(measurer.Width(g))
+	}
+	if unicode.In(base, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	return int(text.TerminalMeasure(base))
+}
+
+// isEmojiSequence reports whether a multi-rune cluster joins or modifies
+// emoji: ZWJ sequences, presentation selectors, keycaps, skin tones and flags
+func isEmojiSequence(g string) bool {
+	for _, r := range g {
+		switch {
+		case r == '\u200d', r == '\ufe0f', r == '\u20e3':
+			return true
+		case r >= 0x1f3fb && r <= 0x1f3ff, r >= 0x1f1e6 && r <= 0x1f1ff:
+			return true
+		}
+	}
+	return false
+}
+
+// EscapeLen returns the length in bytes of the escape sequence at the start
+// of s, which begins with ESC, or 0 if the sequence is incomplete
+func EscapeLen[S ~string | ~[]byte](s S) int {
+	if len(s) < 2 {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameters, then a final byte in @ to ~
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		// OSC: terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return 0
+}
+
+// segments calls fn with each escape sequence and each run of text in s, in
+// order. An incomplete escape at the end of s is passed as an escape.
+func segments(s string, fn func(seg string, escape bool)) {
+	for s != "" {
+		if s[0] == '\x1b' {
+			n := EscapeLen(s)
+			if n == 0 {
+				n = len(s)
+			}
+			fn(s[:n], true)
+			s = s[n:]
+			continue
+		}
+
+		n := strings.IndexByte(s, '\x1b')
+		if n < 0 {
+			n = len(s)
+		}
+		fn(s[:n], false)
+		s = s[n:]
+	}
+}
+
+// isASCII reports whether s is printable ASCII, which is one column a byte
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
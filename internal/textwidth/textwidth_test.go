@@ -0,0 +1,174 @@
+package textwidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"hello", 5},
+		{"héllo", 5},
+		{"é", 1}, // e + combining acute accent
+		{"世界", 4},
+		{"👍", 2},
+		{"👨‍👩‍👧", 2}, // ZWJ family
+		{"\033[1;31mred\033[0m", 3},
+		{"\033]8;;http://x\a\033]8;;\a", 0}, // OSC hyperlink markers
+	}
+
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateNeverSplitsGraphemes(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"abcdef", 3, "abc"},
+		{"héllo", 2, "hé"},
+		{"世界abc", 3, "世"},
+		{"ééé", 2, "éé"},
+		{"👍👍", 3, "👍"},
+		{"short", 10, "short"},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+
+	if got := TruncateWith("abcdef", 4, "…"); got != "abc…" {
+		t.Errorf("TruncateWith = %q, want %q", got, "abc…")
+	}
+	if got := TruncateWith("\033[1mbold text\033[0m", 6, "..."); got != "\033[1mbol\033[0m..." {
+		t.Errorf("TruncateWith should keep escapes, got %q", got)
+	}
+	if got := TruncateStart("/home/世界/src", 8, "..."); got != ".../src" {
+		t.Errorf("TruncateStart = %q, want %q", got, ".../src")
+	}
+	if got := TruncateStart("/home/世界/src", 9, "…"); got != "…世界/src" {
+		t.Errorf("TruncateStart = %q, want %q", got, "…世界/src")
+	}
+}
+
+func TestPadding(t *testing.T) {
+	if got := PadRight("世", 4); got != "世  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft("\033[1mab\033[0m", 4); got != "  \033[1mab\033[0m" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := Center("ab", 5); got != " ab  " {
+		t.Errorf("Center = %q", got)
+	}
+}
+
+func TestFitPadsAndTruncates(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 3, "abc"},
+		{"", 2, "  "},
+		{"héllo", 3, "hél"},
+		{"abc", 0, ""},
+		{"世界", 3, "世 "},
+		{"a\rb", 3, "ab "},
+	}
+
+	for _, tt := range tests {
+		if got := Fit(tt.line, tt.width); got != tt.want {
+			t.Errorf("Fit(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFitKeepsEscapesAndResets(t *testing.T) {
+	got := Fit("\033[1mbold text\033[0m", 4)
+
+	if Strip(got) != "bold" {
+		t.Errorf("Expected visible text 'bold', got %q", Strip(got))
+	}
+	if !strings.HasPrefix(got, "\033[1m") {
+		t.Error("Expected leading style escape to be preserved")
+	}
+	if !strings.HasSuffix(got, "\033[0m") {
+		t.Error("Expected styles to be reset at the end of the line")
+	}
+}
+
+func TestEscapeLen(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"\033[31mx", 5},
+		{"\033[3", 0},
+		{"\033", 0},
+		{"\033]8;;url\033\\x", 10},
+		{"\033]8;;url", 0},
+		{"\033(B", 2},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLen(tt.s); got != tt.want {
+			t.Errorf("EscapeLen(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+		if got := EscapeLen([]byte(tt.s)); got != tt.want {
+			t.Errorf("EscapeLen([]byte(%q)) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"世界世界", 5, []string{"世界", "世界"}},
+		{"one\ntwo three", 20, []string{"one", "two three"}},
+		{"", 5, []string{""}},
+		{"\033[31mred words\033[0m here", 4, []string{"\033[31mred\033[0m", "\033[31mword\033[0m", "\033[31ms\033[0m", "here"}},
+	}
+
+	for _, tt := range tests {
+		got := Wrap(tt.s, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		for _, line := range got {
+			if Width(line) > tt.width {
+				t.Errorf("Wrap(%q, %d) line %q is wider than %d", tt.s, tt.width, line, tt.width)
+			}
+		}
+	}
+}
+
+func BenchmarkWidthCJK(b *testing.B) {
+	s := strings.Repeat("世界", 12800)
+	for i := 0; i < b.N; i++ {
+		Width(s)
+	}
+}
+
+func BenchmarkFitLongLine(b *testing.B) {
+	s := "\033[32m" + strings.Repeat("世界 ", 8000) + "\033[0m"
+	for i := 0; i < b.N; i++ {
+		Fit(s, 80)
+	}
+}
@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap maps the actions of a component to key bindings. Components look up
+// keys by action name instead of matching key strings, so an app can rebind
+// any action without touching the component:
+//
+//	explorer.KeyMap().Rebind("down", "ctrl+n", "down")
+//
+// KeyMap implements the bubbles help.KeyMap interface.
+type KeyMap struct {
+	name     string
+	actions  []string // Action names in the order they were bound
+	bindings map[string]key.Binding
+}
+
+// KeyMapper is implemented by components whose keys can be rebound
+type KeyMapper interface {
+	KeyMap() *KeyMap
+}
+
+// KeyConflict is a key bound to more than one action. Actions are named
+// "KeyMap.action".
+type KeyConflict struct {
+	Key     string
+	Actions []string
+}
+
+// NewKeyMap creates an empty keymap; name identifies it in conflict reports
+// and binding lists
+func NewKeyMap(name string) *KeyMap {
+	return &KeyMap{
+		name:     name,
+		bindings: make(map[string]key.Binding),
+	}
+}
+
+// Name returns the keymap's name
+func (km *KeyMap) Name() string {
+	return km.name
+}
+
+// Bind sets the binding for action, adding the action if it is new
+func (km *KeyMap) Bind(action string, binding key.Binding) {
+	if _, ok := km.bindings[action]; !ok {
+		km.actions = append(km.actions, action)
+	}
+	km.bindings[action] = binding
+}
+
+// Rebind replaces the keys of an existing action, keeping its description.
+// With no keys the action is unbound.
+func (km *KeyMap) Rebind(action string, keys ...string) {
+	b, ok := km.bindings[action]
+	if !ok {
+		return
+	}
+	if len(keys) == 0 {
+		b.SetKeys()
+		b.SetEnabled(false)
+	} else {
+		b.SetKeys(keys...)
+		b.SetEnabled(true)
+	}
+	b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	km.bindings[action] = b
+}
+
+// SetEnabled turns an action on or off without changing its keys
+func (km *KeyMap) SetEnabled(action string, enabled bool) {
+	if b, ok := km.bindings[action]; ok {
+		b.SetEnabled(enabled)
+		km.bindings[action] = b
+	}
+}
+
+// Binding returns the binding for action
+func (km *KeyMap) Binding(action string) key.Binding {
+	return km.bindings[action]
+}
+
+// Actions returns the action names in the order they were bound
+func (km *KeyMap) Actions() []string {
+	return append([]string(nil), km.actions...)
+}
+
+// Matches reports whether msg triggers action
+func (km *KeyMap) Matches(msg tea.KeyMsg, action string) bool {
+	b, ok := km.bindings[action]
+	return ok && key.Matches(msg, b)
+}
+
+// Lookup returns the first action, in binding order, that msg triggers
+func (km *KeyMap) Lookup(msg tea.KeyMsg) (string, bool) {
+	for _, action := range km.actions {
+		if key.Matches(msg, km.bindings[action]) {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// ShortHelp returns the enabled bindings that have help text
+func (km *KeyMap) ShortHelp() []key.Binding {
+	var bindings []key.Binding
+	for _, action := range km.actions {
+		b := km.bindings[action]
+		if b.Enabled() && b.Help().Key != "" {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+// FullHelp returns ShortHelp as a single column
+func (km *KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{km.ShortHelp()}
+}
+
+// hint returns the help key of action, for inline hints such as
+// "(ctrl+o to expand)"
+func (km *KeyMap) hint(action string) string {
+	return km.bindings[action].Help().Key
+}
+
+// bound reports whether msg triggers any enabled action
+func (km *KeyMap) bound(msg tea.KeyMsg) bool {
+	_, ok := km.Lookup(msg)
+	return ok
+}
+
+// typesText reports whether msg enters text, like a letter or a space
+func typesText(msg tea.KeyMsg) bool {
+	return (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt
+}
+
+// Conflicts returns keys bound to more than one enabled action
+func (km *KeyMap) Conflicts() []KeyConflict {
+	return findConflicts([]*KeyMap{km})
+}
+
+// findConflicts returns keys bound to more than one enabled action across
+// maps, sorted by key
+func findConflicts(maps []*KeyMap) []KeyConflict {
+	owners := make(map[string][]string)
+	for _, km := range maps {
+		for _, action := range km.actions {
+			b := km.bindings[action]
+			if !b.Enabled() {
+				continue
+			}
+			for _, k := range b.Keys() {
+				owners[k] = append(owners[k], km.name+"."+action)
+			}
+		}
+	}
+
+	var conflicts []KeyConflict
+	for k, actions := range owners {
+		if len(actions) > 1 {
+			conflicts = append(conflicts, KeyConflict{Key: k, Actions: actions})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// KeyRegistry collects the global keymap and the keymaps of an app's
+// components. It detects conflicts between them and knows which bindings are
+// active, so a StatusBar or CommandPalette can list them.
+type KeyRegistry struct {
+	global *KeyMap
+	maps   []*KeyMap
+	active *KeyMap // Keymap of the focused component
+}
+
+// NewKeyRegistry creates a registry around a global keymap, which may be nil
+func NewKeyRegistry(global *KeyMap) *KeyRegistry {
+	if global == nil {
+		global = NewKeyMap("Global")
+	}
+	return &KeyRegistry{global: global}
+}
+
+// Global returns the keymap of app-wide bindings
+func (r *KeyRegistry) Global() *KeyMap {
+	return r.global
+}
+
+// Register adds a component keymap
+func (r *KeyRegistry) Register(km *KeyMap) {
+	for _, existing := range r.maps {
+		if existing == km {
+			return
+		}
+	}
+	r.maps = append(r.maps, km)
+}
+
+// Unregister removes a component keymap
+func (r *KeyRegistry) Unregister(km *KeyMap) {
+	for i, existing := range r.maps {
+		if existing == km {
+			r.maps = append(r.maps[:i], r.maps[i+1:]...)
+			break
+		}
+	}
+	if r.active == km {
+		r.active = nil
+	}
+}
+
+// KeyMaps returns the registered component keymaps
+func (r *KeyRegistry) KeyMaps() []*KeyMap {
+	return append([]*KeyMap(nil), r.maps...)
+}
+
+// SetActive marks the keymap of the focused component; nil means none
+func (r *KeyRegistry) SetActive(km *KeyMap) {
+	r.active = km
+}
+
+// Active returns the bindings in effect: the focused component's, then the
+// global ones
+func (r *KeyRegistry) Active() []key.Binding {
+	var bindings []key.Binding
+	if r.active != nil {
+		bindings = append(bindings, r.active.ShortHelp()...)
+	}
+	return append(bindings, r.global.ShortHelp()...)
+}
+
+// Conflicts returns keys bound twice within one keymap, or bound both
+// globally and by a component. Components are focused one at a time, so
+// keys shared between two components are not conflicts.
+func (r *KeyRegistry) Conflicts() []KeyConflict {
+	conflicts := r.global.Conflicts()
+	for _, km := range r.maps {
+		conflicts = append(conflicts, findConflicts([]*KeyMap{r.global, km})...)
+	}
+
+	// Conflicts inside the global map were found once per component; keep
+	// the first of identical reports
+	seen := make(map[string]bool)
+	unique := conflicts[:0]
+	for _, c := range conflicts {
+		id := c.Key + "\x00" + strings.Join(c.Actions, "\x00")
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, c)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Key < unique[j].Key
+	})
+	return unique
+}
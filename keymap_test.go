@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyMapLookup(t *testing.T) {
+	km := NewKeyMap("Test")
+	km.Bind("up", key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")))
+
+	if action, ok := km.Lookup(runeKey("j")); !ok || action != "down" {
+		t.Errorf("Lookup(j) = %q, %v, want down", action, ok)
+	}
+	if action, ok := km.Lookup(tea.KeyMsg{Type: tea.KeyUp}); !ok || action != "up" {
+		t.Errorf("Lookup(up) = %q, %v, want up", action, ok)
+	}
+	if _, ok := km.Lookup(runeKey("x")); ok {
+		t.Error("Lookup(x) should not match")
+	}
+
+	km.SetEnabled("down", false)
+	if km.Matches(runeKey("j"), "down") {
+		t.Error("Disabled actions should not match")
+	}
+	if len(km.ShortHelp()) != 1 {
+		t.Errorf("ShortHelp should skip disabled bindings, got %d", len(km.ShortHelp()))
+	}
+}
+
+func TestKeyMapRebind(t *testing.T) {
+	fe := NewFileExplorer(t.TempDir())
+	fe.KeyMap().Rebind("down", "ctrl+n")
+
+	if fe.KeyMap().Matches(runeKey("j"), "down") {
+		t.Error("Old key should no longer match after Rebind")
+	}
+	if !fe.KeyMap().Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, "down") {
+		t.Error("New key should match after Rebind")
+	}
+	if help := fe.KeyMap().Binding("down").Help(); help.Key != "ctrl+n" || help.Desc == "" {
+		t.Errorf("Rebind should update the help key and keep the description, got %+v", help)
+	}
+
+	fe.KeyMap().Rebind("down")
+	if fe.KeyMap().Binding("down").Enabled() {
+		t.Error("Rebind with no keys should disable the action")
+	}
+}
+
+func TestKeyMapConflicts(t *testing.T) {
+	km := NewKeyMap("Test")
+	km.Bind("save", key.NewBinding(key.WithKeys("ctrl+s")))
+	km.Bind("search", key.NewBinding(key.WithKeys("ctrl+s", "/")))
+
+	conflicts := km.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].Key != "ctrl+s" {
+		t.Fatalf("Expected one conflict on ctrl+s, got %+v", conflicts)
+	}
+	if got := strings.Join(conflicts[0].Actions, ","); got != "Test.save,Test.search" {
+		t.Errorf("Conflicting actions = %s", got)
+	}
+
+	km.SetEnabled("search", false)
+	if len(km.Conflicts()) != 0 {
+		t.Error("Disabled bindings should not conflict")
+	}
+}
+
+func TestKeyRegistryConflicts(t *testing.T) {
+	r := NewKeyRegistry(DefaultGlobalKeyMap())
+	r.Register(DefaultConfirmationBlockKeyMap())
+	r.Register(DefaultFileExplorerKeyMap())
+	r.Register(DefaultDashboardKeyMap())
+
+	var tab *KeyConflict
+	conflicts := r.Conflicts()
+	for i, c := range conflicts {
+		if c.Key == "tab" {
+			tab = &conflicts[i]
+		}
+		actions := strings.Join(c.Actions, ",")
+		if strings.Contains(actions, "FileExplorer.") && strings.Contains(actions, "Dashboard.") {
+			t.Errorf("Keys shared by two components should not conflict: %+v", c)
+		}
+	}
+	if tab == nil {
+		t.Fatal("Expected the global tab binding to conflict with ConfirmationBlock")
+	}
+	if got := strings.Join(tab.Actions, ","); got != "Global.focus-next,ConfirmationBlock.down" {
+		t.Errorf("tab conflict actions = %s", got)
+	}
+}
+
+func TestKeyRegistryActive(t *testing.T) {
+	app := NewApplication()
+	fe := NewFileExplorer(t.TempDir())
+	sb := NewStatusBar()
+	app.AddComponent(fe)
+	app.AddComponent(sb)
+
+	active := app.Keys().Active()
+	if len(active) == 0 || active[0].Help().Desc != fe.KeyMap().ShortHelp()[0].Help().Desc {
+		t.Errorf("Active bindings should start with the focused component's")
+	}
+
+	app.FocusComponent(1)
+	active = app.Keys().Active()
+	if len(active) != len(app.Keys().Global().ShortHelp()) {
+		t.Errorf("A component without a keymap should leave only the global bindings, got %d", len(active))
+	}
+}
+
+func TestStatusBarListsActiveBindings(t *testing.T) {
+	app := NewApplication()
+	cb := NewCodeBlock(WithCode("package main"))
+	sb := NewStatusBar()
+	app.AddComponent(cb)
+	app.AddComponent(sb)
+	sb.Update(tea.WindowSizeMsg{Width: 120})
+
+	view := sb.View()
+	for _, want := range []string{"ctrl+o: expand", "tab: focus", "q: quit"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Status bar should list %q, got %q", want, view)
+		}
+	}
+
+	cb.KeyMap().Rebind("toggle", "ctrl+e")
+	if view := sb.View(); !strings.Contains(view, "ctrl+e: expand") {
+		t.Errorf("Status bar should follow rebinding, got %q", view)
+	}
+}
+
+func TestPaletteListsBindings(t *testing.T) {
+	app := NewApplication()
+	palette := NewCommandPalette(nil)
+	app.AddComponent(NewFileExplorer(t.TempDir()))
+	app.AddComponent(palette)
+
+	palette.Show()
+	palette.textInput.SetValue("?")
+	palette.filterCommands()
+
+	found := map[string]string{}
+	for _, cmd := range palette.filtered {
+		found[cmd.Name] = cmd.Keybinding
+	}
+	if found["Global: quit"] != "q" {
+		t.Errorf("Expected the global quit binding, got %v", found)
+	}
+	if found["FileExplorer: refresh"] != "r" {
+		t.Errorf("Expected the explorer's refresh binding, got %v", found)
+	}
+}
+
+func TestCodeBlockRebindHint(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "x := 1"
+	}
+	cb := NewCodeBlock(WithCode(strings.Join(lines, "\n")))
+	cb.Update(tea.WindowSizeMsg{Width: 80})
+	cb.KeyMap().Rebind("toggle", "ctrl+e")
+
+	if view := cb.View(); !strings.Contains(view, "ctrl+e to expand") {
+		t.Errorf("Collapsed hint should use the rebound key, got %q", view)
+	}
+
+	cb.Focus()
+	cb.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if !cb.expanded {
+		t.Error("Rebound key should toggle the block")
+	}
+}
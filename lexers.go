@@ -0,0 +1,546 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Lexer states shared by the built-in lexers
+const (
+	stateNormal       LexerState = iota
+	stateBlockComment            // Inside /* ... */
+	stateFence                   // Inside a Markdown fenced code block
+	stateMultiline               // Inside multi-line string delimiter 0; delimiter i is stateMultiline+i
+)
+
+const operatorChars = "+-*/%=<>!&|^~?:;,.()[]{}"
+
+func init() {
+	RegisterLexer("go", goLexer, "golang")
+	RegisterLexer("json", jsonLexer)
+	RegisterLexer("yaml", yamlLexer{values: yamlValueLexer}, "yml")
+	RegisterLexer("shell", shellLexer, "sh", "bash", "zsh", "console")
+	RegisterLexer("python", pythonLexer, "py")
+	RegisterLexer("typescript", typescriptLexer, "ts", "tsx", "javascript", "js", "jsx")
+	RegisterLexer("markdown", markdownLexer{}, "md")
+
+	for ext, lang := range map[string]string{
+		"go": "go", "json": "json", "yaml": "yaml", "yml": "yaml",
+		"sh": "shell", "bash": "shell", "zsh": "shell",
+		"py": "python", "ts": "typescript", "tsx": "typescript",
+		"js": "typescript", "jsx": "typescript", "mjs": "typescript",
+		"md": "markdown", "markdown": "markdown",
+	} {
+		RegisterExtension(ext, lang)
+	}
+}
+
+// ruleLexer is a table-driven lexer that covers C-like and scripting languages.
+// Each built-in language is a ruleLexer with its own word lists and delimiters.
+type ruleLexer struct {
+	keywords  map[string]bool
+	types     map[string]bool
+	constants map[string]bool
+	builtins  map[string]bool // Highlighted as functions even without a call
+
+	lineComments      []string
+	blockComment      [2]string // Open and close delimiters, empty if unsupported
+	commentAfterSpace bool      // Line comments only start at a word boundary (shell, YAML)
+
+	quotes    string   // Single-line string delimiters
+	multiline []string // Delimiters of strings that may span lines, longest first
+	raw       string   // Delimiters inside which backslash does not escape
+
+	variables       bool // $name and ${name} are variables
+	decorators      bool // @name is a decorator
+	keysBeforeColon bool // Strings followed by ':' are keys
+}
+
+// Tokenize implements Lexer
+func (l *ruleLexer) Tokenize(line string, state LexerState) ([]Token, LexerState) {
+	var toks tokenList
+	i := 0
+
+	// Finish constructs left open by previous lines
+	switch {
+	case state == stateBlockComment:
+		end := strings.Index(line, l.blockComment[1])
+		if end < 0 {
+			toks.add(TokenComment, line)
+			return toks.tokens, state
+		}
+		i = end + len(l.blockComment[1])
+		toks.add(TokenComment, line[:i])
+	case state >= stateMultiline && int(state-stateMultiline) < len(l.multiline):
+		end := l.findClose(line, 0, l.multiline[state-stateMultiline])
+		if end < 0 {
+			toks.add(TokenString, line)
+			return toks.tokens, state
+		}
+		i = end
+		toks.add(TokenString, line[:i])
+	}
+
+	for i < len(line) {
+		rest := line[i:]
+		c := line[i]
+
+		if l.isLineComment(line, i) {
+			toks.add(TokenComment, rest)
+			break
+		}
+
+		if open := l.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], l.blockComment[1])
+			if end < 0 {
+				toks.add(TokenComment, rest)
+				return toks.tokens, stateBlockComment
+			}
+			n := len(open) + end + len(l.blockComment[1])
+			toks.add(TokenComment, rest[:n])
+			i += n
+			continue
+		}
+
+		if k := l.multilineAt(rest); k >= 0 {
+			delim := l.multiline[k]
+			end := l.findClose(line, i+len(delim), delim)
+			if end < 0 {
+				toks.add(TokenString, rest)
+				return toks.tokens, stateMultiline + LexerState(k)
+			}
+			toks.add(TokenString, line[i:end])
+			i = end
+			continue
+		}
+
+		switch {
+		case strings.IndexByte(l.quotes, c) >= 0:
+			end := l.findClose(line, i+1, string(c))
+			if end < 0 {
+				end = len(line)
+			}
+			kind := TokenString
+			if l.keysBeforeColon && strings.HasPrefix(strings.TrimLeft(line[end:], " \t"), ":") {
+				kind = TokenKey
+			}
+			toks.add(kind, line[i:end])
+			i = end
+
+		case l.variables && c == '$' && i+1 < len(line):
+			n := variableLen(rest)
+			toks.add(TokenVariable, rest[:n])
+			i += n
+
+		case l.decorators && c == '@' && i+1 < len(line) && isIdentStart(line[i+1]):
+			n := 1 + identLen(rest[1:])
+			toks.add(TokenFunction, rest[:n])
+			i += n
+
+		case isDigit(c) || (c == '.' && i+1 < len(line) && isDigit(line[i+1])):
+			n := numberLen(rest)
+			toks.add(TokenNumber, rest[:n])
+			i += n
+
+		case isIdentStart(c):
+			n := identLen(rest)
+			toks.add(l.classify(rest[:n], rest[n:]), rest[:n])
+			i += n
+
+		case strings.IndexByte(operatorChars, c) >= 0:
+			toks.add(TokenOperator, rest[:1])
+			i++
+
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			toks.add(TokenText, rest[:size])
+			i += size
+		}
+	}
+
+	return toks.tokens, stateNormal
+}
+
+// classify decides the kind of an identifier given the text that follows it
+func (l *ruleLexer) classify(word, after string) TokenKind {
+	switch {
+	case l.keywords[word]:
+		return TokenKeyword
+	case l.constants[word]:
+		return TokenConstant
+	case l.types[word]:
+		return TokenType
+	case l.builtins[word]:
+		return TokenFunction
+	case strings.HasPrefix(strings.TrimLeft(after, " \t"), "("):
+		return TokenFunction
+	default:
+		return TokenText
+	}
+}
+
+// isLineComment reports whether a line comment starts at line[i]
+func (l *ruleLexer) isLineComment(line string, i int) bool {
+	for _, prefix := range l.lineComments {
+		if !strings.HasPrefix(line[i:], prefix) {
+			continue
+		}
+		if l.commentAfterSpace && i > 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// multilineAt returns the index of the multi-line delimiter starting s, or -1
+func (l *ruleLexer) multilineAt(s string) int {
+	for k, delim := range l.multiline {
+		if strings.HasPrefix(s, delim) {
+			return k
+		}
+	}
+	return -1
+}
+
+// findClose returns the index just past the closing delimiter at or after
+// from, honouring backslash escapes unless the delimiter is raw. It returns
+// -1 if the delimiter is not closed on this line.
+func (l *ruleLexer) findClose(line string, from int, delim string) int {
+	escapes := !strings.Contains(l.raw, delim)
+	for j := from; j < len(line); j++ {
+		if escapes && line[j] == '\\' {
+			j++
+			continue
+		}
+		if strings.HasPrefix(line[j:], delim) {
+			return j + len(delim)
+		}
+	}
+	return -1
+}
+
+// yamlLexer highlights YAML mapping keys, list markers and comments, and
+// delegates scalar values to a ruleLexer
+type yamlLexer struct {
+	values *ruleLexer
+}
+
+// Tokenize implements Lexer
+func (y yamlLexer) Tokenize(line string, state LexerState) ([]Token, LexerState) {
+	var toks tokenList
+
+	rest := strings.TrimLeft(line, " \t")
+	toks.add(TokenText, line[:len(line)-len(rest)])
+
+	switch {
+	case strings.HasPrefix(rest, "#"):
+		toks.add(TokenComment, rest)
+		return toks.tokens, stateNormal
+	case rest == "---" || rest == "...":
+		toks.add(TokenKeyword, rest)
+		return toks.tokens, stateNormal
+	}
+
+	// Sequence markers, possibly nested ("- - item")
+	for rest == "-" || strings.HasPrefix(rest, "- ") {
+		toks.add(TokenOperator, "-")
+		rest = rest[1:]
+		trimmed := strings.TrimLeft(rest, " ")
+		toks.add(TokenText, rest[:len(rest)-len(trimmed)])
+		rest = trimmed
+	}
+
+	if k := yamlKeyEnd(rest); k > 0 {
+		toks.add(TokenKey, rest[:k])
+		toks.add(TokenOperator, ":")
+		rest = rest[k+1:]
+	}
+
+	values, _ := y.values.Tokenize(rest, stateNormal)
+	for _, tok := range values {
+		toks.add(tok.Kind, tok.Text)
+	}
+	return toks.tokens, stateNormal
+}
+
+// yamlKeyEnd returns the index of the colon ending a mapping key, or -1
+func yamlKeyEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i > 0 {
+				return -1 // Quotes only delimit keys when they start them
+			}
+			quote = c
+		case c == '#' && i > 0 && s[i-1] == ' ':
+			return -1
+		case c == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t'):
+			return i
+		}
+	}
+	return -1
+}
+
+// markdownLexer highlights block structure (headings, quotes, lists, fences)
+// and inline spans (code, emphasis, links)
+type markdownLexer struct{}
+
+// Tokenize implements Lexer
+func (markdownLexer) Tokenize(line string, state LexerState) ([]Token, LexerState) {
+	var toks tokenList
+	trimmed := strings.TrimLeft(line, " ")
+
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		toks.add(TokenString, line)
+		if state == stateFence {
+			return toks.tokens, stateNormal
+		}
+		return toks.tokens, stateFence
+	}
+	if state == stateFence {
+		toks.add(TokenString, line)
+		return toks.tokens, state
+	}
+
+	switch {
+	case isMarkdownHeading(trimmed):
+		toks.add(TokenHeading, line)
+		return toks.tokens, stateNormal
+	case strings.HasPrefix(trimmed, ">"):
+		toks.add(TokenComment, line)
+		return toks.tokens, stateNormal
+	case trimmed == "---" || trimmed == "***" || trimmed == "___":
+		toks.add(TokenOperator, line)
+		return toks.tokens, stateNormal
+	}
+
+	toks.add(TokenText, line[:len(line)-len(trimmed)])
+	if n := markdownListMarker(trimmed); n > 0 {
+		toks.add(TokenKeyword, trimmed[:n])
+		trimmed = trimmed[n:]
+	}
+
+	markdownInline(&toks, trimmed)
+	return toks.tokens, stateNormal
+}
+
+// isMarkdownHeading reports whether s is an ATX heading ("# Title")
+func isMarkdownHeading(s string) bool {
+	n := 0
+	for n < len(s) && s[n] == '#' {
+		n++
+	}
+	return n >= 1 && n <= 6 && (n == len(s) || s[n] == ' ')
+}
+
+// markdownListMarker returns the length of a list marker ("- ", "1. ") at the
+// start of s, or 0
+func markdownListMarker(s string) int {
+	if len(s) >= 2 && strings.IndexByte("-*+", s[0]) >= 0 && s[1] == ' ' {
+		return 2
+	}
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	if n > 0 && n+1 < len(s) && (s[n] == '.' || s[n] == ')') && s[n+1] == ' ' {
+		return n + 2
+	}
+	return 0
+}
+
+// markdownInline tokenizes inline code, emphasis and links
+func markdownInline(toks *tokenList, s string) {
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				toks.add(TokenString, rest[:end+2])
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				toks.add(TokenEmphasis, rest[:end+4])
+				i += end + 4
+				continue
+			}
+		case rest[0] == '*' || (rest[0] == '_' && (i == 0 || !isIdentChar(s[i-1]))):
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 {
+				toks.add(TokenEmphasis, rest[:end+2])
+				i += end + 2
+				continue
+			}
+		case rest[0] == '[':
+			if mid := strings.Index(rest, "]("); mid > 0 {
+				if end := strings.IndexByte(rest[mid:], ')'); end > 0 {
+					toks.add(TokenLink, rest[:mid+end+1])
+					i += mid + end + 1
+					continue
+				}
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		toks.add(TokenText, rest[:size])
+		i += size
+	}
+}
+
+// tokenList accumulates tokens, merging adjacent tokens of the same kind
+type tokenList struct {
+	tokens []Token
+}
+
+func (t *tokenList) add(kind TokenKind, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(t.tokens); n > 0 && t.tokens[n-1].Kind == kind {
+		t.tokens[n-1].Text += text
+		return
+	}
+	t.tokens = append(t.tokens, Token{Kind: kind, Text: text})
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+// identLen returns the length of the identifier at the start of s
+func identLen(s string) int {
+	n := 0
+	for n < len(s) && isIdentChar(s[n]) {
+		n++
+	}
+	return n
+}
+
+// numberLen returns the length of the numeric literal at the start of s,
+// including hex/octal/binary prefixes, separators, fractions and exponents
+func numberLen(s string) int {
+	n := 0
+	for n < len(s) {
+		c := s[n]
+		switch {
+		case isIdentChar(c) || c == '.':
+			n++
+		case (c == '+' || c == '-') && n > 0 && (s[n-1] == 'e' || s[n-1] == 'E') && !strings.HasPrefix(s, "0x"):
+			n++
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// variableLen returns the length of a shell variable reference at the start of s
+func variableLen(s string) int {
+	if strings.HasPrefix(s, "${") {
+		if end := strings.IndexByte(s, '}'); end > 0 {
+			return end + 1
+		}
+		return len(s)
+	}
+	if n := identLen(s[1:]); n > 0 {
+		return 1 + n
+	}
+	// Special parameters such as $?, $#, $1
+	return min(2, len(s))
+}
+
+// words builds a lookup set from a space-separated list
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var goLexer = &ruleLexer{
+	keywords: words(`break case chan const continue default defer else fallthrough for func go goto
+		if import interface map package range return select struct switch type var`),
+	types: words(`any bool byte comparable complex64 complex128 error float32 float64 int int8 int16
+		int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr`),
+	constants: words(`true false nil iota`),
+	builtins: words(`append cap clear close complex copy delete imag len make max min new panic
+		print println real recover`),
+	lineComments: []string{"//"},
+	blockComment: [2]string{"/*", "*/"},
+	quotes:       `"'`,
+	multiline:    []string{"`"},
+	raw:          "`",
+}
+
+var typescriptLexer = &ruleLexer{
+	keywords: words(`abstract as async await break case catch class const continue debugger declare
+		default delete do else enum export extends finally for from function get if implements
+		import in instanceof interface keyof let namespace new of private protected public
+		readonly return set static super switch throw try type typeof var void while with yield`),
+	types: words(`any bigint boolean never number object string symbol unknown
+		Array Map Promise Record Set`),
+	constants:    words(`true false null undefined this NaN Infinity`),
+	lineComments: []string{"//"},
+	blockComment: [2]string{"/*", "*/"},
+	quotes:       `"'`,
+	multiline:    []string{"`"},
+	decorators:   true,
+}
+
+var pythonLexer = &ruleLexer{
+	keywords: words(`and as assert async await break class continue def del elif else except
+		finally for from global if import in is lambda match nonlocal not or pass raise return
+		try while with yield`),
+	types:        words(`bool bytes dict float frozenset int list object set str tuple type`),
+	constants:    words(`True False None self cls`),
+	builtins:     words(`abs all any enumerate filter isinstance len map max min open print range sorted sum zip`),
+	lineComments: []string{"#"},
+	quotes:       `"'`,
+	multiline:    []string{`"""`, `'''`},
+	decorators:   true,
+}
+
+var shellLexer = &ruleLexer{
+	keywords: words(`case do done elif else esac fi for function if in local return select then
+		until while export readonly`),
+	builtins: words(`alias cd echo eval exec exit printf pwd read set shift source test trap
+		unset`),
+	constants:         words(`true false`),
+	lineComments:      []string{"#"},
+	commentAfterSpace: true,
+	quotes:            `"'`,
+	raw:               "'",
+	variables:         true,
+}
+
+var jsonLexer = &ruleLexer{
+	constants:       words(`true false null`),
+	quotes:          `"`,
+	keysBeforeColon: true,
+}
+
+var yamlValueLexer = &ruleLexer{
+	constants:         words(`true false null yes no on off True False Null Yes No On Off`),
+	lineComments:      []string{"#"},
+	commentAfterSpace: true,
+	quotes:            `"'`,
+	raw:               "'",
+}
@@ -0,0 +1,770 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/SCKelemen/tui/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MarkdownBlock renders Markdown prose, such as an assistant's response.
+// It supports headings, emphasis, inline code, links, bullet and numbered
+// lists, block quotes, tables, horizontal rules and fenced code. Code is
+// highlighted like a CodeBlock and tables are drawn with the table package.
+//
+// Text is wrapped to the width from the last tea.WindowSizeMsg and reflows
+// when it changes. Responses can be streamed in with Append: only the blocks
+// at the end of the text are parsed and rendered again, so earlier
+// paragraphs cost nothing as the response grows.
+//
+// Example usage:
+//
+//	md := tui.NewMarkdownBlock("")
+//	for chunk := range tokens {
+//		md.Append(chunk)
+//	}
+type MarkdownBlock struct {
+	width   int
+	height  int
+	focused bool
+
+	source []byte
+	blocks []mdBlock
+	syntax SyntaxTheme // Highlight colors for fenced code (nil = default theme)
+
+	// Rendered lines of each block, reused while a block's source and the
+	// width stay the same
+	rendered    []mdRendered
+	renderWidth int
+}
+
+// MarkdownOption configures a MarkdownBlock
+type MarkdownOption func(*MarkdownBlock)
+
+// WithMarkdownDesignTokens applies design-system colors to fenced code
+func WithMarkdownDesignTokens(tokens *design.DesignTokens) MarkdownOption {
+	return func(mb *MarkdownBlock) {
+		mb.syntax = SyntaxThemeFromTokens(tokens)
+	}
+}
+
+// WithMarkdownTheme applies a named design-system theme to fenced code
+func WithMarkdownTheme(theme string) MarkdownOption {
+	return func(mb *MarkdownBlock) {
+		mb.syntax = SyntaxThemeFromTokens(designTokensForTheme(theme))
+	}
+}
+
+// NewMarkdownBlock creates a block that renders source as Markdown
+func NewMarkdownBlock(source string, opts ...MarkdownOption) *MarkdownBlock {
+	mb := &MarkdownBlock{}
+
+	for _, opt := range opts {
+		opt(mb)
+	}
+
+	mb.SetContent(source)
+	return mb
+}
+
+// Init initializes the block
+func (mb *MarkdownBlock) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (mb *MarkdownBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		mb.width = msg.Width
+		mb.height = msg.Height
+	}
+	return mb, nil
+}
+
+// View renders the Markdown, wrapped to the current width
+func (mb *MarkdownBlock) View() string {
+	if mb.width != mb.renderWidth {
+		mb.rendered = nil
+		mb.renderWidth = mb.width
+	}
+
+	rendered := make([]mdRendered, len(mb.blocks))
+	var out []string
+	for i, b := range mb.blocks {
+		if i < len(mb.rendered) && mb.rendered[i].raw == b.raw {
+			rendered[i] = mb.rendered[i]
+		} else {
+			rendered[i] = mdRendered{raw: b.raw, lines: mb.renderBlock(b, mb.width)}
+		}
+		if i > 0 {
+			out = append(out, "")
+		}
+		out = append(out, rendered[i].lines...)
+	}
+	mb.rendered = rendered
+
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// Focus is called when this component receives focus
+func (mb *MarkdownBlock) Focus() {
+	mb.focused = true
+}
+
+// Blur is called when this component loses focus
+func (mb *MarkdownBlock) Blur() {
+	mb.focused = false
+}
+
+// Focused returns whether this component is currently focused
+func (mb *MarkdownBlock) Focused() bool {
+	return mb.focused
+}
+
+// SetContent replaces the Markdown source
+func (mb *MarkdownBlock) SetContent(source string) {
+	mb.source = []byte(source)
+	mb.blocks = nil
+	mb.reparse()
+}
+
+// Append adds streamed text to the end of the source
+func (mb *MarkdownBlock) Append(chunk string) {
+	if chunk == "" {
+		return
+	}
+	mb.source = append(mb.source, chunk...)
+	mb.reparse()
+}
+
+// Content returns the Markdown source
+func (mb *MarkdownBlock) Content() string {
+	return string(mb.source)
+}
+
+// reparse parses the end of the source again. Appended text can only change
+// the last two blocks (a partial line may yet turn out to start a new block
+// or continue the previous one), so earlier blocks are kept.
+func (mb *MarkdownBlock) reparse() {
+	keep := max(0, len(mb.blocks)-2)
+	from := 0
+	if keep > 0 {
+		from = mb.blocks[keep].start
+	}
+	mb.blocks = append(mb.blocks[:keep], parseMarkdown(string(mb.source[from:]), from)...)
+}
+
+// mdKind identifies the kind of a Markdown block
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdTable
+	mdRule
+)
+
+// mdBlock is a parsed block-level Markdown element
+type mdBlock struct {
+	kind  mdKind
+	start int    // Byte offset of the block in the source
+	raw   string // Source of the block
+
+	level   int        // Heading level
+	lang    string     // Fenced code language
+	lines   []string   // Paragraph, heading, code or quote content
+	items   [][]string // List items, as lines with the marker removed
+	ordered bool       // Numbered list
+	number  int        // First number of a numbered list
+	header  []string   // Table header cells
+	rows    [][]string // Table body cells
+}
+
+// mdRendered caches the rendered lines of a block
+type mdRendered struct {
+	raw   string
+	lines []string
+}
+
+// parseMarkdown splits src into blocks. base is the offset of src in the
+// full source, recorded as each block's start.
+func parseMarkdown(src string, base int) []mdBlock {
+	lines := strings.Split(src, "\n")
+	offsets := make([]int, len(lines))
+	offset := base
+	for i, line := range lines {
+		offsets[i] = offset
+		offset += len(line) + 1
+	}
+
+	var blocks []mdBlock
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			i++
+			continue
+		}
+
+		first := i
+		b := mdBlock{start: offsets[i]}
+
+		switch {
+		case fenceMarker(trimmed) != "":
+			fence := fenceMarker(trimmed)
+			b.kind = mdCode
+			b.lang = strings.TrimSpace(strings.TrimPrefix(trimmed, fence))
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					i++
+					break
+				}
+				b.lines = append(b.lines, lines[i])
+			}
+
+		case headingLevel(trimmed) > 0:
+			b.kind = mdHeading
+			b.level = headingLevel(trimmed)
+			text := strings.TrimSpace(trimmed[b.level:])
+			b.lines = []string{strings.TrimSpace(strings.TrimRight(text, "#"))}
+			i++
+
+		case isRule(trimmed):
+			b.kind = mdRule
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			b.kind = mdQuote
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					break
+				}
+				t = strings.TrimPrefix(t, ">")
+				b.lines = append(b.lines, strings.TrimPrefix(t, " "))
+			}
+
+		case isListItem(lines[i]):
+			b.kind = mdList
+			i = parseList(lines, i, &b)
+
+		case i+1 < len(lines) && strings.Contains(trimmed, "|") && isTableSeparator(lines[i+1]):
+			b.kind = mdTable
+			b.header = splitTableRow(trimmed)
+			for i += 2; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if t == "" || !strings.Contains(t, "|") {
+					break
+				}
+				b.rows = append(b.rows, splitTableRow(t))
+			}
+
+		default:
+			b.kind = mdParagraph
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if t == "" || (i > first && startsBlock(lines, i)) {
+					break
+				}
+				b.lines = append(b.lines, t)
+			}
+		}
+
+		b.raw = strings.Join(lines[first:i], "\n")
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// parseList collects the items of the list starting at lines[i] and returns
+// the index of the first line after it. Lines indented past an item's marker
+// belong to the item, so nested lists and paragraphs are parsed from them.
+func parseList(lines []string, i int, b *mdBlock) int {
+	indent, marker, ordered, number := listMarker(lines[i])
+	b.ordered = ordered
+	b.number = number
+	content := indent + len(marker) + 1
+
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			// A blank line continues the list only if the list does
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next == len(lines) || !continuesList(lines[next], indent, content, ordered) {
+				return i
+			}
+			b.items[len(b.items)-1] = append(b.items[len(b.items)-1], "")
+			i++
+			continue
+		}
+
+		if ind, m, o, _ := listMarker(line); m != "" && o == ordered && ind < content {
+			if ind < indent {
+				return i
+			}
+			content = ind + len(m) + 1
+			b.items = append(b.items, []string{strings.TrimSpace(strings.TrimLeft(line, " \t")[len(m):])})
+			i++
+			continue
+		}
+
+		switch {
+		case leadingSpaces(line) >= content:
+			line = dedent(line, content)
+		case startsBlock(lines, i) || strings.TrimSpace(lines[i-1]) == "":
+			return i
+		default:
+			// Lazy continuation of the item's last paragraph
+			line = strings.TrimSpace(line)
+		}
+		b.items[len(b.items)-1] = append(b.items[len(b.items)-1], line)
+		i++
+	}
+	return i
+}
+
+// continuesList reports whether line, following blank lines, is still part
+// of a list whose markers are at indent and content at content
+func continuesList(line string, indent, content int, ordered bool) bool {
+	if leadingSpaces(line) >= content {
+		return true
+	}
+	ind, m, o, _ := listMarker(line)
+	return m != "" && o == ordered && ind >= indent
+}
+
+// startsBlock reports whether lines[i] begins a block that interrupts a
+// paragraph
+func startsBlock(lines []string, i int) bool {
+	t := strings.TrimSpace(lines[i])
+	if fenceMarker(t) != "" || headingLevel(t) > 0 || isRule(t) || strings.HasPrefix(t, ">") || isListItem(lines[i]) {
+		return true
+	}
+	return i+1 < len(lines) && strings.Contains(t, "|") && isTableSeparator(lines[i+1])
+}
+
+// fenceMarker returns the ``` or ~~~ that opens a code fence, or ""
+func fenceMarker(line string) string {
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, fence) {
+			return fence
+		}
+	}
+	return ""
+}
+
+// headingLevel returns the level of an ATX heading ("## Title"), or 0
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// isRule reports whether line is a thematic break such as --- or * * *
+func isRule(line string) bool {
+	compact := strings.ReplaceAll(line, " ", "")
+	if len(compact) < 3 || !strings.ContainsRune("-*_", rune(compact[0])) {
+		return false
+	}
+	return strings.Count(compact, compact[:1]) == len(compact)
+}
+
+// isListItem reports whether line starts with a list marker
+func isListItem(line string) bool {
+	_, marker, _, _ := listMarker(line)
+	return marker != ""
+}
+
+// listMarker returns the indent and marker ("-", "*", "+", "1." or "1)") of
+// a list item, whether it is numbered and its number. The marker is empty
+// when line is not a list item.
+func listMarker(line string) (indent int, marker string, ordered bool, number int) {
+	indent = leadingSpaces(line)
+	rest := strings.TrimLeft(line, " \t")
+
+	if len(rest) >= 1 && strings.ContainsRune("-*+", rune(rest[0])) {
+		if len(rest) == 1 || rest[1] == ' ' {
+			return indent, rest[:1], false, 0
+		}
+		return indent, "", false, 0
+	}
+
+	digits := 0
+	for digits < len(rest) && digits < 9 && rest[digits] >= '0' && rest[digits] <= '9' {
+		number = number*10 + int(rest[digits]-'0')
+		digits++
+	}
+	if digits == 0 || digits >= len(rest) || (rest[digits] != '.' && rest[digits] != ')') {
+		return indent, "", false, 0
+	}
+	if digits+1 < len(rest) && rest[digits+1] != ' ' {
+		return indent, "", false, 0
+	}
+	return indent, rest[:digits+1], true, number
+}
+
+// leadingSpaces counts the spaces at the start of line, a tab counting as 4
+func leadingSpaces(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// dedent removes n columns of indentation from line
+func dedent(line string, n int) string {
+	for n > 0 && line != "" {
+		switch line[0] {
+		case ' ':
+			n--
+		case '\t':
+			n -= 4
+		default:
+			return line
+		}
+		line = line[1:]
+	}
+	return line
+}
+
+// isTableSeparator reports whether line is a table's |---|:---:| row
+func isTableSeparator(line string) bool {
+	t := strings.TrimSpace(line)
+	if !strings.Contains(t, "-") {
+		return false
+	}
+	for _, r := range t {
+		if !strings.ContainsRune("|-: ", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTableRow splits a | separated table row into trimmed cells
+func splitTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// renderBlocks renders nested blocks, separating them with blank lines
+// unless tight is set
+func (mb *MarkdownBlock) renderBlocks(blocks []mdBlock, width int, tight bool) []string {
+	var out []string
+	for i, b := range blocks {
+		if i > 0 && !tight {
+			out = append(out, "")
+		}
+		out = append(out, mb.renderBlock(b, width)...)
+	}
+	return out
+}
+
+// renderBlock renders one block as lines at most width columns wide, apart
+// from code and tables which are not wrapped. A width of 0 disables wrapping.
+func (mb *MarkdownBlock) renderBlock(b mdBlock, width int) []string {
+	switch b.kind {
+	case mdHeading:
+		style := "\033[1m"
+		if b.level == 1 {
+			style += "\033[4m"
+		} else if b.level > 2 {
+			style += "\033[3m"
+		}
+		return wrapSpans(parseInline(b.lines[0], style), width)
+
+	case mdCode:
+		if len(b.lines) == 0 {
+			return nil
+		}
+		cb := NewCodeBlock(WithCodeLines(b.lines), WithLanguage(b.lang))
+		cb.syntax = mb.syntax
+		return strings.Split(strings.TrimSuffix(cb.renderExpanded(), "\n"), "\n")
+
+	case mdQuote:
+		inner := mb.renderBlocks(parseMarkdown(strings.Join(b.lines, "\n"), 0), max(0, width-2), false)
+		for i, line := range inner {
+			inner[i] = "\033[2m│\033[0m " + line
+		}
+		return inner
+
+	case mdList:
+		return mb.renderList(b, width)
+
+	case mdTable:
+		t := table.New(plainInline(b.header)...)
+		for _, row := range b.rows {
+			t.AddRow(plainInline(row)...)
+		}
+		return strings.Split(t.RenderSimple(), "\n")
+
+	case mdRule:
+		if width <= 0 {
+			width = markdownRuleWidth
+		}
+		return []string{"\033[2m" + strings.Repeat("─", width) + "\033[0m"}
+	}
+
+	return wrapSpans(parseInline(strings.Join(b.lines, " "), ""), width)
+}
+
+// markdownRuleWidth is the width of horizontal rules before the block knows
+// the terminal width
+const markdownRuleWidth = 40
+
+// renderList renders list items behind bullets or numbers, indenting their
+// continuation lines
+func (mb *MarkdownBlock) renderList(b mdBlock, width int) []string {
+	markers := make([]string, len(b.items))
+	markerWidth := 1
+	for i := range b.items {
+		markers[i] = "•"
+		if b.ordered {
+			markers[i] = fmt.Sprintf("%d.", b.number+i)
+		}
+		markerWidth = max(markerWidth, textwidth.Width(markers[i]))
+	}
+
+	var out []string
+	for i, item := range b.items {
+		content := mb.renderBlocks(parseMarkdown(strings.Join(item, "\n"), 0), max(0, width-markerWidth-1), true)
+		if len(content) == 0 {
+			content = []string{""}
+		}
+		for j, line := range content {
+			prefix := strings.Repeat(" ", markerWidth+1)
+			if j == 0 {
+				prefix = fmt.Sprintf("%-*s ", markerWidth, markers[i])
+			}
+			out = append(out, prefix+line)
+		}
+	}
+	return out
+}
+
+// mdSpan is a run of inline text with one style
+type mdSpan struct {
+	text  string
+	style string // ANSI codes applied to text, "" for none
+}
+
+// parseInline splits text into styled spans for **strong**, *emphasis*,
+// ~~strikethrough~~, `code` and [links](url). Markers that are not closed,
+// for example while a response is still streaming, are kept as text.
+func parseInline(text, style string) []mdSpan {
+	var spans []mdSpan
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, mdSpan{text: plain.String(), style: style})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && unicode.IsPunct(rune(text[i+1])):
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				flush()
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				spans = append(spans, mdSpan{text: code, style: style + "\033[36m"})
+				i += 2*ticks + end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			delim := rest[:2]
+			if end := strings.Index(rest[2:], delim); end > 0 {
+				flush()
+				inner := "\033[1m"
+				if delim == "~~" {
+					inner = "\033[9m"
+				}
+				spans = append(spans, parseInline(rest[2:2+end], style+inner)...)
+				i += 4 + end
+				continue
+			}
+
+		case (c == '*' || c == '_') && (c == '*' || i == 0 || !isWordByte(text[i-1])):
+			if end := closingEmphasis(rest[1:], c); end > 0 {
+				flush()
+				spans = append(spans, parseInline(rest[1:1+end], style+"\033[3m")...)
+				i += 2 + end
+				continue
+			}
+
+		case c == '[':
+			if label, url, n, ok := parseLink(rest); ok {
+				flush()
+				spans = append(spans, parseInline(label, style+"\033[4m\033[34m")...)
+				if url != label {
+					spans = append(spans, mdSpan{text: " (" + url + ")", style: style + "\033[2m"})
+				}
+				i += n
+				continue
+			}
+		}
+
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+	return spans
+}
+
+// closingEmphasis returns the index in s of the single * or _ closing an
+// emphasis span, or -1
+func closingEmphasis(s string, delim byte) int {
+	if s == "" || s[0] == ' ' {
+		return -1
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != delim || s[i-1] == ' ' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == delim {
+			i++
+			continue
+		}
+		if delim == '_' && i+1 < len(s) && isWordByte(s[i+1]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// parseLink parses [label](url) at the start of s, returning the number of
+// bytes it spans
+func parseLink(s string) (label, url string, n int, ok bool) {
+	mid := strings.Index(s, "](")
+	if mid < 0 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[mid+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	return s[1:mid], s[mid+2 : mid+2+end], mid + 3 + end, true
+}
+
+// isWordByte reports whether b is a letter or digit
+func isWordByte(b byte) bool {
+	return b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// plainInline returns cells with inline Markdown removed
+func plainInline(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, cell := range cells {
+		var b strings.Builder
+		for _, span := range parseInline(cell, "") {
+			b.WriteString(span.text)
+		}
+		out[i] = b.String()
+	}
+	return out
+}
+
+// mdWord is a run of non-space text, possibly mixing styles as in **bold**,
+type mdWord struct {
+	spans []mdSpan
+	width int
+}
+
+// wrapSpans word-wraps styled spans to width columns. Each styled run is
+// reset at its end, so lines can be cut or composed without styles leaking.
+// Words longer than width get a line of their own.
+func wrapSpans(spans []mdSpan, width int) []string {
+	var words []mdWord
+	var word mdWord
+	endWord := func() {
+		if len(word.spans) == 0 {
+			return
+		}
+		for _, span := range word.spans {
+			word.width += textwidth.Width(span.text)
+		}
+		words = append(words, word)
+		word = mdWord{}
+	}
+	for _, span := range spans {
+		for _, r := range span.text {
+			if unicode.IsSpace(r) {
+				endWord()
+				continue
+			}
+			if n := len(word.spans); n > 0 && word.spans[n-1].style == span.style {
+				word.spans[n-1].text += string(r)
+			} else {
+				word.spans = append(word.spans, mdSpan{text: string(r), style: span.style})
+			}
+		}
+	}
+	endWord()
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, w := range words {
+		if lineWidth > 0 && width > 0 && lineWidth+1+w.width > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+		for _, span := range w.spans {
+			if span.style == "" {
+				line.WriteString(span.text)
+				continue
+			}
+			line.WriteString(span.style + span.text + "\033[0m")
+		}
+		lineWidth += w.width
+	}
+	if lineWidth > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
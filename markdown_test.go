@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func renderMarkdown(source string, width int) []string {
+	mb := NewMarkdownBlock(source)
+	mb.Update(tea.WindowSizeMsg{Width: width, Height: 40})
+	return strings.Split(strings.TrimSuffix(textwidth.Strip(mb.View()), "\n"), "\n")
+}
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	src := strings.Join([]string{
+		"# Title",
+		"Some *prose*",
+		"continued.",
+		"",
+		"- one",
+		"- two",
+		"  - nested",
+		"",
+		"> quoted",
+		"",
+		"```go",
+		"func main() {}",
+		"```",
+		"| a | b |",
+		"|---|---|",
+		"| 1 | 2 |",
+		"---",
+	}, "\n")
+
+	var kinds []mdKind
+	for _, b := range parseMarkdown(src, 0) {
+		kinds = append(kinds, b.kind)
+	}
+	want := []mdKind{mdHeading, mdParagraph, mdList, mdQuote, mdCode, mdTable, mdRule}
+	if len(kinds) != len(want) {
+		t.Fatalf("parsed kinds %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("block %d kind = %d, want %d", i, kinds[i], want[i])
+		}
+	}
+
+	list := parseMarkdown(src, 0)[2]
+	if len(list.items) != 2 || strings.Join(list.items[1], "|") != "two|- nested" {
+		t.Errorf("list items = %q", list.items)
+	}
+}
+
+func TestParseInlineStyles(t *testing.T) {
+	spans := parseInline("a **bold** and *em* with `x*y` see [docs](http://x.io) **open", "")
+	var texts []string
+	for _, s := range spans {
+		texts = append(texts, s.text)
+	}
+	want := []string{"a ", "bold", " and ", "em", " with ", "x*y", " see ", "docs", " (http://x.io)", " **open"}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Fatalf("spans = %q, want %q", texts, want)
+	}
+	if spans[1].style != "\033[1m" || spans[3].style != "\033[3m" {
+		t.Errorf("bold and emphasis styles = %q, %q", spans[1].style, spans[3].style)
+	}
+	if snake := parseInline("snake_case_name", ""); len(snake) != 1 {
+		t.Errorf("Underscores inside words should not emphasize, got %d spans", len(snake))
+	}
+}
+
+func TestMarkdownBlockReflows(t *testing.T) {
+	mb := NewMarkdownBlock("The quick brown fox jumps over the lazy dog")
+	mb.Update(tea.WindowSizeMsg{Width: 20, Height: 10})
+	narrow := strings.Split(strings.TrimSuffix(mb.View(), "\n"), "\n")
+	for _, line := range narrow {
+		if n := textwidth.Width(line); n > 20 {
+			t.Errorf("line %q is %d columns, want at most 20", line, n)
+		}
+	}
+
+	mb.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	if wide := strings.Split(strings.TrimSuffix(mb.View(), "\n"), "\n"); len(wide) != 1 || len(narrow) < 3 {
+		t.Errorf("Text should reflow on resize, got %d then %d lines", len(narrow), len(wide))
+	}
+}
+
+func TestMarkdownBlockRendersElements(t *testing.T) {
+	lines := renderMarkdown("## Plan\n\n1. Read\n2. Write the **tests**\n\n> Note\n\n```go\nx := 1\n```\n\n| Name | Age |\n|------|-----|\n| Ann  | 30  |", 40)
+	view := strings.Join(lines, "\n")
+
+	for _, want := range []string{"Plan", "1. Read", "2. Write the tests", "│ Note", "x := 1", "│ Ann"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View should contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "**") || strings.Contains(view, "```") {
+		t.Errorf("Markup should not be shown, got:\n%s", view)
+	}
+}
+
+func TestMarkdownBlockStreaming(t *testing.T) {
+	full := "# Answer\n\nFirst paragraph.\n\n- item one\n- item two\n\n```sh\nls -la\n```\n\nDone."
+	mb := NewMarkdownBlock("")
+	mb.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	for _, r := range full {
+		mb.Append(string(r))
+		mb.View()
+	}
+
+	want := NewMarkdownBlock(full)
+	want.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	if mb.View() != want.View() {
+		t.Errorf("Streamed output differs from rendering at once:\n%s\nwant:\n%s", mb.View(), want.View())
+	}
+	if mb.Content() != full {
+		t.Errorf("Content() = %q", mb.Content())
+	}
+
+	// Blocks before the end are rendered once and reused
+	first := &mb.rendered[0].lines[0]
+	mb.Append(" More.")
+	mb.View()
+	if &mb.rendered[0].lines[0] != first {
+		t.Error("Appending should not re-render earlier blocks")
+	}
+}
@@ -2,8 +2,9 @@ package tui
 
 import (
 	"strings"
-	"unicode/utf8"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -41,6 +42,8 @@ type Modal struct {
 	hasInput   bool
 	onConfirm  func(string) tea.Cmd
 	onCancel   func() tea.Cmd
+	keys       *KeyMap
+	hits       HitMap // Button positions from the last render
 }
 
 // ModalOption configures a Modal
@@ -96,6 +99,24 @@ func WithModalOnCancel(fn func() tea.Cmd) ModalOption {
 	}
 }
 
+// WithModalKeyMap replaces the default keymap
+func WithModalKeyMap(km *KeyMap) ModalOption {
+	return func(m *Modal) {
+		m.keys = km
+	}
+}
+
+// DefaultModalKeyMap returns the default Modal bindings for the actions
+// "cancel", "activate", "next" and "prev"
+func DefaultModalKeyMap() *KeyMap {
+	km := NewKeyMap("Modal")
+	km.Bind("cancel", key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")))
+	km.Bind("activate", key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")))
+	km.Bind("next", key.NewBinding(key.WithKeys("tab", "right"), key.WithHelp("tab", "next button")))
+	km.Bind("prev", key.NewBinding(key.WithKeys("shift+tab", "left"), key.WithHelp("shift+tab", "previous button")))
+	return km
+}
+
 // NewModal creates a new modal dialog
 func NewModal(opts ...ModalOption) *Modal {
 	ti := textinput.New()
@@ -107,6 +128,7 @@ func NewModal(opts ...ModalOption) *Modal {
 		textInput: ti,
 		visible:   false,
 		modalType: ModalAlert,
+		keys:      DefaultModalKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -159,8 +181,9 @@ func (m *Modal) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.Type {
-		case tea.KeyEsc:
+		action, _ := m.keys.Lookup(msg)
+		switch action {
+		case "cancel":
 			// Cancel/close modal
 			m.Hide()
 			if m.onCancel != nil {
@@ -168,22 +191,11 @@ func (m *Modal) Update(msg tea.Msg) (Component, tea.Cmd) {
 			}
 			return m, nil
 
-		case tea.KeyEnter:
+		case "activate":
 			// Activate selected button
-			if m.selected < len(m.buttons) {
-				btn := m.buttons[m.selected]
-				value := ""
-				if m.hasInput {
-					value = m.textInput.Value()
-				}
-				m.Hide()
-				if btn.Action != nil {
-					return m, btn.Action(value)
-				}
-			}
-			return m, nil
+			return m, m.activate(m.selected)
 
-		case tea.KeyTab, tea.KeyRight:
+		case "next":
 			// Move to next button
 			if m.selected < len(m.buttons)-1 {
 				m.selected++
@@ -192,7 +204,7 @@ func (m *Modal) Update(msg tea.Msg) (Component, tea.Cmd) {
 			}
 			return m, nil
 
-		case tea.KeyShiftTab, tea.KeyLeft:
+		case "prev":
 			// Move to previous button
 			if m.selected > 0 {
 				m.selected--
@@ -208,6 +220,16 @@ func (m *Modal) Update(msg tea.Msg) (Component, tea.Cmd) {
 				return m, cmd
 			}
 		}
+
+	case tea.MouseMsg:
+		if !m.visible || !isLeftClick(msg) {
+			return m, nil
+		}
+		if zone, ok := m.hits.Test(msg.X, msg.Y); ok && zone.ID == "button" {
+			m.selected = zone.Index
+			return m, m.activate(zone.Index)
+		}
+		return m, nil
 	}
 
 	// Update text input if focused and has input
@@ -225,6 +247,7 @@ func (m *Modal) View() string {
 	}
 
 	var b strings.Builder
+	m.hits.Reset()
 
 	// Calculate dimensions - ensure we don't exceed terminal width
 	modalWidth := min(60, m.width-4)
@@ -246,11 +269,11 @@ func (m *Modal) View() string {
 	}
 	titleText := "── " + title + " "
 	b.WriteString(titleText)
-	// Calculate remaining width using rune count for visual width
+	// Calculate remaining width from the title's display width
 	// Line structure: ╭─<titleText><dashes>╮
 	// Total = 1(╭) + 1(─) + titleWidth + dashes + 1(╮) = modalWidth
 	// So: dashes = modalWidth - titleWidth - 3
-	titleWidth := utf8.RuneCountInString(titleText)
+	titleWidth := textwidth.Width(titleText)
 	remainingWidth := modalWidth - titleWidth - 3
 	if remainingWidth > 0 {
 		b.WriteString(strings.Repeat("─", remainingWidth))
@@ -268,8 +291,8 @@ func (m *Modal) View() string {
 		b.WriteString(strings.Repeat(" ", startX))
 		b.WriteString("│ ")
 		b.WriteString(line)
-		// Pad to width using rune count for visual width
-		lineWidth := utf8.RuneCountInString(line)
+		// Pad to width
+		lineWidth := textwidth.Width(line)
 		if lineWidth < modalWidth-4 {
 			b.WriteString(strings.Repeat(" ", modalWidth-4-lineWidth))
 		}
@@ -288,8 +311,7 @@ func (m *Modal) View() string {
 		b.WriteString("│ ")
 		inputView := m.textInput.View()
 		b.WriteString(inputView)
-		// Use rune count for visual width after stripping ANSI codes
-		inputLen := utf8.RuneCountInString(stripANSI(inputView))
+		inputLen := textwidth.Width(inputView)
 		if inputLen < modalWidth-4 {
 			b.WriteString(strings.Repeat(" ", modalWidth-4-inputLen))
 		}
@@ -306,10 +328,10 @@ func (m *Modal) View() string {
 	b.WriteString(strings.Repeat(" ", startX))
 	b.WriteString("│")
 
-	// Calculate button layout (using rune count for visual width)
+	// Calculate button layout
 	totalButtonWidth := 0
 	for _, btn := range m.buttons {
-		totalButtonWidth += utf8.RuneCountInString(btn.Label) + 4 // [Label] with brackets
+		totalButtonWidth += textwidth.Width(btn.Label) + 4 // [Label] with brackets
 	}
 	// Add spacing between buttons (2 spaces between each)
 	if len(m.buttons) > 1 {
@@ -322,7 +344,13 @@ func (m *Modal) View() string {
 	}
 	b.WriteString(strings.Repeat(" ", buttonStartX))
 
+	buttonRow := strings.Count(b.String(), "\n")
+	buttonX := startX + 1 + buttonStartX
 	for i, btn := range m.buttons {
+		buttonWidth := textwidth.Width(btn.Label) + 4
+		m.hits.Add("button", i, Bounds{X: buttonX, Y: buttonRow, Width: buttonWidth, Height: 1})
+		buttonX += buttonWidth + 2
+
 		if i == m.selected {
 			// Highlighted button
 			b.WriteString("\033[7m[ ")
@@ -357,8 +385,8 @@ func (m *Modal) View() string {
 	b.WriteString(strings.Repeat(" ", startX))
 	b.WriteString("╰")
 	hints := "─ Tab: navigate · Enter: confirm · Esc: cancel "
-	// Calculate remaining dash width: modalWidth - corners(2) - hints rune count
-	hintsWidth := utf8.RuneCountInString(hints)
+	// Calculate remaining dash width: modalWidth - corners(2) - hints width
+	hintsWidth := textwidth.Width(hints)
 	remainingDashes := modalWidth - 2 - hintsWidth
 	if remainingDashes > 0 {
 		b.WriteString("\033[2m")
@@ -374,6 +402,23 @@ func (m *Modal) View() string {
 	return b.String()
 }
 
+// activate hides the modal and runs the action of the button at index
+func (m *Modal) activate(index int) tea.Cmd {
+	if index < 0 || index >= len(m.buttons) {
+		return nil
+	}
+	btn := m.buttons[index]
+	value := ""
+	if m.hasInput {
+		value = m.textInput.Value()
+	}
+	m.Hide()
+	if btn.Action != nil {
+		return btn.Action(value)
+	}
+	return nil
+}
+
 // Focus is called when this component receives focus
 func (m *Modal) Focus() {
 	m.focused = true
@@ -395,6 +440,20 @@ func (m *Modal) Focused() bool {
 	return m.focused
 }
 
+// KeyMap returns the modal's bindings, which can be rebound
+func (m *Modal) KeyMap() *KeyMap {
+	return m.keys
+}
+
+// ConsumesKey reports whether the modal handles msg: nothing while hidden,
+// otherwise its own bindings and any text typed into its input
+func (m *Modal) ConsumesKey(msg tea.KeyMsg) bool {
+	if !m.visible {
+		return false
+	}
+	return m.keys.bound(msg) || (m.hasInput && typesText(msg))
+}
+
 // Show displays the modal
 func (m *Modal) Show() {
 	m.visible = true
@@ -492,7 +551,7 @@ func (m *Modal) ShowInput(title, message, placeholder string, onOK func(string)
 	m.Show()
 }
 
-// wrapText wraps text to fit within a given width (measured in runes/characters)
+// wrapText wraps text to fit within a given width in columns
 func wrapText(text string, width int) []string {
 	if width <= 0 {
 		return []string{text}
@@ -507,8 +566,8 @@ func wrapText(text string, width int) []string {
 	var currentLine strings.Builder
 
 	for _, word := range words {
-		wordWidth := utf8.RuneCountInString(word)
-		currentWidth := utf8.RuneCountInString(currentLine.String())
+		wordWidth := textwidth.Width(word)
+		currentWidth := textwidth.Width(currentLine.String())
 
 		if currentWidth == 0 {
 			currentLine.WriteString(word)
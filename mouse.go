@@ -0,0 +1,97 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bounds describes a rectangle on screen, measured in terminal cells.
+// X and Y are the column and row of the top-left corner.
+type Bounds struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Contains reports whether the cell at (x, y) lies inside the rectangle.
+func (b Bounds) Contains(x, y int) bool {
+	return x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// Translate returns a copy of msg with its coordinates made relative to the
+// top-left corner of the rectangle. Containers use it to hand mouse events
+// to children in the child's own coordinate space.
+func (b Bounds) Translate(msg tea.MouseMsg) tea.MouseMsg {
+	msg.X -= b.X
+	msg.Y -= b.Y
+	return msg
+}
+
+// HitZone is a clickable region recorded while a component renders.
+type HitZone struct {
+	ID     string // Kind of target, e.g. "node", "button", "header"
+	Index  int    // Item index within that kind (0 when not applicable)
+	Bounds Bounds // Region in component-local coordinates
+}
+
+// HitMap records where a component last drew its interactive parts so that
+// mouse events can be mapped back to them. Components reset it at the start
+// of View, add a zone for every clickable element they draw, and call Test
+// when a tea.MouseMsg arrives.
+//
+// Zones added later take precedence, so overlays should be recorded after
+// the content they cover. The zero value is ready to use.
+type HitMap struct {
+	zones []HitZone
+}
+
+// Reset clears all recorded zones
+func (h *HitMap) Reset() {
+	h.zones = h.zones[:0]
+}
+
+// Add records a clickable zone
+func (h *HitMap) Add(id string, index int, bounds Bounds) {
+	if bounds.Width <= 0 || bounds.Height <= 0 {
+		return
+	}
+	h.zones = append(h.zones, HitZone{ID: id, Index: index, Bounds: bounds})
+}
+
+// Test returns the topmost zone containing (x, y)
+func (h *HitMap) Test(x, y int) (HitZone, bool) {
+	for i := len(h.zones) - 1; i >= 0; i-- {
+		if h.zones[i].Bounds.Contains(x, y) {
+			return h.zones[i], true
+		}
+	}
+	return HitZone{}, false
+}
+
+// Zones returns the recorded zones in the order they were added
+func (h *HitMap) Zones() []HitZone {
+	return h.zones
+}
+
+// isLeftClick reports whether msg is a press of the primary mouse button
+func isLeftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// viewHeight returns the number of terminal rows a rendered view occupies
+func viewHeight(view string) int {
+	if view == "" {
+		return 0
+	}
+	rows := 1
+	for i := 0; i < len(view); i++ {
+		if view[i] == '\n' {
+			rows++
+		}
+	}
+	// A trailing newline terminates the last row rather than starting a new one
+	if view[len(view)-1] == '\n' {
+		rows--
+	}
+	return rows
+}
@@ -0,0 +1,342 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func leftClick(x, y int) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+}
+
+func wheel(button tea.MouseButton) tea.MouseMsg {
+	return tea.MouseMsg{Action: tea.MouseActionPress, Button: button}
+}
+
+func TestBoundsContains(t *testing.T) {
+	b := Bounds{X: 2, Y: 3, Width: 4, Height: 2}
+
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{2, 3, true},
+		{5, 4, true},
+		{6, 4, false},
+		{5, 5, false},
+		{1, 3, false},
+	}
+
+	for _, tt := range tests {
+		if got := b.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("Contains(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestBoundsTranslate(t *testing.T) {
+	b := Bounds{X: 10, Y: 5, Width: 20, Height: 10}
+	msg := b.Translate(leftClick(12, 8))
+
+	if msg.X != 2 || msg.Y != 3 {
+		t.Errorf("Expected translated (2, 3), got (%d, %d)", msg.X, msg.Y)
+	}
+	if msg.Button != tea.MouseButtonLeft {
+		t.Error("Translate should preserve the button")
+	}
+}
+
+func TestHitMapTopmostWins(t *testing.T) {
+	var h HitMap
+	h.Add("base", 0, Bounds{X: 0, Y: 0, Width: 10, Height: 10})
+	h.Add("overlay", 1, Bounds{X: 2, Y: 2, Width: 2, Height: 2})
+
+	zone, ok := h.Test(3, 3)
+	if !ok || zone.ID != "overlay" {
+		t.Errorf("Expected overlay zone, got %+v (ok=%v)", zone, ok)
+	}
+
+	zone, ok = h.Test(8, 8)
+	if !ok || zone.ID != "base" {
+		t.Errorf("Expected base zone, got %+v (ok=%v)", zone, ok)
+	}
+
+	if _, ok := h.Test(20, 20); ok {
+		t.Error("Expected no zone outside all bounds")
+	}
+
+	h.Reset()
+	if len(h.Zones()) != 0 {
+		t.Errorf("Expected no zones after Reset, got %d", len(h.Zones()))
+	}
+}
+
+func TestHitMapIgnoresEmptyBounds(t *testing.T) {
+	var h HitMap
+	h.Add("empty", 0, Bounds{X: 0, Y: 0, Width: 0, Height: 1})
+
+	if len(h.Zones()) != 0 {
+		t.Error("Zero-width zones should not be recorded")
+	}
+}
+
+func TestViewHeight(t *testing.T) {
+	tests := []struct {
+		view string
+		want int
+	}{
+		{"", 0},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"one\ntwo\n", 2},
+	}
+
+	for _, tt := range tests {
+		if got := viewHeight(tt.view); got != tt.want {
+			t.Errorf("viewHeight(%q) = %d, want %d", tt.view, got, tt.want)
+		}
+	}
+}
+
+func TestFileExplorerMouseClickSelects(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fe := NewFileExplorer(dir)
+	fe.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	fe.View()
+
+	// Row 0 is the header, row 1 the root, row 3 the second file
+	fe.Update(leftClick(5, 3))
+
+	if fe.GetSelectedPath() != filepath.Join(dir, "b.txt") {
+		t.Errorf("Expected b.txt selected, got %q", fe.GetSelectedPath())
+	}
+}
+
+func TestFileExplorerMouseClickTogglesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "inner.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fe := NewFileExplorer(dir)
+	fe.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	fe.View()
+
+	// First click selects the directory, second click expands it
+	fe.Update(leftClick(5, 2))
+	fe.View()
+	fe.Update(leftClick(5, 2))
+
+	if !fe.GetSelectedNode().Expanded {
+		t.Error("Clicking the selected directory should expand it")
+	}
+	if len(fe.visibleNodes) != 3 {
+		t.Errorf("Expected 3 visible nodes after expanding, got %d", len(fe.visibleNodes))
+	}
+}
+
+func TestFileExplorerMouseWheel(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fe := NewFileExplorer(dir)
+	fe.Update(wheel(tea.MouseButtonWheelDown))
+	if fe.selectedIndex != 1 {
+		t.Errorf("Expected wheel down to move selection to 1, got %d", fe.selectedIndex)
+	}
+
+	fe.Update(wheel(tea.MouseButtonWheelUp))
+	if fe.selectedIndex != 0 {
+		t.Errorf("Expected wheel up to move selection to 0, got %d", fe.selectedIndex)
+	}
+}
+
+func TestCommandPaletteMouseClickExecutes(t *testing.T) {
+	executed := ""
+	commands := []Command{
+		{Name: "First", Action: func() tea.Cmd { executed = "First"; return nil }},
+		{Name: "Second", Action: func() tea.Cmd { executed = "Second"; return nil }},
+	}
+
+	cp := NewCommandPalette(commands)
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Focus()
+	cp.Show()
+	cp.View()
+
+	zones := cp.hits.Zones()
+	if len(zones) != 2 {
+		t.Fatalf("Expected 2 command zones, got %d", len(zones))
+	}
+
+	second := zones[1].Bounds
+	cp.Update(leftClick(second.X+1, second.Y))
+
+	if executed != "Second" {
+		t.Errorf("Expected Second to execute, got %q", executed)
+	}
+	if cp.IsVisible() {
+		t.Error("Palette should hide after a command is clicked")
+	}
+}
+
+func TestCommandPaletteMouseIgnoredWhenHidden(t *testing.T) {
+	executed := false
+	cp := NewCommandPalette([]Command{
+		{Name: "Only", Action: func() tea.Cmd { executed = true; return nil }},
+	})
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Show()
+	cp.View()
+	cp.Hide()
+
+	zone := cp.hits.Zones()[0].Bounds
+	cp.Update(leftClick(zone.X, zone.Y))
+
+	if executed {
+		t.Error("Hidden palette should not react to clicks")
+	}
+}
+
+func TestDashboardMouseClickFocusesCard(t *testing.T) {
+	cards := []*StatCard{
+		NewStatCard(WithTitle("One")),
+		NewStatCard(WithTitle("Two")),
+		NewStatCard(WithTitle("Three")),
+	}
+	d := NewDashboard(WithCards(cards...), WithGridColumns(3))
+	d.Focus()
+	d.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	d.View()
+
+	third := d.hits.Zones()[2].Bounds
+	d.Update(leftClick(third.X+1, third.Y+1))
+
+	if d.focusedCardIndex != 2 {
+		t.Errorf("Expected card 2 focused, got %d", d.focusedCardIndex)
+	}
+	if cards[0].Focused() || !cards[2].Focused() {
+		t.Error("Click should move card focus from the first to the third card")
+	}
+}
+
+func TestConfirmationBlockMouseClickConfirms(t *testing.T) {
+	cb := NewConfirmationBlock(WithConfirmOptions([]string{"Yes", "Always", "No"}))
+	cb.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cb.View()
+
+	option := cb.hits.Zones()[1].Bounds
+	cb.Update(leftClick(3, option.Y))
+
+	if !cb.IsConfirmed() || cb.GetSelection() != 1 {
+		t.Errorf("Expected option 1 confirmed, got confirmed=%v selection=%d", cb.IsConfirmed(), cb.GetSelection())
+	}
+}
+
+func TestModalMouseClickRunsButtonAction(t *testing.T) {
+	clicked := ""
+	m := NewModal(WithModalButtons([]ModalButton{
+		{Label: "Save", Action: func(string) tea.Cmd { clicked = "Save"; return nil }},
+		{Label: "Discard", Action: func(string) tea.Cmd { clicked = "Discard"; return nil }},
+	}))
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.Focus()
+	m.Show()
+	m.View()
+
+	zones := m.hits.Zones()
+	if len(zones) != 2 {
+		t.Fatalf("Expected 2 button zones, got %d", len(zones))
+	}
+
+	discard := zones[1].Bounds
+	m.Update(leftClick(discard.X+2, discard.Y))
+
+	if clicked != "Discard" {
+		t.Errorf("Expected Discard action, got %q", clicked)
+	}
+	if m.IsVisible() {
+		t.Error("Modal should hide after a button is clicked")
+	}
+}
+
+func TestModalMouseClickOutsideButtons(t *testing.T) {
+	m := NewModal()
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.Show()
+	m.View()
+
+	m.Update(leftClick(0, 0))
+
+	if !m.IsVisible() {
+		t.Error("Clicking outside the buttons should not close the modal")
+	}
+}
+
+func TestToolBlockMouseClickHeaderExpands(t *testing.T) {
+	tb := NewToolBlock("Bash", "ls", []string{"1", "2", "3", "4", "5", "6", "7"})
+	tb.Update(tea.WindowSizeMsg{Width: 80})
+	tb.View()
+
+	tb.Update(leftClick(4, 0))
+	if !tb.expanded {
+		t.Error("Clicking the header should expand the tool block")
+	}
+
+	tb.View()
+	tb.Update(leftClick(4, 2))
+	if !tb.expanded {
+		t.Error("Clicking output lines should not toggle the tool block")
+	}
+}
+
+func TestCodeBlockMouseClickHeaderToggles(t *testing.T) {
+	cb := NewCodeBlock(WithCode("a\nb"), WithCodeFilename("main.go"))
+	cb.View()
+
+	cb.Update(leftClick(2, 0))
+	if !cb.IsExpanded() {
+		t.Error("Clicking the header should expand the code block")
+	}
+}
+
+func TestApplicationRoutesMouseToComponent(t *testing.T) {
+	app := NewApplication()
+	first := NewToolBlock("Bash", "one", []string{"out"})
+	second := NewToolBlock("Bash", "two", []string{"out"})
+	app.AddComponent(first)
+	app.AddComponent(second)
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	app.View()
+
+	// Each block renders a header plus one output row
+	app.Update(leftClick(3, 2))
+
+	if !second.Focused() || first.Focused() {
+		t.Error("Clicking a component should focus it")
+	}
+	if !second.expanded {
+		t.Error("Click should be delivered to the second block's header")
+	}
+	if first.expanded {
+		t.Error("First block should not receive the click")
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PaletteProvider supplies CommandPalette items for queries that start with
+// its prefix, like VS Code's "#" for symbols or ":" for go-to-line. Items is
+// only called while the prefix is typed, so providers can list files or
+// symbols on demand instead of up front. Commands are listed when no prefix
+// matches, or after ">"; a provider with an empty prefix replaces them as the
+// default, leaving ">" for commands.
+//
+// Providers backed by something slow, like a large symbol index, set Search
+// instead of Items. Search runs in the background once typing pauses and
+// passes results to send as they are found; the palette shows a spinner
+// until it returns. When the query changes ctx is cancelled and the results
+// of the old search are dropped, so Search should return promptly once ctx
+// is done.
+type PaletteProvider struct {
+	Prefix string                       // Typed first to switch to this provider, e.g. "#"
+	Name   string                       // Shown in the palette title while active
+	Items  func(query string) []Command // Items for the query typed after the prefix
+	Search func(ctx context.Context, query string, send func(items []Command)) error
+	Exact  bool // Show items as returned instead of fuzzy-filtering them
+}
+
+// commandsPrefix explicitly selects the palette's commands
+const commandsPrefix = ">"
+
+// AddProvider registers a provider, replacing any with the same prefix
+func (cp *CommandPalette) AddProvider(provider PaletteProvider) {
+	for i, p := range cp.providers {
+		if p.Prefix == provider.Prefix {
+			cp.providers[i] = provider
+			return
+		}
+	}
+	cp.providers = append(cp.providers, provider)
+}
+
+// activeProvider returns the provider selected by the query's prefix and the
+// query without it. It returns nil when the query searches commands.
+func (cp *CommandPalette) activeProvider() (*PaletteProvider, string) {
+	query := cp.textInput.Value()
+
+	var active, fallback *PaletteProvider
+	for i, p := range cp.providers {
+		switch {
+		case p.Items == nil && p.Search == nil:
+		case p.Prefix == "":
+			fallback = &cp.providers[i]
+		case strings.HasPrefix(query, p.Prefix) && (active == nil || len(p.Prefix) > len(active.Prefix)):
+			active = &cp.providers[i]
+		}
+	}
+
+	switch {
+	case active != nil:
+		return active, query[len(active.Prefix):]
+	case fallback != nil && !strings.HasPrefix(query, commandsPrefix):
+		return fallback, query
+	}
+	return nil, strings.TrimPrefix(query, commandsPrefix)
+}
+
+// maxProviderFiles bounds how many files NewFileProvider lists, so opening
+// the palette in a huge tree stays responsive
+const maxProviderFiles = 5000
+
+// NewFileProvider returns a "#" provider listing the files under the
+// explorer's root, loading directories as needed. Choosing a file reveals it
+// in the explorer and calls open, which may be nil.
+func NewFileProvider(fe *FileExplorer, open func(path string) tea.Cmd) PaletteProvider {
+	return PaletteProvider{
+		Prefix: "#",
+		Name:   "Files",
+		Items: func(string) []Command {
+			var items []Command
+			for _, node := range fe.Files(maxProviderFiles) {
+				path := node.Path
+				rel, err := filepath.Rel(fe.basePath, path)
+				if err != nil {
+					rel = path
+				}
+				items = append(items, Command{
+					Name: rel,
+					Action: func() tea.Cmd {
+						fe.Reveal(path)
+						if open != nil {
+							return open(path)
+						}
+						return nil
+					},
+				})
+			}
+			return items
+		},
+	}
+}
+
+// NewKeyBindingProvider returns a "?" provider listing the enabled bindings
+// of registry: the global ones, then each component's
+func NewKeyBindingProvider(registry *KeyRegistry) PaletteProvider {
+	return PaletteProvider{
+		Prefix: "?",
+		Name:   "Keyboard Shortcuts",
+		Items: func(string) []Command {
+			var items []Command
+			maps := append([]*KeyMap{registry.Global()}, registry.KeyMaps()...)
+			for _, km := range maps {
+				for _, b := range km.ShortHelp() {
+					items = append(items, Command{
+						Name:       km.Name() + ": " + b.Help().Desc,
+						Category:   km.Name(),
+						Keybinding: b.Help().Key,
+					})
+				}
+			}
+			return items
+		},
+	}
+}
+
+// NewLineProvider returns a ":" provider that jumps to the typed line number
+// in a code block
+func NewLineProvider(cb *CodeBlock) PaletteProvider {
+	return PaletteProvider{
+		Prefix: ":",
+		Name:   "Go to Line",
+		Exact:  true,
+		Items: func(query string) []Command {
+			first, last := cb.startLine, cb.startLine+len(cb.lines)-1
+			hint := fmt.Sprintf("Type a line number between %d and %d", first, last)
+
+			n, err := strconv.Atoi(query)
+			if err != nil || n < first || n > last {
+				return []Command{{Name: "Go to line…", Description: hint}}
+			}
+			return []Command{{
+				Name:        fmt.Sprintf("Go to line %d", n),
+				Description: strings.TrimSpace(cb.lines[n-first]),
+				Action: func() tea.Cmd {
+					cb.GoToLine(n)
+					return nil
+				},
+			}}
+		},
+	}
+}
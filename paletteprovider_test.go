@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCommandPaletteProviderPrefix(t *testing.T) {
+	var queries []string
+	symbols := PaletteProvider{
+		Prefix: "@",
+		Name:   "Symbols",
+		Items: func(query string) []Command {
+			queries = append(queries, query)
+			return []Command{{Name: "NewServer"}, {Name: "Serve"}, {Name: "Close"}}
+		},
+	}
+
+	cp := NewCommandPalette([]Command{{Name: "Save File"}}, WithPaletteProvider(symbols))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if len(queries) != 0 {
+		t.Error("Providers should not be asked for items until their prefix is typed")
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@serv")})
+	if len(cp.filtered) != 2 || cp.filtered[0].Name != "Serve" {
+		t.Errorf("Expected provider items fuzzy filtered by the query, got %v", cp.filtered)
+	}
+	if queries[len(queries)-1] != "serv" {
+		t.Errorf("Provider should get the query without its prefix, got %q", queries[len(queries)-1])
+	}
+
+	view := textwidth.Strip(cp.View())
+	if !strings.Contains(view, "Command Palette › Symbols") || !strings.Contains(view, "2 results") {
+		t.Errorf("View should name the active provider:\n%s", view)
+	}
+}
+
+func TestCommandPaletteCommandsPrefix(t *testing.T) {
+	cp := NewCommandPalette([]Command{{Name: "Save File"}, {Name: "Open File"}})
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">save")})
+
+	if len(cp.filtered) != 1 || cp.filtered[0].Name != "Save File" {
+		t.Errorf("\">\" should search commands, got %v", cp.filtered)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	root := fileTree(t)
+	fe := NewFileExplorer(root)
+	var opened string
+	open := func(path string) tea.Cmd { opened = path; return nil }
+
+	cp := NewCommandPalette(nil, WithPaletteProvider(NewFileProvider(fe, open)))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#main")})
+
+	want := filepath.Join("cmd", "app", "main.go")
+	if len(cp.filtered) != 1 || cp.filtered[0].Name != want {
+		t.Fatalf("Expected %s, got %v", want, cp.filtered)
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if opened != filepath.Join(root, want) {
+		t.Errorf("Choosing a file should open it, got %q", opened)
+	}
+	if fe.GetSelectedPath() != opened {
+		t.Error("Choosing a file should reveal it in the explorer")
+	}
+	if cp.History().Usage(want).Count != 0 {
+		t.Error("Provider items should not be recorded as commands")
+	}
+}
+
+func TestLineProvider(t *testing.T) {
+	cb := NewCodeBlock(WithCode("package main\n\nfunc main() {\n}"))
+	cp := NewCommandPalette(nil, WithPaletteProvider(NewLineProvider(cb)))
+	cp.Focus()
+	cp.Show()
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if len(cp.filtered) != 1 || !strings.Contains(cp.filtered[0].Description, "between 1 and 4") {
+		t.Errorf("Expected a hint for the line range, got %v", cp.filtered)
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if cp.filtered[0].Name != "Go to line 3" || cp.filtered[0].Description != "func main() {" {
+		t.Errorf("Expected a jump to line 3, got %+v", cp.filtered[0])
+	}
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cb.CurrentLine() != 3 {
+		t.Errorf("Choosing the line should move the code block to it, got %d", cb.CurrentLine())
+	}
+}
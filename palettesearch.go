@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteSearch is the state of a CommandPalette's background search
+type paletteSearch struct {
+	id       int             // Bumped for every query; results from older searches are dropped
+	active   bool            // A search was requested for provider and query
+	due      bool            // Waiting for the debounce timer to be scheduled
+	provider PaletteProvider // Provider being searched
+	query    string
+	cancel   context.CancelFunc
+	items    []Command // Results received so far
+	fresh    bool      // No results for the current query have arrived yet
+	loading  bool
+	err      error
+	spinning bool // A spinner tick is scheduled
+	frame    int
+	lastStep time.Time // When the spinner last stepped on the frame clock
+}
+
+// paletteSearchMsg fires when the debounce delay for a query has passed
+type paletteSearchMsg struct {
+	palette *CommandPalette
+	id      int
+}
+
+// paletteResultsMsg carries results streamed from a provider's Search
+type paletteResultsMsg struct {
+	palette *CommandPalette
+	id      int
+	items   []Command
+	done    bool
+	err     error
+	results <-chan paletteResultsMsg
+	ctx     context.Context
+}
+
+// paletteTickMsg advances the loading spinner
+type paletteTickMsg struct {
+	palette *CommandPalette
+}
+
+// Search messages are delivered to their palette even when it isn't focused
+func (m paletteSearchMsg) Target() Component  { return m.palette }
+func (m paletteResultsMsg) Target() Component { return m.palette }
+func (m paletteTickMsg) Target() Component    { return m.palette }
+
+// WithPaletteDebounce sets how long typing must pause before an async
+// provider is searched. The default is 150ms.
+func WithPaletteDebounce(d time.Duration) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.debounce = d
+	}
+}
+
+// WithPaletteSpinner sets the spinner shown while async results load
+func WithPaletteSpinner(s Spinner) CommandPaletteOption {
+	return func(cp *CommandPalette) {
+		cp.spinner = s
+	}
+}
+
+// IsSearching returns whether an async provider is still looking for results
+func (cp *CommandPalette) IsSearching() bool {
+	return cp.search.loading
+}
+
+// requestSearch asks provider for the results of query once typing pauses.
+// Results already shown for the same provider stay until new ones arrive.
+func (cp *CommandPalette) requestSearch(provider PaletteProvider, query string) {
+	s := &cp.search
+	if s.active && s.provider.Prefix == provider.Prefix && s.query == query {
+		return
+	}
+
+	samePrefix := s.provider.Prefix == provider.Prefix
+	cp.cancelSearch()
+	if !samePrefix {
+		s.items = nil
+	}
+	s.active = true
+	s.due = true
+	s.provider = provider
+	s.query = query
+	s.fresh = true
+	s.loading = true
+	s.err = nil
+}
+
+// cancelSearch stops the running search and drops its pending results
+func (cp *CommandPalette) cancelSearch() {
+	s := &cp.search
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.id++
+	s.active = false
+	s.due = false
+	s.loading = false
+}
+
+// scheduleSearch starts the debounce timer for a requested search, and the
+// spinner if it is not already running
+func (cp *CommandPalette) scheduleSearch() tea.Cmd {
+	s := &cp.search
+	if !s.due {
+		return nil
+	}
+	s.due = false
+
+	id := s.id
+	cmds := []tea.Cmd{tea.Tick(cp.debounce, func(time.Time) tea.Msg {
+		return paletteSearchMsg{palette: cp, id: id}
+	})}
+	if !s.spinning {
+		// The frame clock keeps itself running, so only a timer of our own
+		// needs tracking
+		s.spinning = cp.clock == nil
+		cmds = append(cmds, cp.spinnerTick())
+	}
+	return tea.Batch(cmds...)
+}
+
+// paletteSpinnerInterval is how often the loading spinner steps
+const paletteSpinnerInterval = 80 * time.Millisecond
+
+// SetFrameClock animates the loading spinner on a shared clock instead of
+// its own timer
+func (cp *CommandPalette) SetFrameClock(clock *FrameClock) {
+	cp.clock = clock
+}
+
+// Animating reports whether the loading spinner is running
+func (cp *CommandPalette) Animating() bool {
+	return cp.search.loading
+}
+
+// spinnerTick wakes the frame clock, or without one schedules the next
+// spinner frame
+func (cp *CommandPalette) spinnerTick() tea.Cmd {
+	if cp.clock != nil {
+		return cp.clock.Wake()
+	}
+	return tea.Tick(paletteSpinnerInterval, func(time.Time) tea.Msg {
+		return paletteTickMsg{palette: cp}
+	})
+}
+
+// handleSearchMsg runs debounced searches, collects streamed results and
+// animates the spinner. Messages meant for another palette, or for a search
+// that has been replaced, are ignored.
+func (cp *CommandPalette) handleSearchMsg(msg tea.Msg) tea.Cmd {
+	s := &cp.search
+
+	switch msg := msg.(type) {
+	case paletteSearchMsg:
+		if msg.palette != cp || msg.id != s.id || !s.loading {
+			return nil
+		}
+		return cp.startSearch()
+
+	case paletteResultsMsg:
+		if msg.palette != cp || msg.id != s.id {
+			return nil
+		}
+		if s.fresh {
+			s.items = nil
+			s.fresh = false
+			cp.selected = 0
+		}
+		s.items = append(s.items, msg.items...)
+		if msg.done {
+			s.loading = false
+			if !errors.Is(msg.err, context.Canceled) {
+				s.err = msg.err
+			}
+			s.cancel()
+			s.cancel = nil
+		}
+
+		cp.filterCommands()
+		cp.selected = max(0, min(cp.selected, len(cp.filtered)-1))
+		if msg.done {
+			return nil
+		}
+		return waitForResults(msg.ctx, msg.results)
+
+	case paletteTickMsg:
+		if msg.palette != cp {
+			return nil
+		}
+		if !s.loading {
+			s.spinning = false
+			return nil
+		}
+		s.frame++
+		return cp.spinnerTick()
+
+	case FrameMsg:
+		if s.loading && frameDue(&s.lastStep, msg.Time, paletteSpinnerInterval) {
+			s.frame++
+		}
+	}
+	return nil
+}
+
+// startSearch runs the requested search in the background. Results reach the
+// palette as paletteResultsMsgs until the search returns or is cancelled.
+func (cp *CommandPalette) startSearch() tea.Cmd {
+	s := &cp.search
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	results := make(chan paletteResultsMsg)
+	msg := paletteResultsMsg{palette: cp, id: s.id, results: results, ctx: ctx}
+	search, query := s.provider.Search, s.query
+
+	go func() {
+		deliver := func(m paletteResultsMsg) {
+			select {
+			case results <- m:
+			case <-ctx.Done():
+			}
+		}
+		send := func(items []Command) {
+			m := msg
+			m.items = append([]Command(nil), items...)
+			deliver(m)
+		}
+
+		err := search(ctx, query, send)
+		m := msg
+		m.done, m.err = true, err
+		deliver(m)
+	}()
+
+	return waitForResults(ctx, results)
+}
+
+// waitForResults waits for the next batch of results, giving up when the
+// search is cancelled
+func waitForResults(ctx context.Context, results <-chan paletteResultsMsg) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-results:
+			return msg
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
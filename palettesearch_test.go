@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// symbolSearch is an async provider that streams two batches of symbols
+func symbolSearch(calls *atomic.Int32) PaletteProvider {
+	return PaletteProvider{
+		Prefix: "@",
+		Name:   "Symbols",
+		Search: func(ctx context.Context, query string, send func([]Command)) error {
+			calls.Add(1)
+			send([]Command{{Name: "NewServer"}, {Name: "Close"}})
+			send([]Command{{Name: "ServeHTTP"}})
+			return nil
+		},
+	}
+}
+
+// runSearch fires the debounce timer for the current query and feeds the
+// streamed results back into the palette until the search finishes
+func runSearch(cp *CommandPalette) {
+	_, cmd := cp.Update(paletteSearchMsg{palette: cp, id: cp.search.id})
+	for cmd != nil {
+		_, cmd = cp.Update(cmd())
+	}
+}
+
+func TestCommandPaletteAsyncProvider(t *testing.T) {
+	var calls atomic.Int32
+	cp := NewCommandPalette(nil, WithPaletteProvider(symbolSearch(&calls)))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	_, cmd := cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@serv")})
+	if cmd == nil || !cp.IsSearching() {
+		t.Fatal("Typing into an async provider should schedule a search")
+	}
+	if calls.Load() != 0 {
+		t.Error("Search should wait for the debounce delay")
+	}
+	view := textwidth.Strip(cp.View())
+	if !strings.Contains(view, "Searching…") || !strings.Contains(view, "⠋") {
+		t.Errorf("View should show a spinner while searching:\n%s", view)
+	}
+
+	runSearch(cp)
+	if cp.IsSearching() {
+		t.Error("Search should finish once the provider returns")
+	}
+	if len(cp.filtered) != 2 || cp.filtered[0].Name != "ServeHTTP" {
+		t.Errorf("Streamed results should be combined and fuzzy ranked, got %v", cp.filtered)
+	}
+}
+
+func TestCommandPaletteDebounceDropsStaleQueries(t *testing.T) {
+	var calls atomic.Int32
+	cp := NewCommandPalette(nil, WithPaletteProvider(symbolSearch(&calls)))
+	cp.Focus()
+	cp.Show()
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@s")})
+	stale := cp.search.id
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+
+	if _, cmd := cp.Update(paletteSearchMsg{palette: cp, id: stale}); cmd != nil {
+		t.Error("The timer of a superseded query should not start a search")
+	}
+	runSearch(cp)
+	if calls.Load() != 1 {
+		t.Errorf("Only the latest query should be searched, got %d searches", calls.Load())
+	}
+}
+
+func TestCommandPaletteCancelsStaleSearch(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	slow := PaletteProvider{
+		Prefix: "@",
+		Search: func(ctx context.Context, query string, send func([]Command)) error {
+			if query == "a" {
+				close(started)
+				<-ctx.Done()
+				close(cancelled)
+				return ctx.Err()
+			}
+			send([]Command{{Name: "ab"}})
+			return nil
+		},
+	}
+
+	cp := NewCommandPalette(nil, WithPaletteProvider(slow))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@a")})
+	_, wait := cp.Update(paletteSearchMsg{palette: cp, id: cp.search.id})
+	<-started
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	<-cancelled
+	if msg := wait(); msg != nil {
+		t.Errorf("Waiting on a cancelled search should give up, got %T", msg)
+	}
+
+	runSearch(cp)
+	if len(cp.filtered) != 1 || cp.filtered[0].Name != "ab" {
+		t.Errorf("Expected results of the new query, got %v", cp.filtered)
+	}
+}
+
+func TestCommandPaletteSearchError(t *testing.T) {
+	failing := PaletteProvider{
+		Prefix: "@",
+		Search: func(context.Context, string, func([]Command)) error {
+			return errors.New("index unavailable")
+		},
+	}
+
+	cp := NewCommandPalette(nil, WithPaletteProvider(failing))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@x")})
+	runSearch(cp)
+
+	if !strings.Contains(textwidth.Strip(cp.View()), "Search failed: index unavailable") {
+		t.Errorf("View should show the search error:\n%s", textwidth.Strip(cp.View()))
+	}
+}
+
+func TestCommandPaletteSpinnerStopsWhenDone(t *testing.T) {
+	var calls atomic.Int32
+	cp := NewCommandPalette(nil, WithPaletteProvider(symbolSearch(&calls)), WithPaletteSpinner(SpinnerLine))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+
+	_, cmd := cp.Update(paletteTickMsg{palette: cp})
+	if cmd == nil || cp.search.frame != 1 {
+		t.Error("Spinner should keep ticking while loading")
+	}
+
+	runSearch(cp)
+	if _, cmd := cp.Update(paletteTickMsg{palette: cp}); cmd != nil {
+		t.Error("Spinner should stop once results have loaded")
+	}
+}
+
+func TestCommandPaletteSpinnerUsesFrameClock(t *testing.T) {
+	var calls atomic.Int32
+	app := NewApplication()
+	cp := NewCommandPalette(nil, WithPaletteProvider(symbolSearch(&calls)), WithPaletteSpinner(SpinnerLine))
+	app.AddComponent(cp)
+	cp.Focus()
+	cp.Show()
+
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+	if !cp.Animating() || !app.Clock().Running() {
+		t.Fatal("Loading should animate on the application's frame clock")
+	}
+
+	now := time.Now()
+	app.Update(FrameMsg{Time: now.Add(paletteSpinnerInterval)})
+	app.Update(FrameMsg{Time: now.Add(paletteSpinnerInterval + time.Millisecond)})
+	if cp.search.frame != 1 {
+		t.Errorf("Frames should step the spinner at its own interval, got frame %d", cp.search.frame)
+	}
+
+	runSearch(cp)
+	if _, cmd := app.Update(FrameMsg{Time: now.Add(time.Second)}); cmd != nil || cp.Animating() {
+		t.Error("The clock should pause once results have loaded")
+	}
+}
+
+func TestCommandPaletteSourceHasNoSideEffects(t *testing.T) {
+	var calls atomic.Int32
+	cp := NewCommandPalette([]Command{{Name: "Save"}}, WithPaletteProvider(symbolSearch(&calls)))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@s")})
+	search := cp.search.id
+
+	for i := 0; i < 3; i++ {
+		cp.source()
+		cp.View()
+	}
+	if cp.search.id != search || !cp.search.active {
+		t.Error("Reading the source or rendering should not restart the search")
+	}
+
+	// Changing the query away from the provider cancels it
+	cp.textInput.SetValue("")
+	cp.source()
+	if !cp.search.active {
+		t.Error("source should not cancel a search by itself")
+	}
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if cp.search.active || cp.IsSearching() {
+		t.Error("Typing a command query should cancel the search")
+	}
+}
+
+func TestCommandPaletteHideCancelsSearch(t *testing.T) {
+	var calls atomic.Int32
+	cp := NewCommandPalette(nil, WithPaletteProvider(symbolSearch(&calls)))
+	cp.Focus()
+	cp.Show()
+	cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@s")})
+	pending := cp.search.id
+	cp.Hide()
+
+	if _, cmd := cp.Update(paletteSearchMsg{palette: cp, id: pending}); cmd != nil || cp.IsSearching() {
+		t.Error("Hiding the palette should cancel its search")
+	}
+}
@@ -0,0 +1,258 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// filePatch is the parsed diff of a single file in a unified patch
+type filePatch struct {
+	oldName string // Path before the change, empty for created files
+	newName string // Path after the change, empty for deleted files
+	created bool
+	deleted bool
+	lines   []DiffLine
+}
+
+// NewDiffBlockFromUnified parses a unified diff, such as the output of
+// `git diff` or `diff -u`, and returns one DiffBlock per file. It understands
+// ---/+++ file headers, git extended headers (new, deleted and renamed files),
+// @@ hunk headers and "\ No newline at end of file" markers. Text before the
+// first header is ignored, so patches embedded in prose or Markdown fences can
+// be passed as-is. Files without hunks (binary files, pure renames) are
+// skipped.
+//
+// Options are applied to every block after the filename and operation have
+// been set from the patch.
+func NewDiffBlockFromUnified(patch string, opts ...DiffBlockOption) ([]*DiffBlock, error) {
+	files, err := parseUnified(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*DiffBlock, 0, len(files))
+	for _, f := range files {
+		fileOpts := []DiffBlockOption{
+			WithDiffFilename(f.displayName()),
+			WithDiffOperation(f.operation()),
+			WithDiffLanguage(LanguageForFilename(f.displayName())),
+		}
+		db := NewDiffBlock(append(fileOpts, opts...)...)
+		db.lines = f.lines
+		db.oldStart = max(f.lines[0].OldLineNum, 1)
+		db.newStart = max(f.lines[0].NewLineNum, 1)
+		blocks = append(blocks, db)
+	}
+	return blocks, nil
+}
+
+// displayName returns the name shown in the block header
+func (f filePatch) displayName() string {
+	switch {
+	case f.deleted || f.newName == "":
+		return f.oldName
+	case f.created || f.oldName == "" || f.oldName == f.newName:
+		return f.newName
+	default:
+		return f.oldName + " → " + f.newName
+	}
+}
+
+// operation returns the operation shown in the block header
+func (f filePatch) operation() string {
+	switch {
+	case f.created || f.oldName == "":
+		return "Create"
+	case f.deleted || f.newName == "":
+		return "Delete"
+	case f.oldName != f.newName:
+		return "Rename"
+	default:
+		return "Edit"
+	}
+}
+
+// parseUnified splits a unified patch into files and parses their hunks
+func parseUnified(patch string) ([]filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+
+	var files []filePatch
+	var cur *filePatch
+	flush := func() {
+		if cur != nil && len(cur.lines) > 0 {
+			files = append(files, *cur)
+		}
+		cur = nil
+	}
+
+	inHunk := false
+	var oldNum, newNum, oldLeft, newLeft int
+
+	for n := 0; n < len(lines); n++ {
+		line := lines[n]
+		fileHeader := strings.HasPrefix(line, "--- ") && n+1 < len(lines) && strings.HasPrefix(lines[n+1], "+++ ")
+
+		if inHunk {
+			// Counts from the hunk header decide where the hunk ends. Once they
+			// are used up, further +/- lines are still accepted so hand-written
+			// patches with wrong counts keep their content.
+			remaining := oldLeft > 0 || newLeft > 0
+			switch {
+			case strings.HasPrefix(line, `\`):
+				if len(cur.lines) > 0 {
+					cur.lines[len(cur.lines)-1].NoNewline = true
+				}
+				continue
+			case line == "" && remaining:
+				line = " " // Some tools strip the space from empty context lines
+			case fileHeader && !remaining:
+				line = "" // Next file
+			}
+
+			if line != "" {
+				switch line[0] {
+				case ' ':
+					cur.lines = append(cur.lines, DiffLine{
+						Type:       DiffUnchanged,
+						Content:    line[1:],
+						LineNum:    newNum,
+						OldLineNum: oldNum,
+						NewLineNum: newNum,
+					})
+					oldNum++
+					newNum++
+					oldLeft--
+					newLeft--
+					continue
+				case '-':
+					cur.lines = append(cur.lines, DiffLine{
+						Type:       DiffRemoved,
+						Content:    line[1:],
+						LineNum:    oldNum,
+						OldLineNum: oldNum,
+						anchored:   true,
+						newBefore:  newNum - 1,
+					})
+					oldNum++
+					oldLeft--
+					continue
+				case '+':
+					cur.lines = append(cur.lines, DiffLine{
+						Type:       DiffAdded,
+						Content:    line[1:],
+						LineNum:    newNum,
+						NewLineNum: newNum,
+						anchored:   true,
+						oldBefore:  oldNum - 1,
+					})
+					newNum++
+					newLeft--
+					continue
+				}
+			}
+			inHunk = false
+			line = lines[n]
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			cur = &filePatch{}
+			if a, b, ok := strings.Cut(line[len("diff --git "):], " b/"); ok {
+				cur.oldName = strings.TrimPrefix(a, "a/")
+				cur.newName = b
+			}
+
+		case fileHeader:
+			if cur == nil || len(cur.lines) > 0 {
+				flush()
+				cur = &filePatch{}
+			}
+			cur.oldName = headerPath(line[len("--- "):], "a/")
+			cur.newName = headerPath(lines[n+1][len("+++ "):], "b/")
+			n++
+
+		case strings.HasPrefix(line, "@@"):
+			oldStart, oldCount, newStart, newCount, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			if cur == nil {
+				cur = &filePatch{}
+			}
+			// A side without lines gives the line before the hunk
+			oldNum, newNum = max(oldStart, 1), max(newStart, 1)
+			if oldCount == 0 {
+				oldNum = oldStart + 1
+			}
+			if newCount == 0 {
+				newNum = newStart + 1
+			}
+			oldLeft, newLeft = oldCount, newCount
+			inHunk = true
+
+		case cur == nil:
+			// Preamble before the first file
+
+		case strings.HasPrefix(line, "new file mode"):
+			cur.created = true
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.deleted = true
+		case strings.HasPrefix(line, "rename from "):
+			cur.oldName = line[len("rename from "):]
+		case strings.HasPrefix(line, "rename to "):
+			cur.newName = line[len("rename to "):]
+		}
+	}
+	flush()
+
+	return files, nil
+}
+
+// headerPath extracts the path from a ---/+++ header, dropping timestamps,
+// the a/ or b/ prefix, and returning "" for /dev/null
+func headerPath(s, prefix string) string {
+	if tab := strings.IndexByte(s, '\t'); tab >= 0 {
+		s = s[:tab]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// parseHunkHeader parses "@@ -oldStart[,oldCount] +newStart[,newCount] @@"
+func parseHunkHeader(line string) (oldStart, oldCount, newStart, newCount int, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" ||
+		!strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, 0, fmt.Errorf("malformed hunk header %q", line)
+	}
+
+	oldStart, oldCount, err = parseHunkRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	newStart, newCount, err = parseHunkRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	return oldStart, oldCount, newStart, newCount, nil
+}
+
+// parseHunkRange parses "start[,count]"; the count defaults to 1
+func parseHunkRange(s string) (start, count int, err error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return 0, 0, err
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+)
+
+const samplePatch = `Some explanation from the model.
+
+diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,4 @@
+ package main
+
+-import "fmt"
++import "log"
+
+@@ -10,3 +10,4 @@ func main() {
+ 	x := 1
+-	fmt.Println(x)
++	log.Println(x)
++	log.Println("done")
+ }
+\ No newline at end of file
+diff --git a/README.md b/README.md
+new file mode 100644
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1,2 @@
++# Title
++Body
+`
+
+func TestNewDiffBlockFromUnifiedMultiFile(t *testing.T) {
+	blocks, err := NewDiffBlockFromUnified(samplePatch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks, got %d", len(blocks))
+	}
+
+	if blocks[0].filename != "main.go" || blocks[0].operation != "Edit" {
+		t.Errorf("First block = %s(%s), want Edit(main.go)", blocks[0].operation, blocks[0].filename)
+	}
+	if blocks[1].filename != "README.md" || blocks[1].operation != "Create" {
+		t.Errorf("Second block = %s(%s), want Create(README.md)", blocks[1].operation, blocks[1].filename)
+	}
+
+	added, removed := blocks[0].countChanges()
+	if added != 3 || removed != 2 {
+		t.Errorf("Expected 3 added and 2 removed lines, got %d and %d", added, removed)
+	}
+}
+
+func TestNewDiffBlockFromUnifiedLineNumbers(t *testing.T) {
+	blocks, err := NewDiffBlockFromUnified(samplePatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := blocks[0].lines
+
+	// The empty context line lost its leading space but is still context
+	if lines[1].Type != DiffUnchanged || lines[1].Content != "" {
+		t.Errorf("Expected empty context line, got %+v", lines[1])
+	}
+
+	removed := lines[2]
+	if removed.Type != DiffRemoved || removed.OldLineNum != 3 {
+		t.Errorf("Expected removed line at old line 3, got %+v", removed)
+	}
+
+	// Second hunk starts at line 10 on both sides
+	second := lines[5]
+	if second.Content != "\tx := 1" || second.OldLineNum != 10 || second.NewLineNum != 10 {
+		t.Errorf("Expected second hunk to start at line 10, got %+v", second)
+	}
+
+	last := lines[len(lines)-1]
+	if last.Content != "}" || last.NewLineNum != 13 || !last.NoNewline {
+		t.Errorf("Expected final brace at new line 13 without newline, got %+v", last)
+	}
+}
+
+func TestNewDiffBlockFromUnifiedKeepsHunksSeparate(t *testing.T) {
+	blocks, err := NewDiffBlockFromUnified(samplePatch, WithDiffContext(10), WithDiffExpanded(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hunks := blocks[0].Hunks()
+	if len(hunks) != 2 {
+		t.Fatalf("Hunks from separate @@ sections must not merge, got %d", len(hunks))
+	}
+	if got := hunks[1].Header(); got != "@@ -10,3 +10,4 @@" {
+		t.Errorf("Second hunk header = %q", got)
+	}
+
+	view := textwidth.Strip(blocks[0].View())
+	if !strings.Contains(view, `\ No newline at end of file`) {
+		t.Error("View should show the missing newline marker")
+	}
+}
+
+func TestNewDiffBlockFromUnifiedPlainDiff(t *testing.T) {
+	patch := "--- old.txt\t2024-01-01 00:00:00\n+++ new.txt\t2024-01-02 00:00:00\n@@ -1 +1 @@\n-a\n+b\n"
+
+	blocks, err := NewDiffBlockFromUnified(patch, WithDiffOperation("Patch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("Expected 1 block, got %d", len(blocks))
+	}
+	if blocks[0].filename != "old.txt → new.txt" {
+		t.Errorf("Expected rename-style filename, got %q", blocks[0].filename)
+	}
+	if blocks[0].operation != "Patch" {
+		t.Errorf("Options should override the parsed operation, got %q", blocks[0].operation)
+	}
+}
+
+func TestNewDiffBlockFromUnifiedDeletedAndRenamed(t *testing.T) {
+	patch := `diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1,1 +0,0 @@
+-bye
+diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+--- a/old.go
++++ b/new.go
+@@ -1 +1 @@
+-package old
++package new
+diff --git a/image.png b/image.png
+Binary files a/image.png and b/image.png differ
+`
+
+	blocks, err := NewDiffBlockFromUnified(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks (binary skipped), got %d", len(blocks))
+	}
+	if blocks[0].operation != "Delete" || blocks[0].filename != "gone.txt" {
+		t.Errorf("Expected Delete(gone.txt), got %s(%s)", blocks[0].operation, blocks[0].filename)
+	}
+	if blocks[1].operation != "Rename" || blocks[1].filename != "old.go → new.go" {
+		t.Errorf("Expected Rename(old.go → new.go), got %s(%s)", blocks[1].operation, blocks[1].filename)
+	}
+}
+
+func TestNewDiffBlockFromUnifiedMalformedHeader(t *testing.T) {
+	_, err := NewDiffBlockFromUnified("--- a/x\n+++ b/x\n@@ -one +1 @@\n-a\n")
+	if err == nil {
+		t.Fatal("Expected an error for a malformed hunk header")
+	}
+	if !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Error should name the line, got %v", err)
+	}
+}
+
+func TestNewDiffBlockFromUnifiedEmpty(t *testing.T) {
+	blocks, err := NewDiffBlockFromUnified("")
+	if err != nil || len(blocks) != 0 {
+		t.Errorf("Empty patch should produce no blocks, got %d (err=%v)", len(blocks), err)
+	}
+}
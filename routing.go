@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BroadcastMsg is implemented by messages the Application delivers to every
+// component instead of only the focused one, such as animation ticks.
+// Components ignore broadcasts they have no use for.
+type BroadcastMsg interface {
+	Broadcast()
+}
+
+// TargetedMsg is implemented by messages meant for one component, which the
+// Application delivers to it whether or not it is focused. When the target is
+// not one of the application's components, for example because it is nested
+// inside another, the message is broadcast so containers can pass it on.
+type TargetedMsg interface {
+	Target() Component
+}
+
+// broadcastMsg wraps a message for delivery to every component
+type broadcastMsg struct {
+	msg tea.Msg
+}
+
+func (broadcastMsg) Broadcast() {}
+
+// targetedMsg wraps a message for delivery to one component
+type targetedMsg struct {
+	target Component
+	msg    tea.Msg
+}
+
+func (m targetedMsg) Target() Component {
+	return m.target
+}
+
+// Broadcast wraps msg so the Application delivers it to every component:
+//
+//	return tui.Broadcast(themeChangedMsg{})
+func Broadcast(msg tea.Msg) tea.Msg {
+	return broadcastMsg{msg: msg}
+}
+
+// SendTo wraps msg so the Application delivers it to c only
+func SendTo(c Component, msg tea.Msg) tea.Msg {
+	return targetedMsg{target: c, msg: msg}
+}
+
+// sameComponent reports whether a and b are the same component. Components
+// of a type that can't be compared, such as a struct holding a slice passed
+// by value, never match instead of panicking.
+func sameComponent(a, b Component) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// unwrapMsg returns the message inside a Broadcast or SendTo wrapper, or msg
+// itself when it isn't wrapped
+func unwrapMsg(msg tea.Msg) tea.Msg {
+	switch m := msg.(type) {
+	case broadcastMsg:
+		return m.msg
+	case targetedMsg:
+		return m.msg
+	}
+	return msg
+}
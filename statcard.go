@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/SCKelemen/cli/renderer"
 	"github.com/SCKelemen/color"
@@ -474,42 +475,17 @@ func (s *StatCard) renderWithLayout() string {
 	return screen.String()
 }
 
-// truncate truncates a string to fit within width (using rune count for better unicode support)
+// truncate truncates a string to fit within width, padding it if shorter
 func (s *StatCard) truncate(str string, width int) string {
-	runes := []rune(str)
-	runeLen := len(runes)
-
-	if runeLen <= width {
-		return str + strings.Repeat(" ", width-runeLen)
-	}
 	if width > 3 {
-		return string(runes[:width-3]) + "..."
-	}
-	if width > 0 {
-		return string(runes[:width])
+		return textwidth.PadRight(textwidth.TruncateWith(str, width, "..."), width)
 	}
-	return ""
+	return textwidth.PadRight(textwidth.Truncate(str, max(width, 0)), width)
 }
 
-// visibleLength calculates the visible length of a string (excluding ANSI codes, counting runes)
+// visibleLength calculates the columns a string occupies (excluding ANSI codes)
 func (s *StatCard) visibleLength(str string) int {
-	// Count runes while skipping ANSI escape sequences
-	inEscape := false
-	count := 0
-	for _, ch := range str {
-		if ch == '\033' {
-			inEscape = true
-			continue
-		}
-		if inEscape {
-			if ch == 'm' {
-				inEscape = false
-			}
-			continue
-		}
-		count++
-	}
-	return count
+	return textwidth.Width(str)
 }
 
 // abs returns the absolute value of an integer
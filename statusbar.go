@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -16,6 +17,9 @@ import (
 //   - Focused: Inverted colors (black on white)
 //   - Unfocused: Dimmed text
 //
+// Given a KeyRegistry, the hints list the bindings in effect for the focused
+// component; an Application hands its registry to the status bars it holds.
+//
 // Example usage:
 //
 //	statusBar := tui.NewStatusBar()
@@ -27,6 +31,7 @@ type StatusBar struct {
 	focused   bool
 	textColor string
 	hintColor string
+	keys      *KeyRegistry // Lists the active bindings instead of the default hints
 }
 
 // StatusBarOption configures a StatusBar.
@@ -46,6 +51,13 @@ func WithStatusBarTheme(theme string) StatusBarOption {
 	}
 }
 
+// WithStatusBarKeys lists the active bindings of registry as the hints
+func WithStatusBarKeys(registry *KeyRegistry) StatusBarOption {
+	return func(s *StatusBar) {
+		s.keys = registry
+	}
+}
+
 // NewStatusBar creates a new status bar with the default message "Ready".
 func NewStatusBar(opts ...StatusBarOption) *StatusBar {
 	s := &StatusBar{
@@ -87,20 +99,14 @@ func (s *StatusBar) View() string {
 	left := s.message
 
 	// Keybindings on the right
-	right := s.hintColor + "Tab: Focus • q: Quit\033[0m"
+	right := s.hintColor + s.hints() + "\033[0m"
 
 	// Calculate spacing
-	spacing := s.width - len(left) - len(stripANSI(right))
+	spacing := s.width - textwidth.Width(left) - textwidth.Width(right)
 	if spacing < 0 {
 		spacing = 0
 		// Truncate left message if needed
-		maxLeft := s.width - len(stripANSI(right)) - 3
-		if maxLeft < 0 {
-			maxLeft = 0
-		}
-		if len(left) > maxLeft {
-			left = left[:maxLeft] + "..."
-		}
+		left = textwidth.TruncateWith(left, max(s.width-textwidth.Width(right), 0), "...")
 	}
 
 	// Build status bar
@@ -113,6 +119,35 @@ func (s *StatusBar) View() string {
 	return fmt.Sprintf("%s%s\033[0m\n", s.textColor, line)
 }
 
+// SetKeyRegistry lists the active bindings of registry as the hints
+func (s *StatusBar) SetKeyRegistry(registry *KeyRegistry) {
+	s.keys = registry
+}
+
+// hints returns the keybinding hints. Active bindings that would crowd the
+// message past half the bar are left out.
+func (s *StatusBar) hints() string {
+	if s.keys == nil {
+		return "Tab: Focus • q: Quit"
+	}
+
+	var hints []string
+	width := 0
+	for _, b := range s.keys.Active() {
+		hint := b.Help().Key + ": " + b.Help().Desc
+		w := textwidth.Width(hint)
+		if len(hints) > 0 {
+			w += textwidth.Width(" • ")
+		}
+		if width+w > s.width/2 {
+			break
+		}
+		hints = append(hints, hint)
+		width += w
+	}
+	return strings.Join(hints, " • ")
+}
+
 // Focus is called when this component receives focus
 func (s *StatusBar) Focus() {
 	s.focused = true
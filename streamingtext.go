@@ -0,0 +1,389 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// StreamingText displays text that arrives in arbitrary chunks, such as LLM
+// output streamed token by token. Chunks may split lines, words, UTF-8
+// sequences or ANSI escapes anywhere; incomplete sequences are held until
+// the rest arrives. Text is word-wrapped as it comes in, measuring widths
+// with the text package, and only the word being written is wrapped again
+// when more text arrives; a word wider than a line is placed as it arrives.
+// A cursor marks the end of the text until Finish is called.
+//
+// Example usage:
+//
+//	st := tui.NewStreamingText()
+//	for chunk := range tokens {
+//		st.Append(chunk)
+//	}
+//	st.Finish()
+type StreamingText struct {
+	width   int
+	height  int
+	focused bool
+
+	raw     []byte // Complete input, kept to rewrap on resize
+	pending []byte // Incomplete UTF-8 sequence or escape at the end of the input
+
+	out       strings.Builder // Wrapped lines that are finished
+	wrap      lineWrapper
+	streaming bool
+	cursor    string
+}
+
+// StreamingTextOption configures a StreamingText
+type StreamingTextOption func(*StreamingText)
+
+// WithStreamCursor sets the cursor shown at the end of the text while
+// streaming
+func WithStreamCursor(cursor string) StreamingTextOption {
+	return func(st *StreamingText) {
+		st.cursor = cursor
+	}
+}
+
+// NewStreamingText creates an empty streaming text
+func NewStreamingText(opts ...StreamingTextOption) *StreamingText {
+	st := &StreamingText{
+		streaming: true,
+		cursor:    "▌",
+	}
+
+	for _, opt := range opts {
+		opt(st)
+	}
+
+	st.wrap = lineWrapper{out: &st.out}
+	return st
+}
+
+// Init initializes the component
+func (st *StreamingText) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages. A change of width rewraps the text.
+func (st *StreamingText) Update(msg tea.Msg) (Component, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		st.height = msg.Height
+		if msg.Width != st.width {
+			st.width = msg.Width
+			st.rewrap()
+		}
+	}
+	return st, nil
+}
+
+// View renders the wrapped text, with the cursor while streaming
+func (st *StreamingText) View() string {
+	// Place the open line and word on a copy, so the next chunk can
+	// continue the word
+	var tail strings.Builder
+	w := st.wrap
+	w.out = &tail
+	w.flushWord()
+
+	if st.streaming && st.cursor != "" {
+		pad := 0
+		if w.lineWidth > 0 || !w.soft {
+			pad = w.spaces
+		}
+		cursorWidth := textwidth.Width(st.cursor)
+		if w.width > 0 && w.lineWidth > 0 && w.lineWidth+pad+cursorWidth > w.width {
+			w.breakLine(w.style)
+			pad = 0
+		}
+		w.line += strings.Repeat(" ", pad) + st.cursor
+		w.lineWidth += pad + cursorWidth
+	}
+	if w.lineWidth > 0 {
+		w.finishLine(w.style)
+	}
+
+	return st.out.String() + tail.String()
+}
+
+// Focus is called when this component receives focus
+func (st *StreamingText) Focus() {
+	st.focused = true
+}
+
+// Blur is called when this component loses focus
+func (st *StreamingText) Blur() {
+	st.focused = false
+}
+
+// Focused returns whether this component is currently focused
+func (st *StreamingText) Focused() bool {
+	return st.focused
+}
+
+// Append adds a chunk of streamed text
+func (st *StreamingText) Append(chunk string) {
+	data := append(st.pending, chunk...)
+	st.pending = nil
+
+	for i := 0; i < len(data); {
+		if data[i] == '\x1b' {
+			n := textwidth.EscapeLen(data[i:])
+			if n == 0 && !neverEnds(data[i:]) {
+				st.pending = append([]byte(nil), data[i:]...)
+				return
+			}
+			if n == 0 {
+				// Not an escape after all: drop the ESC and show the rest
+				i++
+				continue
+			}
+			st.raw = append(st.raw, data[i:i+n]...)
+			st.wrap.writeEscape(string(data[i : i+n]))
+			i += n
+			continue
+		}
+
+		if !utf8.FullRune(data[i:]) {
+			st.pending = append([]byte(nil), data[i:]...)
+			return
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		st.raw = append(st.raw, data[i:i+size]...)
+		st.wrap.writeRune(r)
+		i += size
+	}
+}
+
+// Write appends p, so output can be copied into the component
+func (st *StreamingText) Write(p []byte) (int, error) {
+	st.Append(string(p))
+	return len(p), nil
+}
+
+// maxPendingEscape is the longest incomplete escape held back while waiting
+// for the rest of it
+const maxPendingEscape = 4096
+
+// neverEnds reports whether an incomplete escape cannot be completed by more
+// input: it runs into a newline or grows past maxPendingEscape. Such a
+// sequence, like an OSC without its terminator, is shown as text instead.
+func neverEnds(seq []byte) bool {
+	return len(seq) > maxPendingEscape || bytes.IndexByte(seq, '\n') >= 0
+}
+
+// Finish ends the stream: the cursor is hidden and an incomplete UTF-8
+// sequence left at the end is shown as U+FFFD, while an incomplete escape
+// is dropped
+func (st *StreamingText) Finish() {
+	if len(st.pending) > 0 && st.pending[0] != '\x1b' {
+		st.raw = append(st.raw, string(utf8.RuneError)...)
+		st.wrap.writeRune(utf8.RuneError)
+	}
+	st.pending = nil
+	st.wrap.flushWord()
+	st.streaming = false
+}
+
+// Streaming reports whether more text is expected
+func (st *StreamingText) Streaming() bool {
+	return st.streaming
+}
+
+// Text returns the text received so far, without any incomplete sequence
+// at its end
+func (st *StreamingText) Text() string {
+	return string(st.raw)
+}
+
+// rewrap wraps the whole text again at the current width
+func (st *StreamingText) rewrap() {
+	st.out.Reset()
+	st.wrap = lineWrapper{width: st.width, out: &st.out}
+
+	data := st.raw
+	for i := 0; i < len(data); {
+		if data[i] == '\x1b' {
+			n := textwidth.EscapeLen(data[i:])
+			st.wrap.writeEscape(string(data[i : i+n]))
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		st.wrap.writeRune(r)
+		i += size
+	}
+}
+
+// lineWrapper word-wraps text as it is written, a rune or escape at a time.
+// Finished lines go to out; the line being filled and the word being
+// collected are kept until the next break.
+type lineWrapper struct {
+	width int // 0 disables wrapping
+	out   *strings.Builder
+
+	line      string // Line being filled
+	lineWidth int
+	soft      bool   // The line began at a wrap, so leading spaces are dropped
+	word      string // Word being collected, placed when it ends
+	wordWidth int    // Estimated width of word, counted a rune at a time
+	wordStyle string // Styles in effect where the word began
+	glued     bool   // word continues text already placed, with no break between
+	spaces    int    // Spaces between the line and the word
+	style     string // SGR sequences in effect, re-applied after each break
+}
+
+// maxWordBytes is the longest word collected while not wrapping before the
+// start of it is placed
+const maxWordBytes = 1024
+
+// writeRune adds a decoded rune
+func (w *lineWrapper) writeRune(r rune) {
+	switch {
+	case r == '\n':
+		w.flushWord()
+		w.finishLine(w.style)
+		w.line, w.lineWidth, w.soft, w.spaces = w.style, 0, false, 0
+	case r == '\t':
+		w.flushWord()
+		w.spaces += 4
+	case unicode.IsSpace(r):
+		w.flushWord()
+		w.spaces++
+	case unicode.IsControl(r):
+		// Carriage returns and other controls would move the cursor
+	default:
+		if w.word == "" {
+			w.wordStyle = w.style
+		}
+		w.word += string(r)
+		w.wordWidth += textwidth.Width(string(r))
+		if w.width > 0 && w.wordWidth > w.width || len(w.word) > maxWordBytes {
+			w.spillWord()
+		}
+	}
+}
+
+// writeEscape adds a complete escape sequence. SGR styles are kept with the
+// text; other sequences, such as cursor movement, are dropped.
+func (w *lineWrapper) writeEscape(seq string) {
+	if !strings.HasPrefix(seq, "\033[") || !strings.HasSuffix(seq, "m") {
+		return
+	}
+	if w.word == "" {
+		w.wordStyle = w.style
+	}
+	w.word += seq
+	w.style = applySGR(w.style, seq)
+}
+
+// flushWord places the collected word on the line, breaking before it if it
+// does not fit, or inside it if it is wider than a whole line
+func (w *lineWrapper) flushWord() {
+	glued := w.glued
+	w.glued = false
+	if w.word == "" {
+		return
+	}
+
+	width := textwidth.Width(w.word)
+	switch {
+	case glued:
+		// The word carries on from the line without a break
+	case w.width > 0 && w.lineWidth > 0 && w.lineWidth+w.spaces+width > w.width:
+		w.breakLine(w.wordStyle)
+	case w.lineWidth > 0 || !w.soft:
+		w.line += strings.Repeat(" ", w.spaces)
+		w.lineWidth += w.spaces
+	}
+	w.spaces = 0
+
+	if w.width > 0 && w.lineWidth+width > w.width {
+		w.placeLong(w.word)
+	} else {
+		w.line += w.word
+		w.lineWidth += width
+	}
+	w.word, w.wordWidth = "", 0
+}
+
+// spillWord places all but the last grapheme of a word that is too long to
+// wait for, so text without spaces, such as CJK, URLs or base64, is placed as
+// it arrives instead of being wrapped again on every View. The last grapheme
+// is kept since further runes may still extend it.
+func (w *lineWrapper) spillWord() {
+	start := 0
+	if i := strings.LastIndexByte(w.word, '\x1b'); i >= 0 {
+		start = i + textwidth.EscapeLen(w.word[i:])
+	}
+	tail := ""
+	if gs := textwidth.Graphemes(w.word[start:]); len(gs) > 0 {
+		tail = gs[len(gs)-1]
+	}
+	if len(tail) == len(w.word) {
+		return
+	}
+
+	w.word = w.word[:len(w.word)-len(tail)]
+	w.flushWord()
+	w.word, w.wordWidth, w.wordStyle, w.glued = tail, textwidth.Width(tail), w.style, true
+}
+
+// placeLong places a word wider than the line a grapheme at a time
+func (w *lineWrapper) placeLong(word string) {
+	style := w.wordStyle
+	for word != "" {
+		if word[0] == '\x1b' {
+			n := strings.IndexByte(word, 'm') + 1
+			w.line += word[:n]
+			style = applySGR(style, word[:n])
+			word = word[n:]
+			continue
+		}
+
+		run := word
+		if i := strings.IndexByte(word, '\x1b'); i >= 0 {
+			run = word[:i]
+		}
+		for _, g := range textwidth.Graphemes(run) {
+			gw := textwidth.Width(g)
+			if w.lineWidth > 0 && w.lineWidth+gw > w.width {
+				w.breakLine(style)
+			}
+			w.line += g
+			w.lineWidth += gw
+		}
+		word = word[len(run):]
+	}
+}
+
+// breakLine ends the line at a wrap. style is in effect at the break and
+// carries over to the next line.
+func (w *lineWrapper) breakLine(style string) {
+	w.finishLine(style)
+	w.line, w.lineWidth, w.soft = style, 0, true
+}
+
+// finishLine writes the line out, resetting style if one is in effect
+func (w *lineWrapper) finishLine(style string) {
+	line := w.line
+	if style != "" {
+		line += "\033[0m"
+	}
+	w.out.WriteString(line)
+	w.out.WriteString("\n")
+}
+
+// applySGR returns the styles in effect after seq: a reset clears them,
+// anything else adds to them
+func applySGR(style, seq string) string {
+	if seq == "\033[0m" || seq == "\033[m" {
+		return ""
+	}
+	return style + seq
+}
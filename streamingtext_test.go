@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func streamText(width int, chunks ...string) *StreamingText {
+	st := NewStreamingText(WithStreamCursor(""))
+	st.Update(tea.WindowSizeMsg{Width: width, Height: 10})
+	for _, c := range chunks {
+		st.Append(c)
+	}
+	return st
+}
+
+func TestStreamingTextWrapsIncrementally(t *testing.T) {
+	input := "The quick brown fox jumps over the lazy dog"
+	whole := streamText(12, input).View()
+	want := "The quick\nbrown fox\njumps over\nthe lazy dog\n"
+	if whole != want {
+		t.Fatalf("View() = %q, want %q", whole, want)
+	}
+
+	// Byte by byte, including partial words, gives the same lines
+	var chunks []string
+	for i := 0; i < len(input); i++ {
+		chunks = append(chunks, input[i:i+1])
+	}
+	st := streamText(12)
+	for _, c := range chunks {
+		st.Append(c)
+		for _, line := range strings.Split(strings.TrimSuffix(st.View(), "\n"), "\n") {
+			if len(line) > 12 {
+				t.Fatalf("Line %q is wider than 12 columns", line)
+			}
+		}
+	}
+	if st.View() != want {
+		t.Errorf("Streamed View() = %q, want %q", st.View(), want)
+	}
+}
+
+func TestStreamingTextPartialSequences(t *testing.T) {
+	// "héllo 世界" split inside the é and 世 encodings, and a color split
+	// inside its escape
+	st := streamText(40, "h\xc3", "\xa9llo \xe4\xb8", "\x96\xe7\x95\x8c \x1b[3", "1mred\x1b[0m")
+	if got := st.View(); got != "héllo 世界 \x1b[31mred\x1b[0m\n" {
+		t.Errorf("View() = %q", got)
+	}
+	if st.Text() != "héllo 世界 \x1b[31mred\x1b[0m" {
+		t.Errorf("Text() = %q", st.Text())
+	}
+
+	// A sequence cut off by the end of the stream is finalized
+	st.Append("!\xe4")
+	st.Finish()
+	if !strings.HasSuffix(st.View(), "!�\n") || st.Streaming() {
+		t.Errorf("Finish should replace the incomplete rune, got %q", st.View())
+	}
+}
+
+func TestStreamingTextMeasuresWideCharacters(t *testing.T) {
+	st := streamText(5, "世界 世界")
+	if got := st.View(); got != "世界\n世界\n" {
+		t.Errorf("Wide characters should take two columns, got %q", got)
+	}
+
+	long := streamText(4, "abcdefghij")
+	if got := long.View(); got != "abcd\nefgh\nij\n" {
+		t.Errorf("Words wider than the line should be broken, got %q", got)
+	}
+}
+
+func TestStreamingTextCarriesStylesAcrossWraps(t *testing.T) {
+	st := streamText(6, "\x1b[1mbold text\x1b[0m end")
+	lines := strings.Split(strings.TrimSuffix(st.View(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q", lines)
+	}
+	if lines[0] != "\x1b[1mbold\x1b[0m" || lines[1] != "\x1b[1mtext\x1b[0m" {
+		t.Errorf("Styles should be closed and reopened at wraps, got %q", lines)
+	}
+}
+
+func TestStreamingTextCursorAndResize(t *testing.T) {
+	st := NewStreamingText()
+	st.Update(tea.WindowSizeMsg{Width: 20, Height: 10})
+	st.Append("Thinking about it")
+	if !strings.HasSuffix(st.View(), "it▌\n") {
+		t.Errorf("The cursor should follow the text while streaming, got %q", st.View())
+	}
+	st.Append(" ")
+	if !strings.HasSuffix(st.View(), "it ▌\n") {
+		t.Errorf("The cursor should follow trailing spaces, got %q", st.View())
+	}
+
+	st.Update(tea.WindowSizeMsg{Width: 8, Height: 10})
+	if got := st.View(); got != "Thinking\nabout it\n▌\n" {
+		t.Errorf("Resizing should rewrap, got %q", got)
+	}
+
+	st.Finish()
+	if got := st.View(); got != "Thinking\nabout it\n" {
+		t.Errorf("Finish should remove the cursor, got %q", got)
+	}
+}
+
+func TestStreamingTextUnterminatedEscape(t *testing.T) {
+	// An OSC without its terminator cannot hold back the text after it
+	st := streamText(40, "hello\n\x1b]0;title", " world\nmore text\n")
+	st.Finish()
+	if got := st.View(); got != "hello\n]0;title world\nmore text\n" {
+		t.Errorf("View() = %q", got)
+	}
+}
+
+func TestStreamingTextLongWordsStreamed(t *testing.T) {
+	input := "see " + strings.Repeat("世界", 40) + " and https://example.com/" + strings.Repeat("a1b2", 30) + " end"
+	want := strings.Join(textwidth.Wrap(input, 17), "\n") + "\n"
+
+	st := streamText(17)
+	for _, r := range input {
+		st.Append(string(r))
+		st.View()
+		// Only the last grapheme of an unbroken run waits to be placed
+		if n := len(st.wrap.word); n > 17*4 {
+			t.Fatalf("Pending word grew to %d bytes", n)
+		}
+	}
+	if got := st.View(); got != want {
+		t.Errorf("Streamed View() = %q, want %q", got, want)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(want, "\n"), "\n") {
+		if w := textwidth.Width(line); w > 17 {
+			t.Errorf("Line %q is %d columns wide", line, w)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -34,6 +36,9 @@ const (
 // structuredDataTickMsg is sent periodically for animation
 type structuredDataTickMsg time.Time
 
+// Broadcast delivers ticks to every StructuredData in an Application
+func (structuredDataTickMsg) Broadcast() {}
+
 // DataItem represents a single item in structured data
 type DataItem struct {
 	Type   ItemType
@@ -50,19 +55,22 @@ type StructuredData struct {
 	items          []DataItem
 	focused        bool
 	expanded       bool
-	maxLines       int        // Max lines when collapsed (0 = show all)
-	icon           string     // Deprecated: use iconSet instead
-	keyWidth       int        // Width for key column (auto-calculated if 0)
-	status         DataStatus // Current status (Running, Success, Error, Info)
-	animationFrame int        // Frame counter for blinking animation
-	runningColor   string     // ANSI color code for running status (default: white)
-	successColor   string     // ANSI color code for success status
-	errorColor     string     // ANSI color code for error status
-	warningColor   string     // ANSI color code for warning status
-	infoColor      string     // ANSI color code for info status
-	noneColor      string     // ANSI color code for neutral status
-	spinner        Spinner    // Spinner animation
-	iconSet        IconSet    // Icon set for different statuses
+	maxLines       int         // Max lines when collapsed (0 = show all)
+	icon           string      // Deprecated: use iconSet instead
+	keyWidth       int         // Width for key column (auto-calculated if 0)
+	status         DataStatus  // Current status (Running, Success, Error, Info)
+	animationFrame int         // Frame counter for blinking animation
+	runningColor   string      // ANSI color code for running status (default: white)
+	successColor   string      // ANSI color code for success status
+	errorColor     string      // ANSI color code for error status
+	warningColor   string      // ANSI color code for warning status
+	infoColor      string      // ANSI color code for info status
+	noneColor      string      // ANSI color code for neutral status
+	spinner        Spinner     // Spinner animation
+	iconSet        IconSet     // Icon set for different statuses
+	keys           *KeyMap     // Rebindable keys
+	clock          *FrameClock // Shared frame clock; nil runs a timer of its own
+	lastFrame      time.Time
 }
 
 // NewStructuredData creates a new structured data component
@@ -81,6 +89,7 @@ func NewStructuredData(title string, opts ...StructuredDataOption) *StructuredDa
 		noneColor:    "\033[36m",     // Cyan
 		spinner:      SpinnerBlink,   // Default spinner
 		iconSet:      IconSetDefault, // Default icon set
+		keys:         DefaultStructuredDataKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -93,6 +102,21 @@ func NewStructuredData(title string, opts ...StructuredDataOption) *StructuredDa
 // StructuredDataOption configures a StructuredData
 type StructuredDataOption func(*StructuredData)
 
+// WithStructuredDataKeyMap replaces the default keymap
+func WithStructuredDataKeyMap(km *KeyMap) StructuredDataOption {
+	return func(sd *StructuredData) {
+		sd.keys = km
+	}
+}
+
+// DefaultStructuredDataKeyMap returns the default StructuredData bindings for
+// the action "toggle"
+func DefaultStructuredDataKeyMap() *KeyMap {
+	km := NewKeyMap("StructuredData")
+	km.Bind("toggle", key.NewBinding(key.WithKeys("ctrl+o", "enter"), key.WithHelp("ctrl+o", "expand")))
+	return km
+}
+
 // WithMaxLines sets the maximum lines to show when collapsed
 func WithStructuredDataMaxLines(n int) StructuredDataOption {
 	return func(sd *StructuredData) {
@@ -242,9 +266,27 @@ func (sd *StructuredData) Init() tea.Cmd {
 	return nil
 }
 
-// tick returns a command that sends a tick message after a delay
+// SetFrameClock animates the status icon on a shared clock instead of its own
+// timer
+func (sd *StructuredData) SetFrameClock(clock *FrameClock) {
+	sd.clock = clock
+}
+
+// Animating reports whether the running status icon is animating
+func (sd *StructuredData) Animating() bool {
+	return sd.status == DataStatusRunning
+}
+
+// structuredDataFrameInterval is how often the status icon steps
+const structuredDataFrameInterval = 500 * time.Millisecond
+
+// tick wakes the frame clock, or without one returns a command that sends a
+// tick message after a delay
 func (sd *StructuredData) tick() tea.Cmd {
-	return tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
+	if sd.clock != nil {
+		return sd.clock.Wake()
+	}
+	return tea.Tick(structuredDataFrameInterval, func(t time.Time) tea.Msg {
 		return structuredDataTickMsg(t)
 	})
 }
@@ -256,11 +298,8 @@ func (sd *StructuredData) Update(msg tea.Msg) (Component, tea.Cmd) {
 		sd.width = msg.Width
 
 	case tea.KeyMsg:
-		if sd.focused {
-			switch msg.String() {
-			case "ctrl+o", "enter":
-				sd.ToggleExpanded()
-			}
+		if sd.focused && sd.keys.Matches(msg, "toggle") {
+			sd.ToggleExpanded()
 		}
 
 	case structuredDataTickMsg:
@@ -268,6 +307,11 @@ func (sd *StructuredData) Update(msg tea.Msg) (Component, tea.Cmd) {
 			sd.animationFrame++
 			return sd, sd.tick()
 		}
+
+	case FrameMsg:
+		if sd.Animating() && frameDue(&sd.lastFrame, msg.Time, structuredDataFrameInterval) {
+			sd.animationFrame++
+		}
 	}
 	return sd, nil
 }
@@ -322,8 +366,8 @@ func (sd *StructuredData) View() string {
 
 	// Show "... +N items" if collapsed
 	if hiddenCount > 0 {
-		expandHint := fmt.Sprintf("     \033[2m… +%d items \033[0m\033[3m(ctrl+o to expand)\033[0m",
-			hiddenCount)
+		expandHint := fmt.Sprintf("     \033[2m… +%d items \033[0m\033[3m(%s to expand)\033[0m",
+			hiddenCount, sd.keys.hint("toggle"))
 		lines = append(lines, expandHint)
 	}
 
@@ -345,6 +389,11 @@ func (sd *StructuredData) Focused() bool {
 	return sd.focused
 }
 
+// KeyMap returns the component's bindings, which can be rebound
+func (sd *StructuredData) KeyMap() *KeyMap {
+	return sd.keys
+}
+
 // ToggleExpanded toggles the expanded state
 func (sd *StructuredData) ToggleExpanded() {
 	sd.expanded = !sd.expanded
@@ -404,7 +453,7 @@ func (sd *StructuredData) calculateKeyWidth() int {
 	maxWidth := 20 // Minimum width
 	for _, item := range sd.items {
 		if item.Type == ItemKeyValue && item.Key != "" {
-			keyLen := len(item.Key) + (item.Indent * 2)
+			keyLen := textwidth.Width(item.Key) + (item.Indent * 2)
 			if keyLen > maxWidth {
 				maxWidth = keyLen
 			}
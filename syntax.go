@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/SCKelemen/color"
+	design "github.com/SCKelemen/design-system"
+)
+
+// TokenKind classifies a span of source code for highlighting
+type TokenKind int
+
+const (
+	TokenText     TokenKind = iota // Plain text and whitespace
+	TokenKeyword                   // Language keywords (func, if, return)
+	TokenType                      // Built-in and declared type names
+	TokenFunction                  // Function and method names at call or definition sites
+	TokenString                    // String and character literals
+	TokenNumber                    // Numeric literals
+	TokenConstant                  // Literal constants (true, nil, None)
+	TokenComment                   // Comments
+	TokenOperator                  // Operators and punctuation
+	TokenVariable                  // Variables with sigils ($HOME, ${name})
+	TokenKey                       // Mapping keys in JSON and YAML
+	TokenHeading                   // Markdown headings
+	TokenEmphasis                  // Markdown emphasis and strong text
+	TokenLink                      // Markdown links
+)
+
+// Token is a span of source text and its kind
+type Token struct {
+	Kind TokenKind
+	Text string
+}
+
+// LexerState carries context from one line to the next, such as being inside
+// a block comment or a multi-line string. The zero value means "at the start
+// of the source, outside any construct".
+type LexerState int
+
+// Lexer splits source code into tokens one line at a time. Components render
+// code line by line, so lexers receive the state returned for the previous
+// line and return the state for the next one.
+//
+// The tokens returned for a line must concatenate back to exactly that line.
+type Lexer interface {
+	Tokenize(line string, state LexerState) ([]Token, LexerState)
+}
+
+var (
+	lexersMu   sync.RWMutex
+	lexers     = map[string]Lexer{}
+	extensions = map[string]string{}
+)
+
+// RegisterLexer makes a lexer available under a language name and any number
+// of aliases. Names are case-insensitive. Registering an existing name
+// replaces the previous lexer, so applications can override the built-ins.
+func RegisterLexer(language string, lexer Lexer, aliases ...string) {
+	lexersMu.Lock()
+	defer lexersMu.Unlock()
+
+	lexers[strings.ToLower(language)] = lexer
+	for _, alias := range aliases {
+		lexers[strings.ToLower(alias)] = lexer
+	}
+}
+
+// RegisterExtension associates a file extension (with or without the leading
+// dot) with a registered language name for LanguageForFilename.
+func RegisterExtension(ext, language string) {
+	lexersMu.Lock()
+	defer lexersMu.Unlock()
+
+	extensions[strings.ToLower(strings.TrimPrefix(ext, "."))] = strings.ToLower(language)
+}
+
+// LexerFor returns the lexer registered for a language name or alias, or nil
+// if the language is unknown.
+func LexerFor(language string) Lexer {
+	lexersMu.RLock()
+	defer lexersMu.RUnlock()
+
+	return lexers[strings.ToLower(strings.TrimSpace(language))]
+}
+
+// LanguageForFilename guesses a language name from a file's extension. It
+// returns an empty string when the extension is unknown.
+//
+//	cb := tui.NewCodeBlock(
+//	    tui.WithCodeFilename(path),
+//	    tui.WithLanguage(tui.LanguageForFilename(path)),
+//	)
+func LanguageForFilename(name string) string {
+	lexersMu.RLock()
+	defer lexersMu.RUnlock()
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	return extensions[ext]
+}
+
+// SyntaxTheme maps token kinds to the ANSI sequences used to draw them.
+// Kinds without an entry are drawn unstyled.
+type SyntaxTheme map[TokenKind]string
+
+// SyntaxThemeFromTokens derives a highlighting palette from design tokens.
+// Keywords use the accent color; other kinds rotate the accent's hue in OKLCH
+// so the palette stays balanced. Every color is adjusted to stay readable on
+// the theme background.
+func SyntaxThemeFromTokens(tokens *design.DesignTokens) SyntaxTheme {
+	if tokens == nil {
+		tokens = design.DefaultTheme()
+	}
+
+	accent, err := color.HexToRGB(tokens.Accent)
+	if err != nil {
+		return SyntaxTheme{}
+	}
+	foreground, err := color.HexToRGB(tokens.Color)
+	if err != nil {
+		return SyntaxTheme{}
+	}
+	background, err := color.HexToRGB(tokens.Background)
+	if err != nil {
+		return SyntaxTheme{}
+	}
+
+	readable := func(c color.Color) string {
+		if suggested, ok := color.SuggestAccessibleForeground(c, background, color.WCAGAA, color.NormalText); ok {
+			c = suggested
+		}
+		return ansiColorFromHex(color.RGBToHex(c))
+	}
+
+	keyword := readable(accent)
+	return SyntaxTheme{
+		TokenKeyword:  keyword,
+		TokenType:     readable(color.AdjustHue(accent, -60)),
+		TokenFunction: readable(color.AdjustHue(accent, 60)),
+		TokenString:   readable(color.AdjustHue(accent, 150)),
+		TokenNumber:   readable(color.AdjustHue(accent, -150)),
+		TokenConstant: readable(color.AdjustHue(accent, -150)),
+		TokenVariable: readable(color.AdjustHue(accent, 90)),
+		TokenKey:      readable(color.AdjustHue(accent, 60)),
+		TokenComment:  "\033[2m" + ansiColorFromHex(color.RGBToHex(color.Mix(foreground, background, 0.4))),
+		TokenOperator: ansiColorFromHex(tokens.Color),
+		TokenHeading:  "\033[1m" + keyword,
+		TokenEmphasis: "\033[1m",
+		TokenLink:     "\033[4m" + readable(color.AdjustHue(accent, 60)),
+	}
+}
+
+// DefaultSyntaxTheme returns the palette for the default design-system theme
+func DefaultSyntaxTheme() SyntaxTheme {
+	return SyntaxThemeFromTokens(design.DefaultTheme())
+}
+
+// defaultSyntaxTheme is computed once and shared by components that were not
+// given design tokens
+var defaultSyntaxTheme = sync.OnceValue(DefaultSyntaxTheme)
+
+// highlightLines tokenizes lines in order, carrying lexer state between them,
+// and returns each line with ANSI styling applied. A nil lexer returns the
+// lines unchanged.
+func highlightLines(lines []string, lexer Lexer, theme SyntaxTheme) []string {
+	if lexer == nil {
+		return lines
+	}
+
+	out := make([]string, len(lines))
+	var state LexerState
+	for i, line := range lines {
+		out[i], state = highlightLine(line, lexer, theme, state)
+	}
+	return out
+}
+
+// highlightCode highlights lines with an explicit lexer, or else the lexer
+// registered for language. A nil theme uses the default palette.
+func highlightCode(lines []string, lexer Lexer, language string, theme SyntaxTheme) []string {
+	if lexer == nil {
+		lexer = LexerFor(language)
+	}
+	if theme == nil {
+		theme = defaultSyntaxTheme()
+	}
+	return highlightLines(lines, lexer, theme)
+}
+
+// highlightLine styles a single line and returns the lexer state after it
+func highlightLine(line string, lexer Lexer, theme SyntaxTheme, state LexerState) (string, LexerState) {
+	tokens, next := lexer.Tokenize(line, state)
+
+	var b strings.Builder
+	for _, tok := range tokens {
+		style := theme[tok.Kind]
+		if style == "" || strings.TrimSpace(tok.Text) == "" {
+			b.WriteString(tok.Text)
+			continue
+		}
+		b.WriteString(style)
+		b.WriteString(tok.Text)
+		b.WriteString("\033[0m")
+	}
+	return b.String(), next
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tokenize runs a lexer over lines and returns the tokens of each line
+func tokenize(lexer Lexer, lines ...string) [][]Token {
+	var out [][]Token
+	var state LexerState
+	for _, line := range lines {
+		var toks []Token
+		toks, state = lexer.Tokenize(line, state)
+		out = append(out, toks)
+	}
+	return out
+}
+
+// kindOf returns the kind of the first token with exactly the given text
+func kindOf(tokens []Token, text string) (TokenKind, bool) {
+	for _, tok := range tokens {
+		if tok.Text == text {
+			return tok.Kind, true
+		}
+	}
+	return TokenText, false
+}
+
+func TestLexersPreserveText(t *testing.T) {
+	samples := map[string][]string{
+		"go":         {`func main() { fmt.Println("hi", 42) } // done`, "\ts := `raw", "still raw` + x"},
+		"json":       {`{"name": "tui", "count": 3, "ok": true}`},
+		"yaml":       {`key: value # comment`, `- item: "quoted"`, `---`},
+		"shell":      {`echo "$HOME" ${USER} # note`, `ls -la | grep foo`},
+		"python":     {`def f(x): return """doc`, `more""" + 'a'`, `@decorator`},
+		"typescript": {`const x: number = 1.5; /* block`, `end */ let y = 'z'`},
+		"markdown":   {"# Title", "Some *emphasis* and `code` and [link](url)", "```go", "x := 1", "```"},
+	}
+
+	for lang, lines := range samples {
+		lexer := LexerFor(lang)
+		if lexer == nil {
+			t.Fatalf("No lexer registered for %s", lang)
+		}
+		for i, toks := range tokenize(lexer, lines...) {
+			var b strings.Builder
+			for _, tok := range toks {
+				b.WriteString(tok.Text)
+			}
+			if b.String() != lines[i] {
+				t.Errorf("%s: tokens rebuild %q, want %q", lang, b.String(), lines[i])
+			}
+		}
+	}
+}
+
+func TestGoLexerKinds(t *testing.T) {
+	toks := tokenize(LexerFor("go"), `func main() { return "hi", 42, nil } // done`)[0]
+
+	tests := []struct {
+		text string
+		want TokenKind
+	}{
+		{"func", TokenKeyword},
+		{"main", TokenFunction},
+		{"return", TokenKeyword},
+		{`"hi"`, TokenString},
+		{"42", TokenNumber},
+		{"nil", TokenConstant},
+		{"// done", TokenComment},
+	}
+
+	for _, tt := range tests {
+		kind, ok := kindOf(toks, tt.text)
+		if !ok {
+			t.Errorf("No token %q in %+v", tt.text, toks)
+			continue
+		}
+		if kind != tt.want {
+			t.Errorf("Token %q has kind %d, want %d", tt.text, kind, tt.want)
+		}
+	}
+}
+
+func TestLexerStateSpansLines(t *testing.T) {
+	lines := tokenize(LexerFor("go"), "x := 1 /* start", "inside", "end */ y", "s := `raw", "body", "done`")
+
+	if kind, _ := kindOf(lines[1], "inside"); kind != TokenComment {
+		t.Error("Line inside a block comment should be a comment")
+	}
+	if kind, _ := kindOf(lines[2], "y"); kind != TokenText {
+		t.Error("Text after a closed block comment should not be a comment")
+	}
+	if kind, _ := kindOf(lines[4], "body"); kind != TokenString {
+		t.Error("Line inside a raw string should be a string")
+	}
+
+	py := tokenize(LexerFor("python"), `x = """doc`, `still doc`, `end""" + y`)
+	if kind, _ := kindOf(py[1], "still doc"); kind != TokenString {
+		t.Error("Line inside a triple-quoted string should be a string")
+	}
+}
+
+func TestDataLexerKeys(t *testing.T) {
+	json := tokenize(LexerFor("json"), `{"name": "tui"}`)[0]
+	if kind, _ := kindOf(json, `"name"`); kind != TokenKey {
+		t.Errorf("JSON key should be a key, got %d", kind)
+	}
+	if kind, _ := kindOf(json, `"tui"`); kind != TokenString {
+		t.Errorf("JSON value should be a string, got %d", kind)
+	}
+
+	yaml := tokenize(LexerFor("yaml"), `  name: true # flag`)[0]
+	if kind, _ := kindOf(yaml, "name"); kind != TokenKey {
+		t.Errorf("YAML key should be a key, got %d", kind)
+	}
+	if kind, _ := kindOf(yaml, "true"); kind != TokenConstant {
+		t.Errorf("YAML boolean should be a constant, got %d", kind)
+	}
+	if kind, _ := kindOf(yaml, "# flag"); kind != TokenComment {
+		t.Errorf("YAML comment should be a comment, got %d", kind)
+	}
+}
+
+func TestShellLexerVariables(t *testing.T) {
+	toks := tokenize(LexerFor("bash"), `echo $HOME ${USER} a#b`)[0]
+
+	for _, v := range []string{"$HOME", "${USER}"} {
+		if kind, _ := kindOf(toks, v); kind != TokenVariable {
+			t.Errorf("%s should be a variable, got %d", v, kind)
+		}
+	}
+	for _, tok := range toks {
+		if tok.Kind == TokenComment {
+			t.Errorf("# inside a word should not start a comment, got %q", tok.Text)
+		}
+	}
+}
+
+func TestMarkdownLexer(t *testing.T) {
+	lines := tokenize(LexerFor("md"), "## Heading", "```go", "# not a heading", "```", "after")
+
+	if lines[0][0].Kind != TokenHeading {
+		t.Error("Heading line should be a heading")
+	}
+	for _, tok := range lines[2] {
+		if tok.Kind == TokenHeading {
+			t.Error("Lines inside a fence should not be headings")
+		}
+	}
+	if kind, _ := kindOf(lines[4], "after"); kind != TokenText {
+		t.Error("Text after a closed fence should be plain")
+	}
+}
+
+func TestLexerRegistry(t *testing.T) {
+	if LexerFor("Go") == nil || LexerFor("golang") == nil {
+		t.Error("Lexer lookup should be case-insensitive and support aliases")
+	}
+	if LexerFor("cobol") != nil {
+		t.Error("Unknown language should have no lexer")
+	}
+
+	tests := map[string]string{
+		"main.go":      "go",
+		"config.YML":   "yaml",
+		"script.sh":    "shell",
+		"app.tsx":      "typescript",
+		"README.md":    "markdown",
+		"unknown.xyz":  "",
+		"no-extension": "",
+	}
+	for name, want := range tests {
+		if got := LanguageForFilename(name); got != want {
+			t.Errorf("LanguageForFilename(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	RegisterLexer("test-upper", upperLexer{})
+	RegisterExtension(".tup", "test-upper")
+	if LexerFor(LanguageForFilename("x.tup")) == nil {
+		t.Error("Registered lexer should be found by extension")
+	}
+}
+
+// upperLexer marks every line as a keyword
+type upperLexer struct{}
+
+func (upperLexer) Tokenize(line string, state LexerState) ([]Token, LexerState) {
+	return []Token{{Kind: TokenKeyword, Text: line}}, state
+}
+
+func TestSyntaxThemeFromTokens(t *testing.T) {
+	for _, name := range []string{"default", "midnight", "nord", "paper", "wrapped"} {
+		theme := SyntaxThemeFromTokens(designTokensForTheme(name))
+		for _, kind := range []TokenKind{TokenKeyword, TokenString, TokenComment, TokenFunction} {
+			if theme[kind] == "" {
+				t.Errorf("Theme %s has no style for kind %d", name, kind)
+			}
+		}
+	}
+}
+
+func TestHighlightLineResetsStyles(t *testing.T) {
+	theme := SyntaxTheme{TokenKeyword: "\033[35m"}
+	got, _ := highlightLine("return x", LexerFor("go"), theme, 0)
+
+	if got != "\033[35mreturn\033[0m x" {
+		t.Errorf("Unexpected highlight output %q", got)
+	}
+	if textwidth.Strip(got) != "return x" {
+		t.Error("Highlighting should not change visible text")
+	}
+}
+
+func TestCodeBlockHighlightsOnlyWithLanguage(t *testing.T) {
+	plain := NewCodeBlock(WithCode("package main"), WithCodeFilename("main.go"))
+	if !strings.Contains(plain.View(), "package main") {
+		t.Error("Code without a language should render unstyled")
+	}
+
+	cb := NewCodeBlock(WithCode("package main"), WithLanguage("go"), WithCodeTheme("nord"))
+	view := cb.View()
+	if strings.Contains(view, "package main") {
+		t.Error("Code with a language should be highlighted")
+	}
+	if !strings.Contains(textwidth.Strip(view), "package main") {
+		t.Error("Highlighted code should keep its text")
+	}
+
+	custom := NewCodeBlock(WithCode("anything"), WithCodeLexer(upperLexer{}))
+	if !strings.Contains(custom.View(), defaultSyntaxTheme()[TokenKeyword]+"anything") {
+		t.Error("Custom lexer should be used for highlighting")
+	}
+}
+
+func TestConfirmationBlockHighlightsCode(t *testing.T) {
+	cb := NewConfirmationBlock(WithConfirmCode(`name: "tui"`), WithConfirmLanguage("yaml"))
+	cb.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	view := cb.View()
+	if strings.Contains(view, `name: "tui"`) {
+		t.Error("Code preview should be highlighted")
+	}
+	if !strings.Contains(textwidth.Strip(view), `name: "tui"`) {
+		t.Error("Highlighted preview should keep its text")
+	}
+}
+
+func TestDiffBlockHighlightsSidesSeparately(t *testing.T) {
+	db := NewDiffBlock(
+		WithDiffLanguage("go"),
+		WithDiffExpanded(true),
+		WithDiffLines([]DiffLine{
+			{Type: DiffRemoved, Content: "x := 1 /* open", LineNum: 1},
+			{Type: DiffAdded, Content: "return y", LineNum: 1},
+		}),
+	)
+
+	contents, _ := db.highlightContents()
+	keyword := defaultSyntaxTheme()[TokenKeyword]
+	if !strings.Contains(contents[1], keyword+"return") {
+		t.Errorf("Added line should not inherit the removed line's comment: %q", contents[1])
+	}
+	if !strings.Contains(textwidth.Strip(db.View()), "-x := 1 /* open") {
+		t.Error("Highlighted diff should keep the sign and text")
+	}
+}
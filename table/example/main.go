@@ -86,5 +86,34 @@ func main() {
 	t7.AddRow("C", "Another very long string that will expand the column", "X")
 	t7.PrintSimple()
 
+	fmt.Println()
+
+	// Example 8: Column alignment, width limits and wrapping
+	fmt.Println("8. Regions (Column specs):")
+	t8 := table.New("Region", "Requests", "Latency", "Notes")
+	t8.SetColumns(
+		table.Column{},
+		table.Column{Align: table.AlignNumber},
+		table.Column{Align: table.AlignRight},
+		table.Column{MaxWidth: 24, Wrap: true},
+	)
+	t8.AddRow("東京 🇯🇵", "1,204,332", "38ms", "Primary region, serves most of Asia")
+	t8.AddRow("Zürich", "88,120", "112ms", "Failover")
+	t8.AddRow("São Paulo", "402,917", "97ms", "\033[33mDegraded\033[0m since the last deploy")
+	t8.PrintSimple()
+
+	fmt.Println()
+
+	// Example 9: Machine-readable output, as for an --output flag
+	fmt.Println("9. Service Status (CSV and JSON):")
+	for _, name := range []string{"csv", "json"} {
+		format, err := table.ParseFormat(name)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		t1.PrintFormat(format)
+	}
+
 	fmt.Println("\n=== All Examples Complete ===")
 }
@@ -0,0 +1,341 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+)
+
+// Format selects how a table is rendered
+type Format int
+
+const (
+	// FormatAuto renders FormatTable when stdout is a terminal and
+	// FormatPlain when it is piped or redirected
+	FormatAuto Format = iota
+	// FormatTable draws the table with borders, like RenderSimple
+	FormatTable
+	// FormatPlain aligns columns with spaces and no borders or colors, for
+	// piping into awk, cut or grep
+	FormatPlain
+	// FormatCSV renders comma-separated values with a header line
+	FormatCSV
+	// FormatTSV renders tab-separated values with a header line
+	FormatTSV
+	// FormatJSON renders an array of objects keyed by header
+	FormatJSON
+	// FormatYAML renders a list of mappings keyed by header
+	FormatYAML
+	// FormatMarkdown renders a GitHub-flavored Markdown table
+	FormatMarkdown
+)
+
+var formatNames = []string{"auto", "table", "plain", "csv", "tsv", "json", "yaml", "markdown"}
+
+// String returns the name of the format, as accepted by ParseFormat
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the format with the given name, such as the value of
+// an --output flag. "yml" and "md" are accepted as well.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yml":
+		return FormatYAML, nil
+	case "md":
+		return FormatMarkdown, nil
+	}
+	for i, n := range formatNames {
+		if strings.EqualFold(name, n) {
+			return Format(i), nil
+		}
+	}
+	return FormatAuto, fmt.Errorf("unknown table format %q (want one of %s)", name, strings.Join(formatNames, ", "))
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal rather than a pipe
+// or file
+var stdoutIsTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// RenderFormat renders the table in the given format. Machine-readable
+// formats carry the full cell text without ANSI escapes, ignoring column
+// width limits.
+func (t *Table) RenderFormat(format Format) string {
+	if format == FormatAuto {
+		format = FormatPlain
+		if stdoutIsTerminal() {
+			format = FormatTable
+		}
+	}
+
+	switch format {
+	case FormatTable:
+		return t.RenderSimple()
+	case FormatPlain:
+		return t.renderPlain()
+	case FormatCSV:
+		return t.renderCSV()
+	case FormatTSV:
+		return t.renderTSV()
+	case FormatJSON:
+		return t.renderJSON()
+	case FormatYAML:
+		return t.renderYAML()
+	case FormatMarkdown:
+		return t.renderMarkdown()
+	}
+	return ""
+}
+
+// PrintFormat renders the table in the given format and prints it to stdout
+func (t *Table) PrintFormat(format Format) {
+	fmt.Println(strings.TrimSuffix(t.RenderFormat(format), "\n"))
+}
+
+// plainCells returns the headers and rows with ANSI escapes removed
+func (t *Table) plainCells() (headers []string, rows [][]string) {
+	strip := func(cells []string) []string {
+		out := make([]string, len(cells))
+		for i, cell := range cells {
+			out[i] = textwidth.Strip(cell)
+		}
+		return out
+	}
+
+	headers = strip(t.headers)
+	for _, row := range t.rows {
+		rows = append(rows, strip(row))
+	}
+	return headers, rows
+}
+
+// renderPlain renders columns separated by three spaces, one line per row.
+// Newlines in cells become spaces, and nothing is truncated.
+func (t *Table) renderPlain() string {
+	if len(t.headers) == 0 {
+		return ""
+	}
+
+	headers, rows := t.plainCells()
+	lines := append([][]string{headers}, rows...)
+	widths := make([]int, len(headers))
+	for _, line := range lines {
+		for i, cell := range line {
+			line[i] = strings.Join(strings.Fields(cell), " ")
+			widths[i] = max(widths[i], textwidth.Width(line[i]))
+		}
+	}
+
+	var b strings.Builder
+	for l, line := range lines {
+		var parts []string
+		for i, cell := range line {
+			part := t.align(i, cell, widths[i], t.rightAligned(i, cell, l == 0))
+			if i == len(line)-1 {
+				part = strings.TrimRight(part, " ")
+			}
+			parts = append(parts, part)
+		}
+		b.WriteString(strings.Join(parts, "   "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderCSV renders RFC 4180 comma-separated values
+func (t *Table) renderCSV() string {
+	if len(t.headers) == 0 {
+		return ""
+	}
+
+	headers, rows := t.plainCells()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(headers)
+	w.WriteAll(rows)
+	return buf.String()
+}
+
+// tsvEscaper escapes the characters that would break tab-separated fields
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// renderTSV renders tab-separated values, escaping tabs, newlines and
+// backslashes in cells as \t, \n and \\
+func (t *Table) renderTSV() string {
+	if len(t.headers) == 0 {
+		return ""
+	}
+
+	headers, rows := t.plainCells()
+
+	var b strings.Builder
+	for _, line := range append([][]string{headers}, rows...) {
+		for i, cell := range line {
+			if i > 0 {
+				b.WriteString("\t")
+			}
+			b.WriteString(tsvEscaper.Replace(cell))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderJSON renders an array with an object per row. Keys keep the order
+// of the headers.
+func (t *Table) renderJSON() string {
+	headers, rows := t.plainCells()
+	if len(rows) == 0 {
+		return "[]\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("[\n")
+	for r, row := range rows {
+		b.WriteString("  {\n")
+		for i, cell := range row {
+			fmt.Fprintf(&b, "    %s: %s", jsonString(headers[i]), jsonString(cell))
+			if i < len(row)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("  }")
+		if r < len(rows)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	return b.String()
+}
+
+// jsonString returns s as a JSON string, leaving <, > and & unescaped
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// renderYAML renders a sequence with a mapping per row. Keys keep the order
+// of the headers, and every value is a string.
+func (t *Table) renderYAML() string {
+	headers, rows := t.plainCells()
+	if len(rows) == 0 {
+		return "[]\n"
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			fmt.Fprintf(&b, "%s%s: %s\n", prefix, yamlString(headers[i]), yamlString(cell))
+		}
+	}
+	return b.String()
+}
+
+// yamlString returns s as a YAML scalar, double-quoted unless it reads back
+// as the same plain string
+func yamlString(s string) string {
+	if yamlNeedsQuotes(s) {
+		// JSON strings are valid double-quoted YAML scalars
+		return jsonString(s)
+	}
+	return s
+}
+
+// yamlNeedsQuotes reports whether s would be misread as a plain scalar: it
+// is empty, has surrounding spaces, special characters or control
+// characters, or could be read as a boolean, null, number or date
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	// Numbers and dates start with a digit, sign or dot
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`+.0123456789") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return true
+	}
+	return false
+}
+
+// markdownEscaper escapes the characters that would break a Markdown table
+// row
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// renderMarkdown renders a GitHub-flavored Markdown table, with column
+// alignment shown in the delimiter row
+func (t *Table) renderMarkdown() string {
+	if len(t.headers) == 0 {
+		return ""
+	}
+
+	headers, rows := t.plainCells()
+	lines := append([][]string{headers}, rows...)
+	widths := make([]int, len(headers))
+	for _, line := range lines {
+		for i, cell := range line {
+			line[i] = markdownEscaper.Replace(cell)
+			widths[i] = max(widths[i], textwidth.Width(line[i]), 3)
+		}
+	}
+
+	writeRow := func(b *strings.Builder, cells []string) {
+		b.WriteString("|")
+		for i, cell := range cells {
+			b.WriteString(" " + textwidth.PadRight(cell, widths[i]) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	var b strings.Builder
+	writeRow(&b, lines[0])
+
+	b.WriteString("|")
+	for i, width := range widths {
+		dashes := strings.Repeat("-", width)
+		switch align := t.columns[i].Align; {
+		case align == AlignRight, align == AlignNumber && t.rightAligned(i, "", true):
+			dashes = dashes[:width-1] + ":"
+		case align == AlignCenter:
+			dashes = ":" + dashes[:width-2] + ":"
+		}
+		b.WriteString(" " + dashes + " |")
+	}
+	b.WriteString("\n")
+
+	for _, row := range lines[1:] {
+		writeRow(&b, row)
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newFormatTable() *Table {
+	table := New("Name", "Count", "Note")
+	table.SetColumn(1, Column{Align: AlignNumber})
+	table.AddRow("\033[32mweb\033[0m", "12", "a, \"quoted\" note")
+	table.AddRow("db", "3", "multi\nline")
+	return table
+}
+
+func TestRenderFormatPlain(t *testing.T) {
+	got := newFormatTable().RenderFormat(FormatPlain)
+	want := "Name   Count   Note\n" +
+		"web       12   a, \"quoted\" note\n" +
+		"db         3   multi line\n"
+	if got != want {
+		t.Errorf("plain output = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFormatCSVAndTSV(t *testing.T) {
+	table := newFormatTable()
+
+	records, err := csv.NewReader(strings.NewReader(table.RenderFormat(FormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV output should parse: %v", err)
+	}
+	if len(records) != 3 || records[1][0] != "web" || records[1][2] != "a, \"quoted\" note" || records[2][2] != "multi\nline" {
+		t.Errorf("CSV records = %q", records)
+	}
+
+	want := "Name\tCount\tNote\nweb\t12\ta, \"quoted\" note\ndb\t3\tmulti\\nline\n"
+	if got := table.RenderFormat(FormatTSV); got != want {
+		t.Errorf("TSV output = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFormatJSON(t *testing.T) {
+	out := newFormatTable().RenderFormat(FormatJSON)
+
+	var rows []map[string]string
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("JSON output should parse: %v\n%s", err, out)
+	}
+	if len(rows) != 2 || rows[0]["Name"] != "web" || rows[1]["Note"] != "multi\nline" {
+		t.Errorf("JSON rows = %v", rows)
+	}
+	// Keys follow the header order
+	if strings.Index(out, `"Name"`) > strings.Index(out, `"Count"`) {
+		t.Errorf("keys should keep header order:\n%s", out)
+	}
+
+	if got := New("A").RenderFormat(FormatJSON); got != "[]\n" {
+		t.Errorf("empty table JSON = %q, want %q", got, "[]\n")
+	}
+}
+
+func TestRenderFormatYAML(t *testing.T) {
+	table := New("name", "value")
+	table.AddRow("plain", "yes")
+	table.AddRow("key: value", "007")
+
+	want := "- name: plain\n" +
+		"  value: \"yes\"\n" +
+		"- name: \"key: value\"\n" +
+		"  value: \"007\"\n"
+	if got := table.RenderFormat(FormatYAML); got != want {
+		t.Errorf("YAML output = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFormatMarkdown(t *testing.T) {
+	table := New("Name", "Count", "Mode")
+	table.SetColumns(Column{}, Column{Align: AlignNumber}, Column{Align: AlignCenter})
+	table.AddRow("a|b", "12", "x")
+
+	want := "| Name | Count | Mode |\n" +
+		"| ---- | ----: | :--: |\n" +
+		"| a\\|b | 12    | x    |\n"
+	if got := table.RenderFormat(FormatMarkdown); got != want {
+		t.Errorf("Markdown output = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFormatAuto(t *testing.T) {
+	defer func(orig func() bool) { stdoutIsTerminal = orig }(stdoutIsTerminal)
+	table := newFormatTable()
+
+	stdoutIsTerminal = func() bool { return true }
+	if got := table.RenderFormat(FormatAuto); got != table.RenderSimple() {
+		t.Errorf("auto format on a terminal should draw borders, got:\n%s", got)
+	}
+
+	stdoutIsTerminal = func() bool { return false }
+	if got := table.RenderFormat(FormatAuto); got != table.RenderFormat(FormatPlain) {
+		t.Errorf("auto format when piped should be plain, got:\n%s", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"auto", "table", "plain", "csv", "tsv", "json", "yaml", "markdown"} {
+		format, err := ParseFormat(name)
+		if err != nil || format.String() != name {
+			t.Errorf("ParseFormat(%q) = %v, %v", name, format, err)
+		}
+	}
+	if format, err := ParseFormat("YML"); err != nil || format != FormatYAML {
+		t.Errorf("ParseFormat(\"YML\") = %v, %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") should fail")
+	}
+}
@@ -19,11 +19,27 @@
 //	├───────────┼─────────┼─────┤
 //	│ service-b │ Stopped │ 5h  │
 //	└───────────┴─────────┴─────┘
+//
+// Columns can be aligned, limited in width and wrapped with SetColumn:
+//
+//	table.SetColumn(2, table.Column{Align: table.AlignNumber, MaxWidth: 10})
+//
+// Widths are measured in terminal columns, so emoji, CJK text and cells
+// colored with ANSI escapes line up.
+//
+// RenderFormat renders the same table as CSV, TSV, JSON, YAML, Markdown or
+// borderless plain text for scripts, and Print falls back to plain text when
+// stdout is not a terminal.
 package table
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"unicode"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
 )
 
 // BorderStyle defines the characters used for table borders
@@ -89,11 +105,36 @@ var (
 	}
 )
 
+// Alignment positions text within a column
+type Alignment int
+
+const (
+	// AlignLeft aligns text to the left edge of the column (default)
+	AlignLeft Alignment = iota
+	// AlignRight aligns text to the right edge of the column
+	AlignRight
+	// AlignCenter centers text in the column
+	AlignCenter
+	// AlignNumber right-aligns cells holding numbers and left-aligns the rest.
+	// The header is right-aligned when every cell in the column is a number.
+	AlignNumber
+)
+
+// Column configures how a column is aligned and sized. The zero value is a
+// left-aligned column as wide as its widest cell.
+type Column struct {
+	Align    Alignment
+	MinWidth int  // Narrowest the column may be
+	MaxWidth int  // Widest the column may be, 0 for no limit
+	Wrap     bool // Wrap cells wider than MaxWidth onto more lines instead of truncating them with "…"
+}
+
 // Table represents a static table for CLI output
 type Table struct {
 	headers     []string
 	rows        [][]string
-	widths      []int
+	widths      []int // Widest cell in each column, in terminal columns
+	columns     []Column
 	borderStyle BorderStyle
 	headerBold  bool
 }
@@ -103,13 +144,14 @@ func New(headers ...string) *Table {
 	t := &Table{
 		headers:     headers,
 		widths:      make([]int, len(headers)),
+		columns:     make([]Column, len(headers)),
 		borderStyle: BorderStyleRounded,
 		headerBold:  true,
 	}
 
-	// Initialize widths with header lengths
+	// Initialize widths with header widths
 	for i, h := range headers {
-		t.widths[i] = len(h)
+		t.widths[i] = cellWidth(h)
 	}
 
 	return t
@@ -125,6 +167,21 @@ func (t *Table) SetHeaderBold(bold bool) {
 	t.headerBold = bold
 }
 
+// SetColumn configures the column at index i. Indexes outside the headers
+// are ignored.
+func (t *Table) SetColumn(i int, col Column) {
+	if i >= 0 && i < len(t.columns) {
+		t.columns[i] = col
+	}
+}
+
+// SetColumns configures the columns in order, starting with the first
+func (t *Table) SetColumns(cols ...Column) {
+	for i, col := range cols {
+		t.SetColumn(i, col)
+	}
+}
+
 // AddRow adds a row to the table
 func (t *Table) AddRow(cells ...string) {
 	// Pad cells to match header count
@@ -133,8 +190,8 @@ func (t *Table) AddRow(cells ...string) {
 
 	// Update column widths
 	for i, cell := range row {
-		if i < len(t.widths) && len(cell) > t.widths[i] {
-			t.widths[i] = len(cell)
+		if i < len(t.widths) {
+			t.widths[i] = max(t.widths[i], cellWidth(cell))
 		}
 	}
 
@@ -151,9 +208,9 @@ func (t *Table) AddRows(rows [][]string) {
 // Clear removes all rows but keeps headers
 func (t *Table) Clear() {
 	t.rows = nil
-	// Reset widths to header lengths
+	// Reset widths to header widths
 	for i, h := range t.headers {
-		t.widths[i] = len(h)
+		t.widths[i] = cellWidth(h)
 	}
 }
 
@@ -170,7 +227,7 @@ func (t *Table) Render() string {
 	b.WriteString("\n")
 
 	// Headers
-	b.WriteString(t.renderRow(t.headers, t.headerBold))
+	b.WriteString(t.renderRow(t.headers, true))
 	b.WriteString("\n")
 
 	// Header separator
@@ -200,39 +257,137 @@ func (t *Table) String() string {
 	return t.Render()
 }
 
+// columnWidths returns the width of each column, its widest cell limited by
+// the column's minimum and maximum
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.widths))
+	for i, width := range t.widths {
+		col := t.columns[i]
+		if col.MaxWidth > 0 {
+			width = min(width, col.MaxWidth)
+		}
+		widths[i] = max(width, col.MinWidth)
+	}
+	return widths
+}
+
 // renderBorder renders a horizontal border line
 func (t *Table) renderBorder(left, middle, right string) string {
 	var parts []string
-	for _, width := range t.widths {
+	for _, width := range t.columnWidths() {
 		parts = append(parts, strings.Repeat(t.borderStyle.Horizontal, width+2))
 	}
 	return left + strings.Join(parts, middle) + right
 }
 
-// renderRow renders a single row
-func (t *Table) renderRow(cells []string, bold bool) string {
-	var parts []string
+// renderRow renders a single row. Cells that wrap or contain newlines make
+// the row span several lines.
+func (t *Table) renderRow(cells []string, header bool) string {
+	bold := header && t.headerBold
+	widths := t.columnWidths()
+
+	cellLines := make([][]string, len(cells))
+	height := 1
 	for i, cell := range cells {
-		width := t.widths[i]
-		padded := t.pad(cell, width)
-		if bold {
-			padded = "\033[1m" + padded + "\033[0m"
+		cellLines[i] = t.cellLines(i, cell, widths[i])
+		height = max(height, len(cellLines[i]))
+	}
+
+	lines := make([]string, height)
+	for l := range lines {
+		var parts []string
+		for i, cell := range cells {
+			var text string
+			if l < len(cellLines[i]) {
+				text = cellLines[i][l]
+			}
+			padded := t.align(i, text, widths[i], t.rightAligned(i, cell, header))
+			if bold {
+				padded = "\033[1m" + padded + "\033[0m"
+			}
+			parts = append(parts, " "+padded+" ")
 		}
-		parts = append(parts, " "+padded+" ")
+		lines[l] = t.borderStyle.Vertical + strings.Join(parts, t.borderStyle.Vertical) + t.borderStyle.Vertical
 	}
-	return t.borderStyle.Vertical + strings.Join(parts, t.borderStyle.Vertical) + t.borderStyle.Vertical
+	return strings.Join(lines, "\n")
 }
 
-// pad pads a string to the specified width
-func (t *Table) pad(s string, width int) string {
-	if len(s) >= width {
-		return s
+// cellLines splits a cell into the lines it occupies in a column of the
+// given width, wrapping or truncating lines that are too wide
+func (t *Table) cellLines(i int, cell string, width int) []string {
+	if t.columns[i].Wrap {
+		return textwidth.Wrap(cell, width)
+	}
+	lines := strings.Split(cell, "\n")
+	for l, line := range lines {
+		lines[l] = textwidth.TruncateWith(line, width, "…")
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return lines
 }
 
-// Print renders and prints the table to stdout
+// align pads text to width as column i is aligned. right reports whether an
+// AlignNumber column right-aligns the cell the text belongs to.
+func (t *Table) align(i int, text string, width int, right bool) string {
+	switch t.columns[i].Align {
+	case AlignRight:
+		return textwidth.PadLeft(text, width)
+	case AlignCenter:
+		return textwidth.Center(text, width)
+	case AlignNumber:
+		if right {
+			return textwidth.PadLeft(text, width)
+		}
+	}
+	return textwidth.PadRight(text, width)
+}
+
+// rightAligned reports whether an AlignNumber column right-aligns a cell: a
+// body cell when it holds a number, and the header when every body cell does
+func (t *Table) rightAligned(i int, cell string, header bool) bool {
+	if t.columns[i].Align != AlignNumber {
+		return false
+	}
+	if !header {
+		return isNumber(cell)
+	}
+	for _, row := range t.rows {
+		if !isNumber(row[i]) {
+			return false
+		}
+	}
+	return len(t.rows) > 0
+}
+
+// isNumber reports whether a cell holds a finite number such as "42",
+// "-3.5", "1,024" or "87%". Words ParseFloat accepts, like "NaN", "Inf" and
+// hex or exponent forms, are text.
+func isNumber(cell string) bool {
+	s := strings.TrimSpace(textwidth.Strip(cell))
+	s = strings.TrimSuffix(s, "%")
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" || strings.IndexFunc(s, unicode.IsLetter) >= 0 {
+		return false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// cellWidth returns the width of a cell's widest line
+func cellWidth(cell string) int {
+	width := 0
+	for _, line := range strings.Split(cell, "\n") {
+		width = max(width, textwidth.Width(line))
+	}
+	return width
+}
+
+// Print renders and prints the table to stdout. When stdout is not a
+// terminal the table is printed in FormatPlain instead.
 func (t *Table) Print() {
+	if !stdoutIsTerminal() {
+		t.PrintFormat(FormatPlain)
+		return
+	}
 	fmt.Println(t.Render())
 }
 
@@ -249,7 +404,7 @@ func (t *Table) RenderSimple() string {
 	b.WriteString("\n")
 
 	// Headers
-	b.WriteString(t.renderRow(t.headers, t.headerBold))
+	b.WriteString(t.renderRow(t.headers, true))
 	b.WriteString("\n")
 
 	// Header separator
@@ -268,7 +423,9 @@ func (t *Table) RenderSimple() string {
 	return b.String()
 }
 
-// PrintSimple renders and prints the table in simple mode (no row separators)
+// PrintSimple renders and prints the table in simple mode (no row
+// separators). When stdout is not a terminal the table is printed in
+// FormatPlain instead.
 func (t *Table) PrintSimple() {
-	fmt.Println(t.RenderSimple())
+	fmt.Println(t.RenderFormat(FormatAuto))
 }
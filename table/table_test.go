@@ -3,6 +3,8 @@ package table
 import (
 	"strings"
 	"testing"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
 )
 
 func TestNew(t *testing.T) {
@@ -189,6 +191,106 @@ func TestWidthCalculation(t *testing.T) {
 	}
 }
 
+func TestWideCharacterAlignment(t *testing.T) {
+	table := New("Name", "Note")
+	table.AddRow("東京", "🚀 launch")
+	table.AddRow("Zoë", "café")
+
+	if table.widths[0] != 4 || table.widths[1] != 9 {
+		t.Errorf("expected widths [4 9], got %v", table.widths)
+	}
+
+	// Every line should end its border in the same column
+	lines := strings.Split(table.Render(), "\n")
+	want := textwidth.Width(lines[0])
+	for _, line := range lines {
+		if got := textwidth.Width(line); got != want {
+			t.Errorf("line %q is %d columns wide, want %d", line, got, want)
+		}
+	}
+}
+
+func TestColumnAlignment(t *testing.T) {
+	table := New("Name", "Count", "Mode")
+	table.SetHeaderBold(false)
+	table.SetColumns(Column{}, Column{Align: AlignNumber}, Column{Align: AlignCenter, MinWidth: 6})
+	table.AddRow("a", "7", "x")
+	table.AddRow("b", "1,024", "yy")
+
+	lines := strings.Split(table.RenderSimple(), "\n")
+	want := []string{
+		"│ Name │ Count │  Mode  │",
+		"│ a    │     7 │   x    │",
+		"│ b    │ 1,024 │   yy   │",
+	}
+	for i, line := range []string{lines[1], lines[3], lines[4]} {
+		if line != want[i] {
+			t.Errorf("line %d = %q, want %q", i, line, want[i])
+		}
+	}
+}
+
+func TestIsNumber(t *testing.T) {
+	for cell, want := range map[string]bool{
+		"42":       true,
+		"-3.5":     true,
+		"1,024":    true,
+		"87%":      true,
+		"":         false,
+		"NaN":      false,
+		"Inf":      false,
+		"-inf":     false,
+		"infinity": false,
+		"1e5":      false,
+		"0x1p-2":   false,
+	} {
+		if got := isNumber(cell); got != want {
+			t.Errorf("isNumber(%q) = %v, want %v", cell, got, want)
+		}
+	}
+}
+
+func TestColumnMaxWidth(t *testing.T) {
+	table := New("Path", "Notes")
+	table.SetHeaderBold(false)
+	table.SetColumn(0, Column{MaxWidth: 8})
+	table.SetColumn(1, Column{MaxWidth: 10, Wrap: true})
+	table.AddRow("/usr/local/bin", "a tool that does many things")
+
+	lines := strings.Split(table.RenderSimple(), "\n")
+	want := []string{
+		"│ /usr/lo… │ a tool     │",
+		"│          │ that does  │",
+		"│          │ many       │",
+		"│          │ things     │",
+	}
+	if len(lines) != 3+len(want)+1 {
+		t.Fatalf("expected %d lines, got:\n%s", 3+len(want)+1, strings.Join(lines, "\n"))
+	}
+	for i := range want {
+		if lines[3+i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[3+i], want[i])
+		}
+	}
+}
+
+func TestColoredCellsLineUp(t *testing.T) {
+	table := New("Status", "Pod")
+	table.SetColumn(0, Column{MaxWidth: 5})
+	table.AddRow("\033[32mRunning\033[0m", "web-1")
+	table.AddRow("Failed", "web-2")
+
+	lines := strings.Split(table.Render(), "\n")
+	for _, line := range lines {
+		if got := textwidth.Width(line); got != textwidth.Width(lines[0]) {
+			t.Errorf("line %q is %d columns wide, want %d", line, got, textwidth.Width(lines[0]))
+		}
+	}
+	if !strings.Contains(lines[3], "\033[32mRunn\033[0m…") {
+		t.Errorf("truncated colored cell should keep its styles, got %q", lines[3])
+	}
+}
+
 func TestString(t *testing.T) {
 	table := New("Name")
 	table.AddRow("Alice")
@@ -2,7 +2,10 @@ package tui
 
 import (
 	"strings"
+	"unicode"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,21 +18,43 @@ type TextInput struct {
 	focused    bool
 	placeholder string
 	onSubmit   func(string) tea.Cmd
+	keys       *KeyMap
+}
+
+// DefaultTextInputKeyMap returns the default TextInput bindings for the
+// actions "submit" and "clear"
+func DefaultTextInputKeyMap() *KeyMap {
+	km := NewKeyMap("TextInput")
+	km.Bind("submit", key.NewBinding(key.WithKeys("ctrl+j", "alt+enter"), key.WithHelp("ctrl+j", "send")))
+	km.Bind("clear", key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "clear")))
+	return km
 }
 
 // NewTextInput creates a new text input component
 func NewTextInput() *TextInput {
 	ta := textarea.New()
-	ta.Placeholder = "Type your message... (Ctrl+J to send)"
 	ta.ShowLineNumbers = false
 	ta.CharLimit = 10000
 	ta.SetHeight(3)
 
-	return &TextInput{
-		textarea:    ta,
-		placeholder: "Type your message... (Ctrl+J to send)",
-		height:      5, // 3 lines + border
+	t := &TextInput{
+		textarea: ta,
+		height:   5, // 3 lines + border
+		keys:     DefaultTextInputKeyMap(),
 	}
+	t.updatePlaceholder()
+	return t
+}
+
+// KeyMap returns the input's bindings, which can be rebound
+func (t *TextInput) KeyMap() *KeyMap {
+	return t.keys
+}
+
+// ConsumesKey reports whether the input handles msg: typed text and its own
+// bindings
+func (t *TextInput) ConsumesKey(msg tea.KeyMsg) bool {
+	return t.focused && (typesText(msg) || t.keys.bound(msg))
 }
 
 // Init initializes the text input
@@ -52,7 +77,7 @@ func (t *TextInput) Update(msg tea.Msg) (Component, tea.Cmd) {
 		}
 
 		// Handle Ctrl+Enter to submit (Ctrl+J in terminal)
-		if msg.Type == tea.KeyCtrlJ || (msg.Type == tea.KeyEnter && msg.Alt) {
+		if t.keys.Matches(msg, "submit") {
 			content := strings.TrimSpace(t.textarea.Value())
 			if content != "" {
 				t.textarea.Reset()
@@ -64,7 +89,7 @@ func (t *TextInput) Update(msg tea.Msg) (Component, tea.Cmd) {
 		}
 
 		// Handle Ctrl+D to clear
-		if msg.Type == tea.KeyCtrlD {
+		if t.keys.Matches(msg, "clear") {
 			t.textarea.Reset()
 			return t, nil
 		}
@@ -91,14 +116,16 @@ func (t *TextInput) View() string {
 	b.WriteString(strings.Repeat("─", t.width-2))
 	b.WriteString("┐\033[0m\n")
 
-	// Textarea content
+	// Textarea content; the placeholder names the submit key, which may have
+	// been rebound
+	t.updatePlaceholder()
 	lines := strings.Split(t.textarea.View(), "\n")
 	for _, line := range lines {
 		b.WriteString("\033[2m│\033[0m ")
 		b.WriteString(line)
 
 		// Pad to width
-		visualLen := len(stripANSI(line))
+		visualLen := textwidth.Width(line)
 		if visualLen < t.width-4 {
 			b.WriteString(strings.Repeat(" ", t.width-4-visualLen))
 		}
@@ -109,9 +136,9 @@ func (t *TextInput) View() string {
 	// Bottom border with hint
 	b.WriteString("\033[2m└")
 	if t.focused {
-		hint := "Ctrl+J: send · Ctrl+D: clear"
-		hintLen := len(hint)
-		if hintLen < t.width-4 {
+		hint := t.hint()
+		hintLen := textwidth.Width(hint)
+		if hint != "" && hintLen < t.width-4 {
 			b.WriteString(" \033[3m")
 			b.WriteString(hint)
 			b.WriteString("\033[0m\033[2m ")
@@ -127,6 +154,43 @@ func (t *TextInput) View() string {
 	return b.String()
 }
 
+// updatePlaceholder names the submit key in the placeholder, such as
+// "Type your message... (Ctrl+J to send)"
+func (t *TextInput) updatePlaceholder() {
+	t.placeholder = "Type your message..."
+	if b := t.keys.Binding("submit"); b.Enabled() && b.Help().Key != "" {
+		t.placeholder += " (" + keyLabel(b.Help().Key) + " to send)"
+	}
+	t.textarea.Placeholder = t.placeholder
+}
+
+// hint returns the bottom border hint built from the help of the submit and
+// clear bindings, such as "Ctrl+J: send · Ctrl+D: clear"
+func (t *TextInput) hint() string {
+	var parts []string
+	for _, action := range []string{"submit", "clear"} {
+		if b := t.keys.Binding(action); b.Enabled() && b.Help().Key != "" {
+			parts = append(parts, keyLabel(b.Help().Key)+": "+b.Help().Desc)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// keyLabel capitalizes each key name in a binding's help, so "ctrl+j" shows
+// as "Ctrl+J" and "ctrl+s/alt+enter" as "Ctrl+S/Alt+Enter"
+func keyLabel(keys string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range keys {
+		if upper {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		upper = r == '+' || r == '/'
+	}
+	return b.String()
+}
+
 // Focus is called when this component receives focus
 func (t *TextInput) Focus() {
 	t.focused = true
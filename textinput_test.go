@@ -125,6 +125,23 @@ func TestTextInputViewFocused(t *testing.T) {
 	}
 }
 
+func TestTextInputHintsFollowKeyMap(t *testing.T) {
+	ti := NewTextInput()
+	ti.KeyMap().Rebind("submit", "ctrl+s")
+	ti.KeyMap().Rebind("clear")
+	ti.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if view := ti.View(); !strings.Contains(view, "(Ctrl+S to send)") || strings.Contains(view, "Ctrl+J") {
+		t.Errorf("Placeholder should name the rebound submit key:\n%s", view)
+	}
+
+	ti.Focus()
+	view := ti.View()
+	if !strings.Contains(view, "Ctrl+S: send") || strings.Contains(view, "clear") {
+		t.Errorf("Hint should list only the bound keys:\n%s", view)
+	}
+}
+
 func TestTextInputViewBlurred(t *testing.T) {
 	ti := NewTextInput()
 	ti.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
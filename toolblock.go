@@ -6,6 +6,8 @@ import (
 	"time"
 
 	design "github.com/SCKelemen/design-system"
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -37,6 +39,10 @@ type ToolBlock struct {
 	successColor string
 	errorColor   string
 	warningColor string
+	keys         *KeyMap
+	clock        *FrameClock // Shared frame clock; nil runs a timer of its own
+	lastFrame    time.Time
+	hits         HitMap // Header position from the last render
 }
 
 // ToolBlockOption configures a ToolBlock
@@ -85,6 +91,21 @@ func WithToolBlockTheme(theme string) ToolBlockOption {
 	}
 }
 
+// WithToolBlockKeyMap replaces the default keymap
+func WithToolBlockKeyMap(km *KeyMap) ToolBlockOption {
+	return func(tb *ToolBlock) {
+		tb.keys = km
+	}
+}
+
+// DefaultToolBlockKeyMap returns the default ToolBlock bindings for the
+// action "toggle"
+func DefaultToolBlockKeyMap() *KeyMap {
+	km := NewKeyMap("ToolBlock")
+	km.Bind("toggle", key.NewBinding(key.WithKeys("ctrl+o", "enter"), key.WithHelp("ctrl+o", "expand")))
+	return km
+}
+
 // NewToolBlock creates a new tool block
 func NewToolBlock(toolName, command string, output []string, opts ...ToolBlockOption) *ToolBlock {
 	tb := &ToolBlock{
@@ -99,6 +120,7 @@ func NewToolBlock(toolName, command string, output []string, opts ...ToolBlockOp
 		successColor: "\033[32m",
 		errorColor:   "\033[31m",
 		warningColor: "\033[33m",
+		keys:         DefaultToolBlockKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -121,6 +143,11 @@ type toolBlockTickMsg struct {
 	id *ToolBlock
 }
 
+// Target delivers the tick to the block that scheduled it
+func (m toolBlockTickMsg) Target() Component {
+	return m.id
+}
+
 // Update handles messages
 func (tb *ToolBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -133,12 +160,22 @@ func (tb *ToolBlock) Update(msg tea.Msg) (Component, tea.Cmd) {
 			return tb, tb.tick()
 		}
 
+	case FrameMsg:
+		if tb.Animating() && frameDue(&tb.lastFrame, msg.Time, toolBlockFrameInterval) {
+			tb.spinner = (tb.spinner + 1) % len(spinnerFrames)
+		}
+
 	case tea.KeyMsg:
-		if tb.focused {
-			switch msg.String() {
-			case "ctrl+o", "enter":
-				tb.ToggleExpanded()
-			}
+		if tb.focused && tb.keys.Matches(msg, "toggle") {
+			tb.ToggleExpanded()
+		}
+
+	case tea.MouseMsg:
+		if !isLeftClick(msg) {
+			return tb, nil
+		}
+		if zone, ok := tb.hits.Test(msg.X, msg.Y); ok && zone.ID == "header" {
+			tb.ToggleExpanded()
 		}
 	}
 	return tb, nil
@@ -164,7 +201,7 @@ func (tb *ToolBlock) View() string {
 			statusColor,
 			tb.icon,
 			tb.toolName,
-			truncateString(tb.command, tb.width-len(tb.toolName)-20),
+			textwidth.TruncateWith(tb.command, max(tb.width-textwidth.Width(tb.toolName)-20, 0), "..."),
 			statusColor,
 			spinner)
 	} else {
@@ -172,7 +209,7 @@ func (tb *ToolBlock) View() string {
 			statusColor,
 			tb.icon,
 			tb.toolName,
-			truncateString(tb.command, tb.width-len(tb.toolName)-15),
+			textwidth.TruncateWith(tb.command, max(tb.width-textwidth.Width(tb.toolName)-15, 0), "..."),
 			statusIcon)
 	}
 
@@ -181,6 +218,8 @@ func (tb *ToolBlock) View() string {
 	}
 
 	lines = append(lines, header)
+	tb.hits.Reset()
+	tb.hits.Add("header", 0, Bounds{X: 0, Y: 0, Width: tb.width, Height: 1})
 
 	// Output with tree connector
 	if len(tb.output) == 0 {
@@ -213,19 +252,16 @@ func (tb *ToolBlock) View() string {
 		}
 
 		// Truncate long lines
-		displayLine := line
-		maxWidth := tb.width - len(stripANSI(prefix)) - 2
-		if len(displayLine) > maxWidth {
-			displayLine = displayLine[:maxWidth-3] + "..."
-		}
+		maxWidth := tb.width - textwidth.Width(prefix) - 2
+		displayLine := textwidth.TruncateWith(line, max(maxWidth, 0), "...")
 
 		lines = append(lines, prefix+displayLine)
 	}
 
 	// Show "... +N lines" if collapsed
 	if hiddenCount > 0 {
-		expandHint := fmt.Sprintf("     \033[2m… +%d lines \033[0m\033[3m(ctrl+o to expand)\033[0m",
-			hiddenCount)
+		expandHint := fmt.Sprintf("     \033[2m… +%d lines \033[0m\033[3m(%s to expand)\033[0m",
+			hiddenCount, tb.keys.hint("toggle"))
 		lines = append(lines, expandHint)
 	}
 
@@ -247,6 +283,11 @@ func (tb *ToolBlock) Focused() bool {
 	return tb.focused
 }
 
+// KeyMap returns the block's bindings, which can be rebound
+func (tb *ToolBlock) KeyMap() *KeyMap {
+	return tb.keys
+}
+
 // ToggleExpanded toggles the expanded state
 func (tb *ToolBlock) ToggleExpanded() {
 	tb.expanded = !tb.expanded
@@ -276,17 +317,6 @@ func getToolIcon(toolName string) string {
 	return "⏺" // Default icon
 }
 
-// truncateString truncates a string to a maximum length
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	if maxLen <= 3 {
-		return s[:maxLen]
-	}
-	return s[:maxLen-3] + "..."
-}
-
 // getStatusIndicator returns the icon and color for the current status
 func (tb *ToolBlock) getStatusIndicator() (string, string) {
 	switch tb.status {
@@ -303,9 +333,26 @@ func (tb *ToolBlock) getStatusIndicator() (string, string) {
 	}
 }
 
-// tick returns a command that sends a tick message for spinner animation
+// SetFrameClock animates the block on a shared clock instead of its own timer
+func (tb *ToolBlock) SetFrameClock(clock *FrameClock) {
+	tb.clock = clock
+}
+
+// Animating reports whether the block's spinner is running
+func (tb *ToolBlock) Animating() bool {
+	return tb.streaming && tb.status == StatusRunning
+}
+
+// toolBlockFrameInterval is how often the spinner steps
+const toolBlockFrameInterval = 100 * time.Millisecond
+
+// tick wakes the frame clock, or without one returns a command that sends a
+// tick message for spinner animation
 func (tb *ToolBlock) tick() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+	if tb.clock != nil {
+		return tb.clock.Wake()
+	}
+	return tea.Tick(toolBlockFrameInterval, func(t time.Time) tea.Msg {
 		return toolBlockTickMsg{id: tb}
 	})
 }
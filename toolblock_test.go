@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SCKelemen/tui/internal/textwidth"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -437,7 +438,7 @@ func TestToolBlockLongLinesTruncation(t *testing.T) {
 	// View should not contain the full 200-character line
 	lines := strings.Split(view, "\n")
 	for _, line := range lines {
-		strippedLine := stripANSI(line)
+		strippedLine := textwidth.Strip(line)
 		if len(strippedLine) > 100 {
 			t.Errorf("Line should be truncated, got length %d", len(strippedLine))
 		}
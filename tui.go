@@ -13,6 +13,7 @@
 //   - Status bars with keybinding hints
 //   - Modal dialogs for confirmations and details
 //   - Full keyboard navigation with vim-style bindings
+//   - Mouse support: click to select or activate, wheel to scroll
 //   - Responsive layouts that adapt to terminal size
 //   - Theme support via design tokens
 //
@@ -23,6 +24,8 @@
 //
 // Components can be composed together to build complex UIs. The Application type
 // provides a container for managing multiple components with automatic focus cycling.
+// Given a layout tree via SetLayout, it sizes each component to its own region and
+// composites their views into a single screen-sized frame.
 //
 // Example usage:
 //
@@ -47,9 +50,8 @@
 package tui
 
 import (
-	"fmt"
-	"strings"
-
+	"github.com/SCKelemen/layout"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -58,7 +60,20 @@ type Application struct {
 	width      int
 	height     int
 	components []Component
-	focused    int // Index of currently focused component
+	ids        []string     // Stable ID of each component
+	nextID     int          // Counter for generated IDs
+	focused    int          // Index of currently focused component
+	scopes     []focusScope // Focus traps, innermost last
+	bounds     []Bounds     // Where each component was drawn by the last View
+	keys       *KeyRegistry
+	clock      *FrameClock // Drives the animations of Animated components
+	quitModal  *Modal      // Asks before quitting; nil quits straight away
+	quitPrompt string
+
+	// Optional layout tree; its leaf nodes are the regions components render into
+	root    *layout.Node
+	regions []Bounds
+	pending []tea.Cmd // From resizes outside Update; returned by the next Init or Update
 }
 
 // Component is the interface all TUI components must implement
@@ -82,23 +97,146 @@ type Component interface {
 	Focused() bool
 }
 
-// NewApplication creates a new TUI application
-func NewApplication() *Application {
-	return &Application{
+// KeyConsumer is implemented by components that decide which keys they take.
+// The focused component gets each key before the application's global
+// bindings; a key it consumes never quits or moves focus. Components that
+// don't implement KeyConsumer consume the keys their KeyMap binds.
+type KeyConsumer interface {
+	// ConsumesKey reports whether the component handles msg itself
+	ConsumesKey(msg tea.KeyMsg) bool
+}
+
+// keyRegistryUser is implemented by components that list an app's bindings,
+// like StatusBar and CommandPalette
+type keyRegistryUser interface {
+	SetKeyRegistry(registry *KeyRegistry)
+}
+
+// ApplicationOption configures an Application
+type ApplicationOption func(*Application)
+
+// WithGlobalKeyMap replaces the global bindings. The application acts on
+// the actions "quit", "focus-next" and "focus-prev"; see DefaultGlobalKeyMap.
+func WithGlobalKeyMap(km *KeyMap) ApplicationOption {
+	return func(a *Application) {
+		a.keys = NewKeyRegistry(km)
+	}
+}
+
+// WithFPS sets the frame rate animations run at. The default is DefaultFPS.
+func WithFPS(fps int) ApplicationOption {
+	return func(a *Application) {
+		a.clock.SetFPS(fps)
+	}
+}
+
+// WithQuitConfirmation asks before quitting, showing message in a Yes/No
+// modal. Pressing a quit key again while it is open quits.
+func WithQuitConfirmation(message string) ApplicationOption {
+	return func(a *Application) {
+		a.quitModal = NewModal(WithModalType(ModalConfirm))
+		a.quitPrompt = message
+	}
+}
+
+// NewApplication creates a new TUI application. Keys go to the focused
+// component first, so typing "q" into a TextInput doesn't quit; the global
+// bindings can be changed through Keys().Global() or WithGlobalKeyMap.
+func NewApplication(opts ...ApplicationOption) *Application {
+	a := &Application{
 		components: make([]Component, 0),
 		focused:    -1,
+		keys:       NewKeyRegistry(DefaultGlobalKeyMap()),
+		clock:      NewFrameClock(DefaultFPS),
+	}
+
+	for _, opt := range opts {
+		opt(a)
 	}
+
+	return a
 }
 
-// AddComponent adds a component to the application
-func (a *Application) AddComponent(c Component) {
-	a.components = append(a.components, c)
-	if a.focused == -1 && len(a.components) > 0 {
-		a.focused = 0
-		a.components[0].Focus()
+// DefaultGlobalKeyMap returns the default Application bindings for the
+// actions "quit", "focus-next" and "focus-prev"
+func DefaultGlobalKeyMap() *KeyMap {
+	km := NewKeyMap("Global")
+	km.Bind("quit", key.NewBinding(key.WithKeys("ctrl+c", "q"), key.WithHelp("q", "quit")))
+	km.Bind("focus-next", key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "focus")))
+	km.Bind("focus-prev", key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "focus back")))
+	return km
+}
+
+// Keys returns the application's key registry. Its global keymap holds the
+// app-wide bindings, and the keymaps of added components are registered with
+// it, so conflicts can be checked with Keys().Conflicts().
+func (a *Application) Keys() *KeyRegistry {
+	return a.keys
+}
+
+// Clock returns the frame clock shared by the application's animations
+func (a *Application) Clock() *FrameClock {
+	return a.clock
+}
+
+// AddComponent adds a component to the application, registering its keymap,
+// handing the key registry to components that list bindings, and the frame
+// clock to Animated components. It returns the component's generated ID; use
+// AddComponentWithID to choose one.
+func (a *Application) AddComponent(c Component) string {
+	id := a.generateID()
+	a.insert(len(a.components), id, c)
+	return id
+}
+
+// syncActiveKeys marks the focused component's keymap as active
+func (a *Application) syncActiveKeys() {
+	a.keys.SetActive(nil)
+	if a.focused < 0 || a.focused >= len(a.components) {
+		return
+	}
+	if km, ok := a.components[a.focused].(KeyMapper); ok {
+		a.keys.SetActive(km.KeyMap())
 	}
 }
 
+// SetLayout arranges components with a layout tree instead of stacking their
+// views. The leaf nodes of root, in depth-first order, are the regions: the
+// first component added renders into the first leaf, the second into the
+// second, and so on. Components without a matching leaf are not drawn.
+//
+// Each component receives a tea.WindowSizeMsg with the size of its own region,
+// and View returns a frame exactly as large as the terminal. Lengths in ch
+// units resolve to terminal cells, so trees from LayoutHelpers work directly:
+//
+//	app.SetLayout(tui.LayoutHelpers.HeaderContentFooterLayout(3, 1))
+//	app.AddComponent(header)
+//	app.AddComponent(transcript)
+//	app.AddComponent(statusBar)
+//
+// Regions are computed straight away once the screen size is known, and
+// otherwise on the first tea.WindowSizeMsg. Pass nil to return to stacked
+// rendering, which gives every component the whole screen again. SetLayout
+// returns the commands the components return for their new sizes.
+func (a *Application) SetLayout(root *layout.Node) tea.Cmd {
+	a.root = root
+	a.regions = nil
+	if a.width == 0 {
+		return nil
+	}
+	if root != nil {
+		return a.resizeRegions()
+	}
+
+	var cmds []tea.Cmd
+	for i, c := range a.components {
+		var cmd tea.Cmd
+		a.components[i], cmd = c.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
 // FocusComponent focuses a specific component by index, blurring the currently focused one
 func (a *Application) FocusComponent(index int) {
 	if index < 0 || index >= len(a.components) {
@@ -113,6 +251,7 @@ func (a *Application) FocusComponent(index int) {
 	// Focus new
 	a.focused = index
 	a.components[index].Focus()
+	a.syncActiveKeys()
 }
 
 // Init initializes the application
@@ -121,123 +260,291 @@ func (a *Application) Init() tea.Cmd {
 	for _, c := range a.components {
 		cmds = append(cmds, c.Init())
 	}
-	return tea.Batch(cmds...)
+	return tea.Batch(append(cmds, a.takePending())...)
 }
 
 // Update handles messages
 func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := a.update(msg)
+	// Overlays shown or hidden while handling msg push or pop focus scopes
+	a.syncOverlays()
+	return a, tea.Batch(cmd, a.takePending())
+}
+
+// update handles a message and returns the command to run
+func (a *Application) update(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return a, tea.Quit
-		case "tab":
-			// Cycle focus forward
-			return a, a.focusNext()
-		case "shift+tab":
-			// Cycle focus backward
-			return a, a.focusPrev()
-		}
+		return a.handleKey(msg)
 
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
+		if a.quitModal != nil {
+			a.quitModal.Update(msg)
+		}
+		if a.root != nil {
+			return a.resizeRegions()
+		}
 		// Window size messages should go to all components
 		for i, c := range a.components {
 			var cmd tea.Cmd
 			a.components[i], cmd = c.Update(msg)
 			cmds = append(cmds, cmd)
 		}
-		return a, tea.Batch(cmds...)
-	}
+		return tea.Batch(cmds...)
 
-	// Check if this is a tick message (these need to go to all components for animations)
-	// We do this by checking the message type name
-	if isTickMessage(msg) {
-		// Broadcast tick messages to all components for animations
-		for i, c := range a.components {
-			var cmd tea.Cmd
-			a.components[i], cmd = c.Update(msg)
-			cmds = append(cmds, cmd)
+	case FrameMsg:
+		// Keep the clock running only while something animates
+		cmd := a.broadcast(a.clock.advance(msg))
+		return tea.Batch(cmd, a.clock.schedule(a.animating()))
+
+	case tea.MouseMsg:
+		if a.confirmingQuit() {
+			return a.updateQuitModal(msg)
 		}
-		return a, tea.Batch(cmds...)
+		return a.routeMouse(msg)
+	}
+
+	// Broadcasts, like animation ticks, go to all components; targeted
+	// messages go to their component whether or not it is focused
+	switch m := msg.(type) {
+	case BroadcastMsg:
+		return a.broadcast(unwrapMsg(msg))
+	case TargetedMsg:
+		return a.sendTo(m.Target(), msg)
 	}
 
 	// Pass other messages only to focused component
 	if a.focused >= 0 && a.focused < len(a.components) {
 		var cmd tea.Cmd
 		a.components[a.focused], cmd = a.components[a.focused].Update(msg)
-		return a, cmd
+		return cmd
 	}
 
-	return a, nil
+	return nil
 }
 
-// isTickMessage checks if a message is a tick-related message that should be broadcast
-func isTickMessage(msg tea.Msg) bool {
-	// Check for specific tick message types
-	switch msg.(type) {
-	case activityBarTickMsg:
-		return true
-	case toolBlockTickMsg:
-		return true
-	default:
-		// Check the type name for any message containing "tick" or "Tick"
-		typeName := fmt.Sprintf("%T", msg)
-		return strings.Contains(typeName, "tick") || strings.Contains(typeName, "Tick")
+// handleKey gives a key to the focused component if it consumes it, and
+// otherwise runs the global binding it triggers. Keys that neither consume
+// nor trigger still reach the focused component.
+func (a *Application) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if a.confirmingQuit() {
+		if a.keys.Global().Matches(msg, "quit") {
+			return tea.Quit
+		}
+		return a.updateQuitModal(msg)
+	}
+
+	if a.focused >= 0 && a.focused < len(a.components) && consumesKey(a.components[a.focused], msg) {
+		var cmd tea.Cmd
+		a.components[a.focused], cmd = a.components[a.focused].Update(msg)
+		return cmd
+	}
+
+	action, _ := a.keys.Global().Lookup(msg)
+	switch action {
+	case "quit":
+		return a.quit()
+	case "focus-next":
+		// Cycle focus forward
+		return a.focusNext()
+	case "focus-prev":
+		// Cycle focus backward
+		return a.focusPrev()
 	}
+
+	if a.focused >= 0 && a.focused < len(a.components) {
+		var cmd tea.Cmd
+		a.components[a.focused], cmd = a.components[a.focused].Update(msg)
+		return cmd
+	}
+	return nil
 }
 
-// View renders the application
-func (a *Application) View() string {
-	if len(a.components) == 0 {
-		return "No components"
+// consumesKey reports whether c handles msg itself instead of leaving it to
+// the global bindings
+func consumesKey(c Component, msg tea.KeyMsg) bool {
+	if kc, ok := c.(KeyConsumer); ok {
+		return kc.ConsumesKey(msg)
+	}
+	if km, ok := c.(KeyMapper); ok {
+		return km.KeyMap().bound(msg)
 	}
+	return false
+}
 
-	var view string
-	for _, c := range a.components {
-		view += c.View()
+// quit quits, or asks first when quit confirmation is enabled
+func (a *Application) quit() tea.Cmd {
+	if a.quitModal == nil {
+		return tea.Quit
 	}
-	return view
+	a.quitModal.ShowConfirm("Quit", a.quitPrompt, func() tea.Cmd {
+		return tea.Quit
+	}, nil)
+	a.quitModal.Focus()
+	a.keys.SetActive(a.quitModal.KeyMap())
+	return nil
 }
 
-// focusNext moves focus to the next component
-func (a *Application) focusNext() tea.Cmd {
-	if len(a.components) == 0 {
-		return nil
+// confirmingQuit reports whether the quit confirmation is showing
+func (a *Application) confirmingQuit() bool {
+	return a.quitModal != nil && a.quitModal.IsVisible()
+}
+
+// updateQuitModal passes msg to the quit confirmation, restoring the focused
+// component's bindings once it closes
+func (a *Application) updateQuitModal(msg tea.Msg) tea.Cmd {
+	_, cmd := a.quitModal.Update(msg)
+	if !a.quitModal.IsVisible() {
+		a.quitModal.Blur()
+		a.syncActiveKeys()
 	}
+	return cmd
+}
 
-	// Blur current
-	if a.focused >= 0 {
-		a.components[a.focused].Blur()
+// resizeRegions lays out the tree for the current screen size and sends each
+// component the size of its region
+func (a *Application) resizeRegions() tea.Cmd {
+	a.regions = layoutRegions(a.root, a.width, a.height)
+
+	var cmds []tea.Cmd
+	for i, c := range a.components {
+		if i >= len(a.regions) {
+			break
+		}
+		var cmd tea.Cmd
+		region := a.regions[i]
+		a.components[i], cmd = c.Update(tea.WindowSizeMsg{Width: region.Width, Height: region.Height})
+		cmds = append(cmds, cmd)
 	}
+	return tea.Batch(cmds...)
+}
 
-	// Focus next
-	a.focused = (a.focused + 1) % len(a.components)
-	a.components[a.focused].Focus()
+// takePending returns the commands kept by relayout and forgets them
+func (a *Application) takePending() tea.Cmd {
+	cmd := tea.Batch(a.pending...)
+	a.pending = nil
+	return cmd
+}
 
-	return nil
+// animating reports whether any component needs frames
+func (a *Application) animating() bool {
+	for _, c := range a.components {
+		if anim, ok := c.(Animated); ok && anim.Animating() {
+			return true
+		}
+	}
+	return false
 }
 
-// focusPrev moves focus to the previous component
-func (a *Application) focusPrev() tea.Cmd {
+// broadcast delivers msg to every component
+func (a *Application) broadcast(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for i, c := range a.components {
+		var cmd tea.Cmd
+		a.components[i], cmd = c.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// sendTo delivers msg to target, or to every component when target is not
+// one of them. Broadcasts keep the target so containers can find it.
+func (a *Application) sendTo(target Component, msg tea.Msg) tea.Cmd {
+	for i, c := range a.components {
+		if sameComponent(c, target) {
+			var cmd tea.Cmd
+			a.components[i], cmd = c.Update(unwrapMsg(msg))
+			return cmd
+		}
+	}
+	return a.broadcast(msg)
+}
+
+// View renders the application, with the quit confirmation on top while it
+// is showing
+func (a *Application) View() string {
+	view := a.view()
+	if a.confirmingQuit() {
+		return overlayView(view, a.quitModal.View())
+	}
+	return view
+}
+
+// view renders the components
+func (a *Application) view() string {
 	if len(a.components) == 0 {
-		return nil
+		return "No components"
 	}
 
-	// Blur current
-	if a.focused >= 0 {
-		a.components[a.focused].Blur()
+	if a.root != nil {
+		return a.viewRegions()
 	}
 
-	// Focus previous
-	a.focused--
-	if a.focused < 0 {
-		a.focused = len(a.components) - 1
+	var view string
+	a.bounds = a.bounds[:0]
+	y := 0
+	for _, c := range a.components {
+		componentView := c.View()
+		height := viewHeight(componentView)
+		a.bounds = append(a.bounds, Bounds{X: 0, Y: y, Width: a.width, Height: height})
+		y += height
+		view += componentView
 	}
-	a.components[a.focused].Focus()
+	return view
+}
 
+// viewRegions composites each component into its layout region
+func (a *Application) viewRegions() string {
+	a.bounds = a.bounds[:0]
+	var panes []framePane
+	for i, c := range a.components {
+		if i >= len(a.regions) {
+			break
+		}
+		region := a.regions[i]
+		a.bounds = append(a.bounds, region)
+		panes = append(panes, framePane{bounds: region, view: c.View()})
+	}
+	return composeFrame(a.width, a.height, panes)
+}
+
+// routeMouse delivers a mouse event to the component drawn under the cursor,
+// translated into that component's coordinates. A left click also moves focus
+// to the component. Components outside the current focus scope get nothing.
+// Mouse reporting must be enabled on the program, e.g. with
+// tea.WithMouseCellMotion().
+func (a *Application) routeMouse(msg tea.MouseMsg) tea.Cmd {
+	for i, b := range a.bounds {
+		if i >= len(a.components) || !b.Contains(msg.X, msg.Y) {
+			continue
+		}
+		if !a.inScope(i) {
+			return nil // Trapped by a focus scope
+		}
+
+		if isLeftClick(msg) && i != a.focused {
+			a.FocusComponent(i)
+		}
+
+		var cmd tea.Cmd
+		a.components[i], cmd = a.components[i].Update(b.Translate(msg))
+		return cmd
+	}
+	return nil
+}
+
+// focusNext moves focus to the next component in the current focus scope
+func (a *Application) focusNext() tea.Cmd {
+	a.cycleFocus(1)
+	return nil
+}
+
+// focusPrev moves focus to the previous component in the current focus scope
+func (a *Application) focusPrev() tea.Cmd {
+	a.cycleFocus(-1)
 	return nil
 }
@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -118,3 +120,355 @@ func TestStatusBarView(t *testing.T) {
 		t.Error("Expected non-empty view after setting width")
 	}
 }
+
+// sizeRecorder is a minimal component that remembers the last size it was given
+type sizeRecorder struct {
+	width, height int
+	focused       bool
+	view          string
+}
+
+func (s *sizeRecorder) Init() tea.Cmd { return nil }
+
+func (s *sizeRecorder) Update(msg tea.Msg) (Component, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		s.width = size.Width
+		s.height = size.Height
+	}
+	return s, nil
+}
+
+func (s *sizeRecorder) View() string  { return s.view }
+func (s *sizeRecorder) Focus()        { s.focused = true }
+func (s *sizeRecorder) Blur()         { s.focused = false }
+func (s *sizeRecorder) Focused() bool { return s.focused }
+
+func TestApplicationLayoutSizesRegions(t *testing.T) {
+	app := NewApplication()
+	app.SetLayout(LayoutHelpers.HeaderContentFooterLayout(3, 1))
+
+	header := &sizeRecorder{view: "header"}
+	content := &sizeRecorder{view: "content"}
+	footer := &sizeRecorder{view: "footer"}
+	app.AddComponent(header)
+	app.AddComponent(content)
+	app.AddComponent(footer)
+
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if header.width != 80 || header.height != 3 {
+		t.Errorf("Header size = %dx%d, want 80x3", header.width, header.height)
+	}
+	if content.width != 80 || content.height != 20 {
+		t.Errorf("Content size = %dx%d, want 80x20", content.width, content.height)
+	}
+	if footer.width != 80 || footer.height != 1 {
+		t.Errorf("Footer size = %dx%d, want 80x1", footer.width, footer.height)
+	}
+}
+
+func TestApplicationSetLayoutAfterResize(t *testing.T) {
+	app := NewApplication()
+	header := &sizeRecorder{view: "header"}
+	content := &sizeRecorder{view: "content"}
+	app.AddComponent(header)
+	app.AddComponent(content)
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	app.SetLayout(LayoutHelpers.HeaderContentFooterLayout(3, 1))
+	if header.width != 80 || header.height != 3 {
+		t.Errorf("Header size = %dx%d, want 80x3 without waiting for a resize", header.width, header.height)
+	}
+	if len(strings.Split(app.View(), "\n")) != 24 {
+		t.Error("View should use the layout straight away")
+	}
+
+	app.SetLayout(nil)
+	if content.width != 80 || content.height != 24 {
+		t.Errorf("Content size = %dx%d, want the whole screen back", content.width, content.height)
+	}
+}
+
+// resizeFetcher starts a fetch whenever it is resized
+type resizeFetcher struct{ sizeRecorder }
+
+type fetchMsg struct{ width int }
+
+func (r *resizeFetcher) Update(msg tea.Msg) (Component, tea.Cmd) {
+	r.sizeRecorder.Update(msg)
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		return r, func() tea.Msg { return fetchMsg{size.Width} }
+	}
+	return r, nil
+}
+
+func TestApplicationLayoutKeepsResizeCommands(t *testing.T) {
+	app := NewApplication()
+	app.AddComponent(&resizeFetcher{})
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	cmd := app.SetLayout(LayoutHelpers.SidebarLayout(10))
+	if cmd == nil || cmd() != (fetchMsg{10}) {
+		t.Error("SetLayout should return the command the component started when resized")
+	}
+
+	// Adding a component moves the regions; their commands come with the next Update
+	app.InsertBefore(app.IDs()[0], &sizeRecorder{})
+	_, cmd = app.Update(struct{}{})
+	if cmd == nil || cmd() != (fetchMsg{70}) {
+		t.Error("Update should return commands from resizes made by InsertBefore")
+	}
+	if _, cmd := app.Update(struct{}{}); cmd != nil {
+		t.Error("Commands from a resize should be returned only once")
+	}
+}
+
+func TestApplicationLayoutViewIsScreenSized(t *testing.T) {
+	app := NewApplication()
+	app.SetLayout(LayoutHelpers.SidebarLayout(10))
+
+	sidebar := &sizeRecorder{view: "files\nmore"}
+	main := &sizeRecorder{view: "transcript"}
+	app.AddComponent(sidebar)
+	app.AddComponent(main)
+
+	app.Update(tea.WindowSizeMsg{Width: 30, Height: 5})
+	view := app.View()
+
+	lines := strings.Split(view, "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 rows, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if len([]rune(line)) != 30 {
+			t.Errorf("Row %d is %d columns wide, want 30", i, len([]rune(line)))
+		}
+	}
+	if !strings.HasPrefix(lines[0], "files     transcript") {
+		t.Errorf("Row 0 = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "more      ") {
+		t.Errorf("Row 1 = %q", lines[1])
+	}
+}
+
+func TestApplicationLayoutRoutesMouseByRegion(t *testing.T) {
+	app := NewApplication()
+	app.SetLayout(LayoutHelpers.SidebarLayout(10))
+
+	sidebar := &sizeRecorder{view: "files"}
+	main := &sizeRecorder{view: "transcript"}
+	app.AddComponent(sidebar)
+	app.AddComponent(main)
+
+	app.Update(tea.WindowSizeMsg{Width: 30, Height: 5})
+	app.View()
+
+	app.Update(tea.MouseMsg{X: 15, Y: 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+
+	if !main.Focused() || sidebar.Focused() {
+		t.Error("Clicking inside the main region should focus the main component")
+	}
+}
+
+// isQuit reports whether cmd quits the program
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestFocusedComponentGetsKeysFirst(t *testing.T) {
+	app := NewApplication()
+	input := NewTextInput()
+	app.AddComponent(input)
+	app.AddComponent(NewStatusBar())
+
+	_, cmd := app.Update(runeKey("q"))
+	if isQuit(cmd) {
+		t.Error("Typing q into a focused TextInput should not quit")
+	}
+	if input.Value() != "q" {
+		t.Errorf("TextInput value = %q, want q", input.Value())
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if input.Focused() {
+		t.Error("Keys the input does not consume should still move focus")
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !isQuit(cmd) {
+		t.Error("ctrl+c should quit")
+	}
+}
+
+func TestConfirmationBlockKeepsTabUntilConfirmed(t *testing.T) {
+	app := NewApplication()
+	cb := NewConfirmationBlock()
+	app.AddComponent(cb)
+	app.AddComponent(NewStatusBar())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !cb.Focused() || cb.selectedIndex != 1 {
+		t.Errorf("Tab should move between options, focused=%v selected=%d", cb.Focused(), cb.selectedIndex)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if cb.Focused() {
+		t.Error("Tab should move focus once the block is confirmed")
+	}
+}
+
+func TestConfigurableGlobalKeys(t *testing.T) {
+	app := NewApplication()
+	app.Keys().Global().Rebind("quit", "ctrl+q")
+
+	if _, cmd := app.Update(runeKey("q")); isQuit(cmd) {
+		t.Error("q should not quit after rebinding")
+	}
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlQ}); !isQuit(cmd) {
+		t.Error("ctrl+q should quit after rebinding")
+	}
+
+	km := NewKeyMap("Global")
+	km.Bind("focus-next", key.NewBinding(key.WithKeys("ctrl+n")))
+	app = NewApplication(WithGlobalKeyMap(km))
+	first, second := NewStatusBar(), NewStatusBar()
+	app.AddComponent(first)
+	app.AddComponent(second)
+
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); isQuit(cmd) {
+		t.Error("A global keymap without quit should not quit")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if !second.Focused() {
+		t.Error("ctrl+n should move focus with the custom keymap")
+	}
+}
+
+func TestQuitConfirmation(t *testing.T) {
+	app := NewApplication(WithQuitConfirmation("Really quit?"))
+	app.AddComponent(NewStatusBar())
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	_, cmd := app.Update(runeKey("q"))
+	if isQuit(cmd) {
+		t.Fatal("Quit should ask for confirmation first")
+	}
+	if !strings.Contains(app.View(), "Really quit?") {
+		t.Error("View should show the confirmation")
+	}
+
+	// Choose No
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter}); isQuit(cmd) {
+		t.Error("Choosing No should not quit")
+	}
+	if strings.Contains(app.View(), "Really quit?") {
+		t.Error("The confirmation should close after choosing No")
+	}
+
+	app.Update(runeKey("q"))
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter}); !isQuit(cmd) {
+		t.Error("Choosing Yes should quit")
+	}
+
+	app.Update(runeKey("q"))
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("Pressing quit again while confirming should quit")
+	}
+}
+
+// msgRecorder records the messages it receives
+type msgRecorder struct {
+	sizeRecorder
+	msgs []tea.Msg
+}
+
+func (r *msgRecorder) Update(msg tea.Msg) (Component, tea.Cmd) {
+	r.msgs = append(r.msgs, msg)
+	return r, nil
+}
+
+type stickyNoteMsg struct{}
+
+type pingMsg struct{}
+
+func TestApplicationMessageRouting(t *testing.T) {
+	app := NewApplication()
+	first, second := &msgRecorder{}, &msgRecorder{}
+	app.AddComponent(first)
+	app.AddComponent(second)
+
+	app.Update(stickyNoteMsg{})
+	if len(first.msgs) != 1 || len(second.msgs) != 0 {
+		t.Errorf("Plain messages should only reach the focused component, got %d and %d", len(first.msgs), len(second.msgs))
+	}
+
+	app.Update(Broadcast(pingMsg{}))
+	if len(second.msgs) != 1 || len(first.msgs) != 2 {
+		t.Fatalf("Broadcasts should reach every component, got %d and %d", len(first.msgs), len(second.msgs))
+	}
+	if _, ok := second.msgs[0].(pingMsg); !ok {
+		t.Errorf("Broadcast should deliver the wrapped message, got %T", second.msgs[0])
+	}
+
+	app.Update(SendTo(second, pingMsg{}))
+	if len(second.msgs) != 2 || len(first.msgs) != 2 {
+		t.Errorf("Targeted messages should only reach their component, got %d and %d", len(first.msgs), len(second.msgs))
+	}
+
+	// A target the application doesn't hold directly is reached through a
+	// broadcast
+	app.Update(SendTo(&msgRecorder{}, pingMsg{}))
+	if len(first.msgs) != 3 || len(second.msgs) != 3 {
+		t.Errorf("Messages for unknown targets should be broadcast, got %d and %d", len(first.msgs), len(second.msgs))
+	}
+}
+
+// valueComponent is a component held by value whose type can't be compared
+type valueComponent struct {
+	lines []string
+}
+
+func (v valueComponent) Init() tea.Cmd                       { return nil }
+func (v valueComponent) Update(tea.Msg) (Component, tea.Cmd) { return v, nil }
+func (v valueComponent) View() string                        { return strings.Join(v.lines, "\n") }
+func (v valueComponent) Focus()                              {}
+func (v valueComponent) Blur()                               {}
+func (v valueComponent) Focused() bool                       { return false }
+
+func TestApplicationTargetsPastValueComponents(t *testing.T) {
+	app := NewApplication()
+	app.AddComponent(valueComponent{lines: []string{"a"}})
+	target := &msgRecorder{}
+	app.AddComponent(target)
+
+	app.Update(SendTo(target, pingMsg{}))
+	if len(target.msgs) != 1 {
+		t.Errorf("Targeted message should reach its component, got %d", len(target.msgs))
+	}
+	app.Update(SendTo(valueComponent{}, pingMsg{}))
+	if len(target.msgs) != 2 {
+		t.Errorf("A target that can't be compared should be broadcast, got %d", len(target.msgs))
+	}
+}
+
+func TestApplicationRoutesComponentTicks(t *testing.T) {
+	app := NewApplication()
+	status := NewStatusBar()
+	block := NewToolBlock("Bash", "ls", nil, WithStreaming())
+	block.SetStatus(StatusRunning)
+	app.AddComponent(status)
+	app.AddComponent(block)
+
+	before := block.spinner
+	app.Update(toolBlockTickMsg{id: block})
+	if block.spinner == before {
+		t.Error("A tool block tick should reach its block even when it isn't focused")
+	}
+}
@@ -0,0 +1,547 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/SCKelemen/tui/internal/textwidth"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Viewport is a scrollable container for a column of child components, such
+// as a transcript of ToolBlocks, CodeBlocks and DiffBlocks that is taller than
+// the terminal. Rendering is virtualized: only children that intersect the
+// visible rows, and those not yet measured, are rendered, and only their
+// visible rows are kept. Heights of children off screen are remembered from
+// the last time they were drawn, so a resize, which forgets them all, renders
+// every child once.
+//
+// PgUp/PgDn, Home/End and the arrow keys scroll; j/k move focus between
+// children, scrolling each into view. Keys the focused child handles itself
+// go to the child first. While scrolled to the bottom the viewport follows
+// new output, so streaming blocks stay in view; scrolling up stops following
+// until the bottom is reached again.
+//
+// Example usage:
+//
+//	transcript := tui.NewViewport()
+//	transcript.Add(tui.NewToolBlock("Bash", "go test ./...", nil, tui.WithStreaming()))
+//	app.AddComponent(transcript)
+type Viewport struct {
+	width    int
+	height   int
+	focused  bool
+	children []Component
+	heights  []int // Rendered height of each child; -1 until measured
+	offset   int   // First visible row of the content
+	follow   bool  // Keep the bottom in view as content grows
+	sticky   bool  // Whether reaching the bottom turns following back on
+	active   int   // Index of the focused child, -1 for none
+	first    int   // Children drawn by the last View, first to last
+	last     int
+	tops     []int // Content row of each child in the last View
+	keys     *KeyMap
+	clock    *FrameClock
+}
+
+// ViewportOption configures a Viewport
+type ViewportOption func(*Viewport)
+
+// WithViewportChildren sets the initial children
+func WithViewportChildren(children ...Component) ViewportOption {
+	return func(vp *Viewport) {
+		for _, c := range children {
+			vp.Add(c)
+		}
+	}
+}
+
+// WithStickToBottom sets whether the viewport follows new content while
+// scrolled to the bottom. It is on by default.
+func WithStickToBottom(stick bool) ViewportOption {
+	return func(vp *Viewport) {
+		vp.sticky = stick
+		vp.follow = stick
+	}
+}
+
+// WithViewportKeyMap replaces the default keymap
+func WithViewportKeyMap(km *KeyMap) ViewportOption {
+	return func(vp *Viewport) {
+		vp.keys = km
+	}
+}
+
+// DefaultViewportKeyMap returns the default Viewport bindings for the actions
+// "up", "down", "page-up", "page-down", "top", "bottom", "next" and "prev"
+func DefaultViewportKeyMap() *KeyMap {
+	km := NewKeyMap("Viewport")
+	km.Bind("up", key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "scroll up")))
+	km.Bind("down", key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "scroll down")))
+	km.Bind("page-up", key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")))
+	km.Bind("page-down", key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "page down")))
+	km.Bind("top", key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("home", "top")))
+	km.Bind("bottom", key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("end", "bottom")))
+	km.Bind("next", key.NewBinding(key.WithKeys("j"), key.WithHelp("j", "next block")))
+	km.Bind("prev", key.NewBinding(key.WithKeys("k"), key.WithHelp("k", "previous block")))
+	return km
+}
+
+// NewViewport creates an empty viewport that sticks to the bottom
+func NewViewport(opts ...ViewportOption) *Viewport {
+	vp := &Viewport{
+		follow: true,
+		sticky: true,
+		active: -1,
+		first:  -1,
+		last:   -1,
+		keys:   DefaultViewportKeyMap(),
+	}
+
+	for _, opt := range opts {
+		opt(vp)
+	}
+
+	return vp
+}
+
+// Add appends a child below the others. It returns the command the child
+// returns when sized to the viewport.
+func (vp *Viewport) Add(c Component) tea.Cmd {
+	return vp.Insert(len(vp.children), c)
+}
+
+// Insert adds a child at index i, clamped to the valid range. It returns the
+// command the child returns when sized to the viewport.
+func (vp *Viewport) Insert(i int, c Component) tea.Cmd {
+	i = max(0, min(i, len(vp.children)))
+	vp.children = append(vp.children[:i], append([]Component{c}, vp.children[i:]...)...)
+	vp.heights = append(vp.heights[:i], append([]int{-1}, vp.heights[i:]...)...)
+	if vp.active >= i {
+		vp.active++
+	}
+	// Keep the children drawn last time, so they are measured again
+	switch {
+	case i <= vp.first:
+		vp.first++
+		vp.last++
+	case i <= vp.last:
+		vp.last++
+	}
+
+	if anim, ok := c.(Animated); ok && vp.clock != nil {
+		anim.SetFrameClock(vp.clock)
+	}
+	if vp.width == 0 {
+		return nil
+	}
+	return vp.updateChild(i, tea.WindowSizeMsg{Width: vp.width, Height: vp.height})
+}
+
+// Remove removes a child, reporting whether it was found
+func (vp *Viewport) Remove(c Component) bool {
+	for i, child := range vp.children {
+		if !sameComponent(child, c) {
+			continue
+		}
+		vp.children = append(vp.children[:i], vp.children[i+1:]...)
+		vp.heights = append(vp.heights[:i], vp.heights[i+1:]...)
+		switch {
+		case vp.active == i:
+			c.Blur()
+			vp.active = -1
+		case vp.active > i:
+			vp.active--
+		}
+		switch {
+		case i < vp.first:
+			vp.first--
+			vp.last--
+		case i <= vp.last:
+			vp.last--
+		}
+		if vp.last < vp.first {
+			vp.first, vp.last = -1, -1
+		}
+		return true
+	}
+	return false
+}
+
+// Clear removes every child
+func (vp *Viewport) Clear() {
+	if vp.active >= 0 {
+		vp.children[vp.active].Blur()
+	}
+	vp.children, vp.heights = nil, nil
+	vp.active, vp.first, vp.last = -1, -1, -1
+	vp.offset = 0
+	vp.follow = vp.sticky
+}
+
+// Children returns the children in display order
+func (vp *Viewport) Children() []Component {
+	return append([]Component(nil), vp.children...)
+}
+
+// Invalidate forgets the measured heights of all children. Call it after
+// changing children that are off screen in ways that change their height.
+func (vp *Viewport) Invalidate() {
+	for i := range vp.heights {
+		vp.heights[i] = -1
+	}
+}
+
+// Init initializes the children
+func (vp *Viewport) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, c := range vp.children {
+		cmds = append(cmds, c.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update handles messages. Size changes, broadcasts and frames go to every
+// child; targeted messages to their child; anything else to the focused child.
+func (vp *Viewport) Update(msg tea.Msg) (Component, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		vp.width = msg.Width
+		vp.height = msg.Height
+		vp.Invalidate()
+		return vp, vp.updateAll(msg)
+
+	case tea.KeyMsg:
+		if !vp.focused {
+			return vp, nil
+		}
+		return vp, vp.handleKey(msg)
+
+	case tea.MouseMsg:
+		return vp, vp.handleMouse(msg)
+
+	case BroadcastMsg:
+		return vp, vp.updateAll(unwrapMsg(msg))
+
+	case TargetedMsg:
+		for i, c := range vp.children {
+			if sameComponent(c, msg.Target()) {
+				return vp, vp.updateChild(i, unwrapMsg(msg))
+			}
+		}
+		// The target may be nested further down
+		return vp, vp.updateAll(unwrapMsg(msg))
+	}
+
+	if vp.active >= 0 {
+		return vp, vp.updateChild(vp.active, msg)
+	}
+	return vp, nil
+}
+
+// handleKey gives keys the focused child consumes to the child, and
+// otherwise scrolls or moves focus between children
+func (vp *Viewport) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if vp.active >= 0 && consumesKey(vp.children[vp.active], msg) {
+		return vp.updateChild(vp.active, msg)
+	}
+
+	action, _ := vp.keys.Lookup(msg)
+	switch action {
+	case "up":
+		vp.ScrollBy(-1)
+	case "down":
+		vp.ScrollBy(1)
+	case "page-up":
+		vp.ScrollBy(-max(1, vp.height-1))
+	case "page-down":
+		vp.ScrollBy(max(1, vp.height-1))
+	case "top":
+		vp.GotoTop()
+	case "bottom":
+		vp.GotoBottom()
+	case "next":
+		vp.focusStep(1)
+	case "prev":
+		vp.focusStep(-1)
+	default:
+		if vp.active >= 0 {
+			return vp.updateChild(vp.active, msg)
+		}
+	}
+	return nil
+}
+
+// handleMouse scrolls with the wheel and hands other events to the child
+// under the cursor; a left click also focuses it
+func (vp *Viewport) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		vp.ScrollBy(-3)
+		return nil
+	case tea.MouseButtonWheelDown:
+		vp.ScrollBy(3)
+		return nil
+	}
+
+	for i := vp.first; i >= 0 && i <= vp.last && i < len(vp.tops); i++ {
+		b := Bounds{Y: vp.tops[i] - vp.offset, Width: vp.width, Height: vp.heights[i]}
+		if !b.Contains(msg.X, msg.Y) {
+			continue
+		}
+		if isLeftClick(msg) {
+			vp.FocusChild(i)
+		}
+		return vp.updateChild(i, b.Translate(msg))
+	}
+	return nil
+}
+
+// updateChild passes msg to the child at index i
+func (vp *Viewport) updateChild(i int, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	vp.children[i], cmd = vp.children[i].Update(msg)
+	return cmd
+}
+
+// updateAll passes msg to every child
+func (vp *Viewport) updateAll(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range vp.children {
+		cmds = append(cmds, vp.updateChild(i, msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// View renders the visible rows, exactly height lines of width cells
+func (vp *Viewport) View() string {
+	if vp.width == 0 || vp.height == 0 {
+		return ""
+	}
+
+	// Render children whose height is unknown, those drawn last time and,
+	// while following, the last child, since they are the ones likely to be
+	// growing
+	views := make(map[int]string)
+	render := func(i int) string {
+		if v, ok := views[i]; ok {
+			return v
+		}
+		v := vp.children[i].View()
+		views[i] = v
+		vp.heights[i] = viewHeight(v)
+		return v
+	}
+	for i := range vp.children {
+		if vp.heights[i] < 0 || (i >= vp.first && i <= vp.last) {
+			render(i)
+		}
+	}
+	if vp.follow && len(vp.children) > 0 {
+		render(len(vp.children) - 1)
+	}
+
+	total := 0
+	vp.tops = vp.tops[:0]
+	for _, h := range vp.heights {
+		vp.tops = append(vp.tops, total)
+		total += h
+	}
+	maxOffset := max(0, total-vp.height)
+	if vp.follow {
+		vp.offset = maxOffset
+	}
+	vp.offset = max(0, min(vp.offset, maxOffset))
+	if vp.offset == maxOffset {
+		vp.follow = vp.sticky
+	}
+
+	lines := make([]string, 0, vp.height)
+	vp.first, vp.last = -1, -1
+	for i := range vp.children {
+		top := vp.tops[i]
+		if top+vp.heights[i] <= vp.offset || vp.heights[i] == 0 {
+			continue
+		}
+		if top >= vp.offset+vp.height {
+			break
+		}
+		if vp.first < 0 {
+			vp.first = i
+		}
+		vp.last = i
+
+		rows := strings.Split(strings.TrimSuffix(render(i), "\n"), "\n")
+		for r := max(0, vp.offset-top); r < len(rows) && len(lines) < vp.height; r++ {
+			lines = append(lines, rows[r])
+		}
+	}
+
+	var b strings.Builder
+	for r := 0; r < vp.height; r++ {
+		line := ""
+		if r < len(lines) {
+			line = lines[r]
+		}
+		b.WriteString(textwidth.Fit(line, vp.width))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ScrollBy scrolls down n rows, or up when n is negative. Scrolling to the
+// bottom resumes following new content.
+func (vp *Viewport) ScrollBy(n int) {
+	vp.offset = max(0, min(vp.offset+n, vp.maxOffset()))
+	vp.follow = vp.sticky && vp.offset >= vp.maxOffset()
+}
+
+// GotoTop scrolls to the first row
+func (vp *Viewport) GotoTop() {
+	vp.offset = 0
+	vp.follow = false
+}
+
+// GotoBottom scrolls to the last row and resumes following new content
+func (vp *Viewport) GotoBottom() {
+	vp.offset = vp.maxOffset()
+	vp.follow = vp.sticky
+}
+
+// Offset returns the first visible row of the content
+func (vp *Viewport) Offset() int {
+	return vp.offset
+}
+
+// AtBottom reports whether the last row is in view
+func (vp *Viewport) AtBottom() bool {
+	return vp.offset >= vp.maxOffset()
+}
+
+// Following reports whether the viewport keeps new content in view
+func (vp *Viewport) Following() bool {
+	return vp.follow
+}
+
+// maxOffset returns the largest offset that still fills the viewport, from
+// the heights known so far
+func (vp *Viewport) maxOffset() int {
+	total := 0
+	for _, h := range vp.heights {
+		total += max(0, h)
+	}
+	return max(0, total-vp.height)
+}
+
+// Active returns the focused child, or nil if none is
+func (vp *Viewport) Active() Component {
+	if vp.active < 0 {
+		return nil
+	}
+	return vp.children[vp.active]
+}
+
+// FocusChild focuses the child at index i and scrolls it into view
+func (vp *Viewport) FocusChild(i int) {
+	if i < 0 || i >= len(vp.children) {
+		return
+	}
+	if vp.active >= 0 && vp.active != i {
+		vp.children[vp.active].Blur()
+	}
+	vp.active = i
+	if vp.focused {
+		vp.children[i].Focus()
+	}
+	vp.scrollIntoView(i)
+}
+
+// focusStep moves focus step children down, or up when negative. With no
+// child focused it starts from the children on screen.
+func (vp *Viewport) focusStep(step int) {
+	if len(vp.children) == 0 {
+		return
+	}
+	switch {
+	case vp.active >= 0:
+		vp.FocusChild(max(0, min(vp.active+step, len(vp.children)-1)))
+	case step > 0:
+		vp.FocusChild(max(0, vp.first))
+	case vp.last >= 0:
+		vp.FocusChild(vp.last)
+	default:
+		vp.FocusChild(len(vp.children) - 1)
+	}
+}
+
+// scrollIntoView scrolls so the child at index i is visible, showing its top
+// if it is taller than the viewport
+func (vp *Viewport) scrollIntoView(i int) {
+	top := 0
+	for j := 0; j < i; j++ {
+		top += max(0, vp.heights[j])
+	}
+	bottom := top + max(0, vp.heights[i])
+
+	switch {
+	case top < vp.offset:
+		vp.offset = top
+	case bottom > vp.offset+vp.height:
+		vp.offset = min(top, bottom-vp.height)
+	}
+	vp.follow = vp.sticky && vp.offset >= vp.maxOffset()
+}
+
+// Focus focuses the viewport and its focused child
+func (vp *Viewport) Focus() {
+	vp.focused = true
+	if vp.active >= 0 {
+		vp.children[vp.active].Focus()
+	}
+}
+
+// Blur blurs the viewport and its focused child
+func (vp *Viewport) Blur() {
+	vp.focused = false
+	if vp.active >= 0 {
+		vp.children[vp.active].Blur()
+	}
+}
+
+// Focused returns whether the viewport is focused
+func (vp *Viewport) Focused() bool {
+	return vp.focused
+}
+
+// KeyMap returns the viewport's bindings, which can be rebound
+func (vp *Viewport) KeyMap() *KeyMap {
+	return vp.keys
+}
+
+// ConsumesKey reports whether the viewport or its focused child handles msg
+func (vp *Viewport) ConsumesKey(msg tea.KeyMsg) bool {
+	if vp.active >= 0 && consumesKey(vp.children[vp.active], msg) {
+		return true
+	}
+	return vp.keys.bound(msg)
+}
+
+// SetFrameClock hands the frame clock to animated children
+func (vp *Viewport) SetFrameClock(clock *FrameClock) {
+	vp.clock = clock
+	for _, c := range vp.children {
+		if anim, ok := c.(Animated); ok {
+			anim.SetFrameClock(clock)
+		}
+	}
+}
+
+// Animating reports whether any child is animating
+func (vp *Viewport) Animating() bool {
+	for _, c := range vp.children {
+		if anim, ok := c.(Animated); ok && anim.Animating() {
+			return true
+		}
+	}
+	return false
+}
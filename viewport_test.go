@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewCounter renders a fixed number of numbered lines and counts its renders
+type viewCounter struct {
+	sizeRecorder
+	name  string
+	lines int
+	views int
+}
+
+func (v *viewCounter) Update(msg tea.Msg) (Component, tea.Cmd) {
+	v.sizeRecorder.Update(msg)
+	return v, nil
+}
+
+func (v *viewCounter) View() string {
+	v.views++
+	var b strings.Builder
+	for i := 0; i < v.lines; i++ {
+		fmt.Fprintf(&b, "%s%d\n", v.name, i)
+	}
+	return b.String()
+}
+
+func newTranscript(n, lines int) (*Viewport, []*viewCounter) {
+	vp := NewViewport()
+	var blocks []*viewCounter
+	for i := 0; i < n; i++ {
+		b := &viewCounter{name: fmt.Sprintf("b%d:", i), lines: lines}
+		blocks = append(blocks, b)
+		vp.Add(b)
+	}
+	vp.Update(tea.WindowSizeMsg{Width: 20, Height: 6})
+	vp.Focus()
+	return vp, blocks
+}
+
+func TestViewportRendersOnlyVisibleChildren(t *testing.T) {
+	vp, blocks := newTranscript(100, 4)
+	vp.GotoTop()
+	vp.View()
+
+	for _, b := range blocks {
+		b.views = 0
+	}
+	view := vp.View()
+	if lines := strings.Split(strings.TrimSuffix(view, "\n"), "\n"); len(lines) != 6 {
+		t.Fatalf("View should fill the height, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(view, "b0:0") || !strings.Contains(view, "b1:1") {
+		t.Errorf("Top of the transcript should be visible, got %q", view)
+	}
+	if blocks[0].views != 1 || blocks[1].views != 1 {
+		t.Error("Visible children should render once per frame")
+	}
+	for i, b := range blocks[2:] {
+		if b.views != 0 {
+			t.Fatalf("Child %d is off screen and should not render", i+2)
+		}
+	}
+}
+
+func TestViewportSticksToBottom(t *testing.T) {
+	vp, _ := newTranscript(10, 4)
+	if !strings.Contains(vp.View(), "b9:3") || !vp.Following() {
+		t.Fatal("A new viewport should show the end of the transcript")
+	}
+
+	vp.Add(&viewCounter{name: "new:", lines: 2})
+	view := vp.View()
+	if !strings.Contains(view, "new:1") {
+		t.Errorf("New output should stay in view while following, got %q", view)
+	}
+
+	vp.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if vp.Following() || vp.AtBottom() {
+		t.Error("Scrolling up should stop following")
+	}
+	offset := vp.Offset()
+	vp.Add(&viewCounter{name: "more:", lines: 2})
+	vp.View()
+	if vp.Offset() != offset {
+		t.Errorf("New output should not move a scrolled viewport, offset %d want %d", vp.Offset(), offset)
+	}
+
+	vp.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if !strings.Contains(vp.View(), "more:1") || !vp.Following() {
+		t.Error("End should jump to the bottom and resume following")
+	}
+	vp.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if vp.Offset() != 0 {
+		t.Errorf("Home should scroll to the top, offset %d", vp.Offset())
+	}
+	vp.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if vp.Offset() != 5 {
+		t.Errorf("PgDn should scroll a page less one row, offset %d", vp.Offset())
+	}
+}
+
+func TestViewportRemeasuresAcrossInsert(t *testing.T) {
+	vp, blocks := newTranscript(10, 4)
+	vp.View()
+
+	// The last block grows while a block is inserted above the screen
+	blocks[9].lines = 6
+	vp.Insert(0, &viewCounter{name: "top:", lines: 2})
+	view := vp.View()
+	if !strings.Contains(view, "b9:5") {
+		t.Errorf("A growing child on screen should be measured again after Insert, got %q", view)
+	}
+
+	// The last child is measured while following even if it was not drawn
+	vp.Add(&viewCounter{name: "tail:", lines: 1})
+	vp.first, vp.last = -1, -1
+	vp.View()
+	tail := vp.children[len(vp.children)-1].(*viewCounter)
+	tail.lines = 3
+	vp.first, vp.last = -1, -1
+	if view := vp.View(); !strings.Contains(view, "tail:2") {
+		t.Errorf("The last child should be measured again while following, got %q", view)
+	}
+
+	vp.Remove(blocks[0])
+	if vp.first < 0 || vp.children[vp.last] != tail {
+		t.Errorf("Remove should keep the drawn range, got %d to %d", vp.first, vp.last)
+	}
+}
+
+// sizedValue is a value-receiver component that records its width and
+// reports each resize with a command
+type sizedValue struct{ width int }
+
+type resizedMsg struct{ width int }
+
+func (v sizedValue) Init() tea.Cmd { return nil }
+func (v sizedValue) Update(msg tea.Msg) (Component, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		v.width = size.Width
+		return v, func() tea.Msg { return resizedMsg{size.Width} }
+	}
+	return v, nil
+}
+func (v sizedValue) View() string  { return "sized\n" }
+func (v sizedValue) Focus()        {}
+func (v sizedValue) Blur()         {}
+func (v sizedValue) Focused() bool { return false }
+
+func TestViewportInsertKeepsResizedChild(t *testing.T) {
+	vp, _ := newTranscript(2, 1)
+	cmd := vp.Add(sizedValue{})
+
+	if got := vp.Children()[2].(sizedValue).width; got != 20 {
+		t.Errorf("The resized child should be stored, got width %d", got)
+	}
+	if cmd == nil || cmd() != (resizedMsg{20}) {
+		t.Error("Add should return the command the child returned when resized")
+	}
+	if NewViewport().Add(sizedValue{}) != nil {
+		t.Error("A viewport without a size should not resize its children")
+	}
+}
+
+func TestViewportMovesFocusBetweenChildren(t *testing.T) {
+	vp, blocks := newTranscript(5, 4)
+	vp.GotoTop()
+	vp.View()
+
+	vp.Update(runeKey("j"))
+	if vp.Active() != blocks[0] || !blocks[0].Focused() {
+		t.Fatal("j should focus the first visible child")
+	}
+	vp.Update(runeKey("j"))
+	vp.Update(runeKey("j"))
+	if vp.Active() != blocks[2] || blocks[0].Focused() {
+		t.Error("j should move focus down, blurring the previous child")
+	}
+	if !strings.Contains(vp.View(), "b2:3") {
+		t.Error("The focused child should be scrolled into view")
+	}
+
+	vp.Update(runeKey("k"))
+	if vp.Active() != blocks[1] {
+		t.Error("k should move focus up")
+	}
+
+	vp.Update(stickyNoteMsg{})
+	vp.Blur()
+	if blocks[1].Focused() {
+		t.Error("Blurring the viewport should blur the focused child")
+	}
+}
+
+func TestViewportGivesKeysToFocusedChild(t *testing.T) {
+	input := NewTextInput()
+	vp := NewViewport(WithViewportChildren(NewStatusBar(), input))
+	vp.Update(tea.WindowSizeMsg{Width: 40, Height: 10})
+	vp.Focus()
+	vp.FocusChild(1)
+
+	vp.Update(runeKey("j"))
+	if vp.Active() != input || input.Value() != "j" {
+		t.Errorf("A focused input should receive j, got %q", input.Value())
+	}
+	if !vp.ConsumesKey(runeKey("k")) {
+		t.Error("The viewport should consume keys its focused child takes")
+	}
+}